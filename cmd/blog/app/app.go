@@ -11,11 +11,22 @@ import (
 	"github.com/narcissus1949/narcissus-blog/cmd/blog/app/config"
 	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
 	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/feed"
+	"github.com/narcissus1949/narcissus-blog/internal/jwt"
 	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/loginguard"
+	"github.com/narcissus1949/narcissus-blog/internal/mail"
 	"github.com/narcissus1949/narcissus-blog/internal/middleware"
+	"github.com/narcissus1949/narcissus-blog/internal/moderation"
+	"github.com/narcissus1949/narcissus-blog/internal/oidc"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
+	"github.com/narcissus1949/narcissus-blog/internal/related"
+	"github.com/narcissus1949/narcissus-blog/internal/sitemap"
+	"github.com/narcissus1949/narcissus-blog/internal/trash"
 	"github.com/narcissus1949/narcissus-blog/internal/validator"
 	"github.com/narcissus1949/narcissus-blog/pkg/route"
 	"github.com/narcissus1949/narcissus-blog/pkg/server/processor"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
 	"go.uber.org/zap"
 )
 
@@ -45,10 +56,32 @@ func MustInit(ctx context.Context, configPath string) {
 	logger.MustInit(config.Config.Logger)
 	cache.MustInit(config.Config.Redis)
 	mysql.MustInit(config.Config.Mysql)
+	moderation.MustInit(config.Config.Moderation)
+	feed.MustInit(config.Config.Feed)
+	sitemap.MustInit(config.Config.Sitemap)
+	rbac.MustInit(config.Config.Rbac)
+	mail.MustInit(config.Config.Mail)
+	loginguard.MustInit(config.Config.LoginGuard)
+	jwt.MustInit(config.Config.Jwt)
+	oidc.MustInit(config.Config.Oidc)
+	trash.MustInit(config.Config.Trash)
+	related.MustInit(config.Config.Related)
 	validator.MustRegistValidator()
+	// 初始管理员
+	if err := service.UserRoleService.BootstrapAdmin(ctx); err != nil {
+		panic(fmt.Sprintf("bootstrap initial admin failed: %v", err))
+	}
 
 	// 更新文章浏览量
 	processor.RunPageViewProcessor(ctx)
+	// 定时发布文章
+	processor.RunScheduledPublishProcessor(ctx)
+	// 构建文章搜索索引
+	processor.RunSearchIndexProcessor(ctx)
+	// 清理回收站中过期的文章
+	processor.RunTrashPurgeProcessor(ctx)
+	// 计算相关文章推荐
+	processor.RunRelatedArticleProcessor(ctx)
 }
 
 func StartServer(ctx context.Context) error {
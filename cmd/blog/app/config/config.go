@@ -5,7 +5,17 @@ import (
 
 	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
 	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/feed"
+	"github.com/narcissus1949/narcissus-blog/internal/jwt"
 	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/loginguard"
+	"github.com/narcissus1949/narcissus-blog/internal/mail"
+	"github.com/narcissus1949/narcissus-blog/internal/moderation"
+	"github.com/narcissus1949/narcissus-blog/internal/oidc"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
+	"github.com/narcissus1949/narcissus-blog/internal/related"
+	"github.com/narcissus1949/narcissus-blog/internal/sitemap"
+	"github.com/narcissus1949/narcissus-blog/internal/trash"
 	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	"github.com/spf13/viper"
 )
@@ -20,12 +30,24 @@ type Conf struct {
 	Mysql  mysql.MysqlConfig `json:"mysql"`
 	Redis  cache.RedisConfig `json:"redis"`
 	Logger logger.LogConfig  `json:"logger"`
+
+	Moderation moderation.ModerationConfig `json:"moderation"`
+	Feed       feed.FeedConfig             `json:"feed"`
+	Sitemap    sitemap.SitemapConfig       `json:"sitemap"`
+	Rbac       rbac.RbacConfig             `json:"rbac"`
+	Mail       mail.MailConfig             `json:"mail"`
+	LoginGuard loginguard.LoginGuardConfig `json:"loginGuard"`
+	Jwt        jwt.JwtConfig               `json:"jwt"`
+	Oidc       oidc.OidcConfig             `json:"oidc"`
+	Trash      trash.TrashConfig           `json:"trash"`
+	Related    related.RelatedConfig       `json:"related"`
 }
 
 type AppConfig struct {
 	Name          string `json:"name"`
 	Port          int    `json:"port"`
 	Domain        string `json:"domain"`
+	Scheme        string `json:"scheme"`      // 站点访问协议，与domain组成站点地址
 	ImgDataDir    string `json:"imgDataDir"`  // 图片存储根路径，绝对路径
 	ImgProxyURL   string `json:"imgProxyURL"` // 图片代理URL
 	PrivateKeyDir string `json:"privateKeyDir"`
@@ -38,6 +60,7 @@ func NewDefaultAppCfg() AppConfig {
 		Name:          "blog",
 		Port:          8080,
 		Domain:        "localhost",
+		Scheme:        "http",
 		ImgDataDir:    filepath.Join(rootDir, "data", "img"),
 		ImgProxyURL:   "http://127.0.0.1:8082/img",
 		PrivateKeyDir: filepath.Join(rootDir, "data", "conf"),
@@ -45,12 +68,28 @@ func NewDefaultAppCfg() AppConfig {
 	}
 }
 
+// SiteURL 站点地址，如 https://example.com
+func (c AppConfig) SiteURL() string {
+	return c.Scheme + "://" + c.Domain
+}
+
 func NewConfig() Conf {
 	return Conf{
 		App:    NewDefaultAppCfg(),
 		Logger: logger.NewDefaultCfg(),
 		Mysql:  mysql.NewDefaultMysqlCfg(),
 		Redis:  cache.NewDefaultRedisCfg(),
+
+		Moderation: moderation.NewDefaultModerationCfg(),
+		Feed:       feed.NewDefaultFeedCfg(),
+		Sitemap:    sitemap.NewDefaultSitemapCfg(),
+		Rbac:       rbac.NewDefaultRbacCfg(),
+		Mail:       mail.NewDefaultMailCfg(),
+		LoginGuard: loginguard.NewDefaultLoginGuardCfg(),
+		Jwt:        jwt.NewDefaultJwtCfg(),
+		Oidc:       oidc.NewDefaultOidcCfg(),
+		Trash:      trash.NewDefaultTrashCfg(),
+		Related:    related.NewDefaultRelatedCfg(),
 	}
 }
 
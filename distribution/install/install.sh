@@ -331,9 +331,18 @@ prepare_backend() {
     if [ ! -d "$BACKEND_CONF_DIR" ]; then
         mkdir -p "$BACKEND_CONF_DIR"
         
+        # 检查OpenSSL是否安装
+        if ! check_install_openssl; then
+            echo "OpenSSL not found. Please install it first."
+            exit 1
+        fi
+
         # 复制原始模板文件
         cp "./backend/conf.yaml" "$BACKEND_CONF_DIR/conf.yaml.template"
-        
+
+        # 生成token签名密钥
+        JWT_SECRET=$(openssl rand -hex 32)
+
         # 替换配置文件中的变量
         sed -e "s|{{BACKEND_PORT}}|$BACKEND_PORT|g" \
             -e "s|{{DOMAIN}}|$DOMAIN|g" \
@@ -342,6 +351,7 @@ prepare_backend() {
             -e "s|{{MYSQL_HOST}}|$MYSQL_HOST|g" \
             -e "s|{{REDIS_HOST}}|$REDIS_HOST|g" \
             -e "s|{{REDIS_PASSWORD}}|$REDIS_PASSWORD|g" \
+            -e "s|{{JWT_SECRET}}|$JWT_SECRET|g" \
             "$BACKEND_CONF_DIR/conf.yaml.template" > "$BACKEND_CONF_DIR/conf.yaml"
         
         echo "Backend configuration file generated with the following settings:"
@@ -350,11 +360,6 @@ prepare_backend() {
         echo "  MySQL Host: $MYSQL_HOST"
         echo "  Redis Host: $REDIS_HOST"
         
-        # 检查OpenSSL是否安装
-        if ! check_install_openssl; then
-            echo "OpenSSL not found. Please install it first."
-            exit 1
-        fi
         # 生成RSA密钥对
         if ! generate_rsa_keys; then
             echo "Error: Failed to generate RSA keys. Please check the logs above."
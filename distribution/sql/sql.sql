@@ -9,6 +9,8 @@ CREATE TABLE users (
     email VARCHAR(100) COMMENT '邮箱',
     phone_number VARCHAR(20) COMMENT '手机号',
     avatar_path VARCHAR(255) COMMENT '头像路径',
+    status TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '0:待审核;1:正常;2:已拒绝;3:已禁用',
+    password_reset_required TINYINT(1) UNSIGNED NOT NULL DEFAULT 0 COMMENT '是否需要重置密码，管理员强制重置后置为1，修改密码后置为0',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     PRIMARY KEY(id),
@@ -18,18 +20,97 @@ CREATE TABLE users (
 
 CREATE TABLE roles (
     id INT AUTO_INCREMENT COMMENT '角色ID',
-    role TINYINT(1) UNSIGNED NOT NULL DEFAULT 0 COMMENT '0:普通用户;1:系统管理员',
+    role TINYINT(1) UNSIGNED NOT NULL DEFAULT 0 COMMENT '0:普通用户;1:系统管理员;2:编辑;3:作者',
     PRIMARY KEY(id),
     UNIQUE(role)
 ) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
 
+CREATE TABLE user_roles (
+    id INT AUTO_INCREMENT COMMENT 'ID',
+    user_id INT NOT NULL COMMENT '用户ID',
+    role_id INT NOT NULL COMMENT '角色ID',
+    created_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '授予时间',
+    PRIMARY KEY(id),
+    UNIQUE KEY(user_id, role_id),
+    KEY(role_id)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE user_totps (
+    id INT AUTO_INCREMENT COMMENT 'ID',
+    user_id INT NOT NULL COMMENT '用户ID',
+    secret VARCHAR(64) NOT NULL COMMENT 'base32编码的TOTP密钥',
+    enabled TINYINT(1) UNSIGNED NOT NULL DEFAULT 0 COMMENT '是否已启用，0表示待验证，1表示已启用',
+    created_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY(id),
+    UNIQUE KEY(user_id)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE user_recovery_codes (
+    id INT AUTO_INCREMENT COMMENT 'ID',
+    user_id INT NOT NULL COMMENT '用户ID',
+    code_hash CHAR(64) NOT NULL COMMENT '恢复码sha256',
+    used_time DATETIME COMMENT '使用时间，为空表示未使用',
+    created_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY(id),
+    UNIQUE KEY(user_id, code_hash)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE user_identities (
+    id INT AUTO_INCREMENT COMMENT 'ID',
+    user_id INT NOT NULL COMMENT '用户ID',
+    provider VARCHAR(50) NOT NULL COMMENT '身份提供方标识',
+    subject VARCHAR(255) NOT NULL COMMENT '提供方中的用户唯一标识(sub)',
+    email VARCHAR(100) COMMENT '提供方返回的邮箱',
+    created_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '关联时间',
+    last_login_time DATETIME COMMENT '最近一次通过该身份登录的时间',
+    PRIMARY KEY(id),
+    UNIQUE KEY(provider, subject),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE user_access_tokens (
+    id INT AUTO_INCREMENT COMMENT 'ID',
+    user_id INT NOT NULL COMMENT '用户ID',
+    name VARCHAR(50) NOT NULL COMMENT '名称',
+    token_hash CHAR(64) NOT NULL COMMENT 'token的sha256',
+    token_prefix VARCHAR(20) NOT NULL COMMENT 'token前几位，用于识别',
+    scopes VARCHAR(255) NOT NULL COMMENT '权限范围，逗号分隔',
+    expire_time DATETIME COMMENT '过期时间，为空表示永不过期',
+    last_used_time DATETIME COMMENT '最近使用时间',
+    last_used_ip VARCHAR(64) COMMENT '最近使用IP',
+    created_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY(id),
+    UNIQUE KEY(token_hash),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE audit_logs (
+    id BIGINT AUTO_INCREMENT COMMENT 'ID',
+    actor_id INT NOT NULL DEFAULT 0 COMMENT '操作人ID',
+    action VARCHAR(30) NOT NULL COMMENT '操作类型，如create、update、delete',
+    entity_type VARCHAR(30) NOT NULL COMMENT '实体类型，如article、user',
+    entity_id BIGINT NOT NULL COMMENT '实体ID',
+    before_data TEXT COMMENT '修改前的JSON快照，新建时为空',
+    after_data TEXT COMMENT '修改后的JSON快照，删除时为空',
+    request_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '请求ID',
+    ip VARCHAR(64) NOT NULL DEFAULT '' COMMENT '操作人IP',
+    created_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '操作时间',
+    PRIMARY KEY(id),
+    INDEX idx_entity (entity_type, entity_id),
+    INDEX idx_actor_id (actor_id),
+    INDEX idx_created_time (created_time)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
 CREATE TABLE `articles` (
     `id` INT AUTO_INCREMENT COMMENT '文章ID',
     `title` VARCHAR(255) NOT NULL COMMENT '文章标题',
+    `slug` VARCHAR(100) NOT NULL COMMENT '文章URL别名，全局唯一',
     `summary` VARCHAR(500) NOT NULL COMMENT '摘要',
     `type` TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '文章类型，0表示博客文章，1表示随笔，2表示关于',
     `category_id` INT COMMENT '文章分类ID，每篇文章最多1个分类，可以为空',
     `author` VARCHAR(50) NOT NULL COMMENT '作者姓名或标识',
+    `owner_id` INT NOT NULL DEFAULT 0 COMMENT '所属用户ID，作者只能修改自己的文章，0表示无所属用户',
     `allow_comment` TINYINT(1) UNSIGNED NOT NULL DEFAULT 0 COMMENT '是否允许评论，0表示不允许评论，1表示允许评论',
     `views` INT NOT NULL DEFAULT 0 COMMENT '文章浏览量',
     `weight` INT NOT NULL DEFAULT 0 COMMENT '文章权重，默认初始值为0',
@@ -37,11 +118,28 @@ CREATE TABLE `articles` (
     `is_original` TINYINT(1) UNSIGNED NOT NULL DEFAULT 1 COMMENT '原创/转载标识。0表示非原创，1表示原创。默认初始值为1，表示原创',
     `original_article_link` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '转载文章的原始文章链接',
     `status` TINYINT(1) UNSIGNED NOT NULL DEFAULT 1 COMMENT '状态，0表示offline，1表示online',
+    `publish_at` DATETIME COMMENT '定时发布时间，为空表示没有待执行的定时发布',
+    `version` INT NOT NULL DEFAULT 0 COMMENT '乐观锁版本号，每次更新加1',
     `created_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `deleted_time` DATETIME COMMENT '移入回收站的时间，为空表示未删除',
     PRIMARY KEY (`id`),
+    UNIQUE KEY (`slug`),
     KEY (`category_id`),
-    KEY (`type`)
+    KEY (`type`),
+    KEY (`owner_id`),
+    KEY (`publish_at`),
+    KEY (`deleted_time`)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `article_slug_histories` (
+    `id` INT AUTO_INCREMENT COMMENT 'ID',
+    `article_id` INT NOT NULL COMMENT '文章ID',
+    `slug` VARCHAR(100) NOT NULL COMMENT '文章曾经使用的URL别名',
+    `created_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '别名被替换的时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY (`slug`),
+    KEY (`article_id`)
 ) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
 
 CREATE TABLE `article_content` (
@@ -52,6 +150,38 @@ CREATE TABLE `article_content` (
     UNIQUE KEY(article_id)
 ) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
 
+CREATE TABLE `article_revisions` (
+    `id` INT AUTO_INCREMENT COMMENT '文章版本ID',
+    `article_id` INT NOT NULL COMMENT '文章ID',
+    `revision` INT NOT NULL COMMENT '版本号，每篇文章从1开始递增',
+    `title` VARCHAR(255) NOT NULL COMMENT '文章标题',
+    `summary` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '摘要',
+    `content` MEDIUMTEXT NOT NULL COMMENT '文章内容',
+    `category_id` INT COMMENT '文章分类ID',
+    `category_name` VARCHAR(20) NOT NULL DEFAULT '' COMMENT '文章分类名称',
+    `tag_name_list` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '文章标签名称，用,隔开',
+    `editor_id` INT NOT NULL DEFAULT 0 COMMENT '编辑者用户ID',
+    `created_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY (`article_id`, `revision`)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `article_drafts` (
+    `id` INT AUTO_INCREMENT COMMENT '草稿ID',
+    `article_id` INT NOT NULL DEFAULT 0 COMMENT '文章ID，新建文章的草稿为0',
+    `user_id` INT NOT NULL COMMENT '草稿所属用户ID',
+    `title` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '文章标题',
+    `summary` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '摘要',
+    `content` MEDIUMTEXT NOT NULL COMMENT '文章内容',
+    `category` VARCHAR(20) NOT NULL DEFAULT '' COMMENT '文章分类名称',
+    `tags` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '文章标签名称，用,隔开',
+    `base_version` INT NOT NULL DEFAULT 0 COMMENT '草稿所基于的文章版本号',
+    `created_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY (`article_id`, `user_id`)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
 CREATE TABLE `article_categories` (
     `id` INT AUTO_INCREMENT COMMENT '分类ID',  -- 分类ID
     `name` VARCHAR(20) NOT NULL COMMENT '分类名称',  -- 分类名称
@@ -79,6 +209,73 @@ CREATE TABLE `article_tag_relations` (
     KEY (`tag_id`)
 ) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
 
+CREATE TABLE `article_series` (
+    `id` INT AUTO_INCREMENT COMMENT '系列ID',
+    `name` VARCHAR(50) NOT NULL COMMENT '系列名称',
+    `description` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '系列简介',
+    `created_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY (`name`)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `article_series_relations` (
+    `id` INT AUTO_INCREMENT COMMENT '文章系列关系ID',
+    `series_id` INT NOT NULL COMMENT '系列ID',
+    `article_id` INT NOT NULL COMMENT '文章ID，每篇文章最多属于1个系列',
+    `sort_order` INT NOT NULL DEFAULT 0 COMMENT '文章在系列中的顺序，从小到大',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY (`article_id`),
+    KEY (`series_id`, `sort_order`)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `comments` (
+    `id` INT AUTO_INCREMENT COMMENT '评论ID',
+    `article_id` INT NOT NULL COMMENT '文章ID',
+    `root_id` INT NOT NULL DEFAULT 0 COMMENT '所属顶层评论ID，顶层评论为0',
+    `parent_id` INT NOT NULL DEFAULT 0 COMMENT '回复的评论ID，顶层评论为0',
+    `nickname` VARCHAR(30) NOT NULL COMMENT '评论者昵称',
+    `email` VARCHAR(100) NOT NULL DEFAULT '' COMMENT '评论者邮箱',
+    `website` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '评论者网站',
+    `content` TEXT NOT NULL COMMENT '评论内容',
+    `status` TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '状态，0表示待审核，1表示已通过，2表示已隐藏',
+    `ip` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '评论者IP',
+    `user_agent` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '评论者UA',
+    `created_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY (`article_id`, `status`),
+    KEY (`root_id`),
+    KEY (`parent_id`)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `moderations` (
+    `id` INT AUTO_INCREMENT COMMENT '审核记录ID',
+    `subject_type` VARCHAR(30) NOT NULL COMMENT '提交类型，如user_signup',
+    `subject_id` INT NOT NULL COMMENT '提交内容对应的实体ID',
+    `content` TEXT NOT NULL COMMENT '参与检测的文本快照',
+    `fingerprint` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '提交者指纹',
+    `ip` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '提交者IP',
+    `score` INT NOT NULL DEFAULT 0 COMMENT '可疑分数，越高越可疑',
+    `reasons` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '命中的规则，用,隔开',
+    `status` TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '状态，0表示待审核，1表示已通过，2表示已拒绝',
+    `reviewer_id` INT NOT NULL DEFAULT 0 COMMENT '审核人ID',
+    `reviewed_time` DATETIME COMMENT '审核时间',
+    `created_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY (`status`, `subject_type`),
+    KEY (`subject_type`, `subject_id`),
+    KEY (`fingerprint`)
+) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;
+
+INSERT INTO roles(role)
+VALUES
+(0),
+(1),
+(2),
+(3);
+
 INSERT INTO article_categories(name) 
 VALUES
 ("Java"),
@@ -0,0 +1,95 @@
+package comment
+
+import (
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+// Reply 回复及被回复评论的昵称
+type Reply struct {
+	model.Comment
+	ReplyTo string
+}
+
+// Thread 顶层评论及其下全部回复，回复保持查询顺序
+type Thread struct {
+	Root    model.Comment
+	Replies []Reply
+}
+
+// CheckArticle 校验文章已上线，forWrite为true时还要求文章允许评论
+func CheckArticle(article *model.Article, forWrite bool) error {
+	if article == nil || article.Status != utils.ARTICLE_STATUS_ONLINE {
+		return cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
+	}
+	if forWrite && !article.AllowComment {
+		return cerr.New(cerr.ERROR_COMMENT_NOT_ALLOWED)
+	}
+	return nil
+}
+
+// RootOf 校验被回复的评论并返回新回复所属的顶层评论ID
+// 只能回复同一文章下已展示的评论
+func RootOf(parent *model.Comment, articleID int64) (int64, error) {
+	if parent == nil || parent.ArticleID != articleID || parent.Status != utils.COMMENT_STATUS_APPROVED {
+		return 0, cerr.New(cerr.ERROR_COMMENT_PARENT_INVALID)
+	}
+	if parent.RootID == 0 {
+		return parent.ID, nil
+	}
+	return parent.RootID, nil
+}
+
+// BuildThreads 将回复挂到所属顶层评论下组装楼中楼，不属于rootList的回复被丢弃
+func BuildThreads(rootList, replyList []model.Comment) []Thread {
+	nicknameMap := make(map[int64]string, len(rootList)+len(replyList))
+	for i := range rootList {
+		nicknameMap[rootList[i].ID] = rootList[i].Nickname
+	}
+	for i := range replyList {
+		nicknameMap[replyList[i].ID] = replyList[i].Nickname
+	}
+	replyMap := make(map[int64][]Reply, len(rootList))
+	for i := range replyList {
+		replyMap[replyList[i].RootID] = append(replyMap[replyList[i].RootID], Reply{
+			Comment: replyList[i],
+			ReplyTo: nicknameMap[replyList[i].ParentID],
+		})
+	}
+	threads := make([]Thread, 0, len(rootList))
+	for i := range rootList {
+		threads = append(threads, Thread{Root: rootList[i], Replies: replyMap[rootList[i].ID]})
+	}
+	return threads
+}
+
+// CollectDescendants 返回ids及其下所有层级的回复ID，listChildren查询直接回复指定评论的评论ID
+func CollectDescendants(ids []int64, listChildren func(parentIDs []int64) ([]int64, error)) ([]int64, error) {
+	seen := make(map[int64]bool, len(ids))
+	result := make([]int64, 0, len(ids))
+	parentIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+			parentIDs = append(parentIDs, id)
+		}
+	}
+	for len(parentIDs) > 0 {
+		childIDs, err := listChildren(parentIDs)
+		if err != nil {
+			return nil, err
+		}
+		parentIDs = parentIDs[:0:0]
+		// 跳过已收集的ID，避免异常数据形成环时死循环
+		for _, id := range childIDs {
+			if !seen[id] {
+				seen[id] = true
+				result = append(result, id)
+				parentIDs = append(parentIDs, id)
+			}
+		}
+	}
+	return result, nil
+}
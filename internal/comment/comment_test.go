@@ -0,0 +1,178 @@
+package comment
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+// errCode 返回业务错误码，nil返回0
+func errCode(err error) int {
+	var e *cerr.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	if err != nil {
+		return -1
+	}
+	return 0
+}
+
+func TestCheckArticle(t *testing.T) {
+	online := &model.Article{Status: utils.ARTICLE_STATUS_ONLINE, AllowComment: true}
+	closed := &model.Article{Status: utils.ARTICLE_STATUS_ONLINE, AllowComment: false}
+	offline := &model.Article{Status: utils.ARTICLE_STATUS_OFFLINE, AllowComment: true}
+	tests := []struct {
+		name     string
+		article  *model.Article
+		forWrite bool
+		want     int
+	}{
+		{name: "read online article", article: online},
+		{name: "write online article", article: online, forWrite: true},
+		{name: "read article not allowing comment", article: closed},
+		{name: "write article not allowing comment", article: closed, forWrite: true, want: cerr.ERROR_COMMENT_NOT_ALLOWED},
+		{name: "read offline article", article: offline, want: cerr.ERROR_ARTICLE_NOT_EXIST},
+		{name: "write offline article", article: offline, forWrite: true, want: cerr.ERROR_ARTICLE_NOT_EXIST},
+		{name: "article not found", article: nil, forWrite: true, want: cerr.ERROR_ARTICLE_NOT_EXIST},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errCode(CheckArticle(tt.article, tt.forWrite)); got != tt.want {
+				t.Errorf("CheckArticle() error code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		parent   *model.Comment
+		wantRoot int64
+		want     int
+	}{
+		{name: "reply to root", parent: &model.Comment{ID: 1, ArticleID: 10, Status: utils.COMMENT_STATUS_APPROVED}, wantRoot: 1},
+		{name: "reply to reply", parent: &model.Comment{ID: 3, ArticleID: 10, RootID: 1, ParentID: 2, Status: utils.COMMENT_STATUS_APPROVED}, wantRoot: 1},
+		{name: "parent not found", parent: nil, want: cerr.ERROR_COMMENT_PARENT_INVALID},
+		{name: "parent of another article", parent: &model.Comment{ID: 1, ArticleID: 11, Status: utils.COMMENT_STATUS_APPROVED}, want: cerr.ERROR_COMMENT_PARENT_INVALID},
+		{name: "pending parent", parent: &model.Comment{ID: 1, ArticleID: 10, Status: utils.COMMENT_STATUS_PENDING}, want: cerr.ERROR_COMMENT_PARENT_INVALID},
+		{name: "hidden parent", parent: &model.Comment{ID: 1, ArticleID: 10, Status: utils.COMMENT_STATUS_HIDDEN}, want: cerr.ERROR_COMMENT_PARENT_INVALID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := RootOf(tt.parent, 10)
+			if got := errCode(err); got != tt.want {
+				t.Fatalf("RootOf() error code = %d, want %d", got, tt.want)
+			}
+			if root != tt.wantRoot {
+				t.Errorf("RootOf() = %d, want %d", root, tt.wantRoot)
+			}
+		})
+	}
+}
+
+func TestBuildThreads(t *testing.T) {
+	rootList := []model.Comment{
+		{ID: 1, Nickname: "alice"},
+		{ID: 5, Nickname: "bob"},
+		{ID: 8, Nickname: "carol"},
+	}
+	replyList := []model.Comment{
+		{ID: 2, RootID: 1, ParentID: 1, Nickname: "dave"},
+		{ID: 6, RootID: 5, ParentID: 5, Nickname: "erin"},
+		{ID: 3, RootID: 1, ParentID: 2, Nickname: "frank"},
+		// 回复的评论未展示，昵称为空
+		{ID: 4, RootID: 1, ParentID: 9, Nickname: "grace"},
+		// 所属顶层评论不在当前页
+		{ID: 7, RootID: 100, ParentID: 100, Nickname: "heidi"},
+	}
+	threads := BuildThreads(rootList, replyList)
+
+	type reply struct {
+		ID      int64
+		ReplyTo string
+	}
+	got := map[int64][]reply{}
+	var order []int64
+	for _, thread := range threads {
+		order = append(order, thread.Root.ID)
+		for _, r := range thread.Replies {
+			got[thread.Root.ID] = append(got[thread.Root.ID], reply{r.ID, r.ReplyTo})
+		}
+	}
+	if want := []int64{1, 5, 8}; !reflect.DeepEqual(order, want) {
+		t.Errorf("root order = %v, want %v", order, want)
+	}
+	want := map[int64][]reply{
+		1: {{2, "alice"}, {3, "dave"}, {4, ""}},
+		5: {{6, "bob"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replies = %v, want %v", got, want)
+	}
+	if len(BuildThreads(nil, nil)) != 0 {
+		t.Error("BuildThreads(nil, nil) is not empty")
+	}
+}
+
+func TestCollectDescendants(t *testing.T) {
+	// parent -> 直接回复
+	children := map[int64][]int64{
+		1: {2, 3},
+		2: {4},
+		4: {5},
+		6: {7},
+		// 异常数据形成环
+		8: {9},
+		9: {8},
+	}
+	listChildren := func(calls *int) func([]int64) ([]int64, error) {
+		return func(parentIDs []int64) ([]int64, error) {
+			*calls++
+			var childIDs []int64
+			for _, id := range parentIDs {
+				childIDs = append(childIDs, children[id]...)
+			}
+			return childIDs, nil
+		}
+	}
+	tests := []struct {
+		name      string
+		ids       []int64
+		want      []int64
+		wantCalls int
+	}{
+		{name: "nested replies", ids: []int64{1}, want: []int64{1, 2, 3, 4, 5}, wantCalls: 4},
+		{name: "reply without children", ids: []int64{3}, want: []int64{3}, wantCalls: 1},
+		{name: "multiple roots", ids: []int64{4, 6}, want: []int64{4, 6, 5, 7}, wantCalls: 2},
+		{name: "ancestor and descendant both selected", ids: []int64{2, 1}, want: []int64{2, 1, 4, 3, 5}, wantCalls: 3},
+		{name: "duplicated ids", ids: []int64{6, 6}, want: []int64{6, 7}, wantCalls: 2},
+		{name: "cycle", ids: []int64{8}, want: []int64{8, 9}, wantCalls: 2},
+		{name: "empty", ids: nil, want: []int64{}, wantCalls: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			got, err := CollectDescendants(tt.ids, listChildren(&calls))
+			if err != nil {
+				t.Fatalf("CollectDescendants() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectDescendants() = %v, want %v", got, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("listChildren calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+
+	wantErr := errors.New("db error")
+	if _, err := CollectDescendants([]int64{1}, func([]int64) ([]int64, error) { return nil, wantErr }); !errors.Is(err, wantErr) {
+		t.Errorf("CollectDescendants() error = %v, want %v", err, wantErr)
+	}
+}
@@ -108,6 +108,34 @@ func RunDBTransaction(c *gin.Context, fn func() error) error {
 	return nil
 }
 
+// RunDBTransaction2 在非请求场景(如定时任务)中执行事务，fn中需使用传入的ctx访问数据库
+func RunDBTransaction2(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx := Client.Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("begin transaction failed: %w", tx.Error)
+	}
+	txCtx := context.WithValue(ctx, DB_TRANSACTION_CONTEXT_KEY, tx)
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(txCtx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 func GetDBFromContext(c *gin.Context) *gorm.DB {
 	tx, dbExist := c.Get(DB_TRANSACTION_CONTEXT_KEY)
 	if !dbExist || tx == nil {
@@ -0,0 +1,214 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 编辑距离超过该值时不再计算最小差异，直接按整体替换处理，避免占用过多CPU
+const maxEditDistance = 4000
+
+type OpKind int
+
+const (
+	OpEqual OpKind = iota
+	OpDelete
+	OpInsert
+)
+
+// Edit 单行编辑操作
+type Edit struct {
+	Kind OpKind
+	Line string
+}
+
+// Lines 按行比较两段文本，返回编辑脚本
+func Lines(from, to string) []Edit {
+	return computeEdits(splitLines(from), splitLines(to))
+}
+
+// Unified 生成统一格式(unified diff)的差异文本，无差异时返回空字符串
+func Unified(fromName, toName, from, to string, context int) string {
+	edits := Lines(from, to)
+	if context < 0 {
+		context = 0
+	}
+
+	// 找出所有变更所在位置
+	var changes []int
+	for i := range edits {
+		if edits[i].Kind != OpEqual {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	// 每个编辑操作之前，两侧文本已处理的行数
+	aPos := make([]int, len(edits)+1)
+	bPos := make([]int, len(edits)+1)
+	for i := range edits {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if edits[i].Kind != OpInsert {
+			aPos[i+1]++
+		}
+		if edits[i].Kind != OpDelete {
+			bPos[i+1]++
+		}
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", fromName, toName)
+	for i := 0; i < len(changes); {
+		start := max(0, changes[i]-context)
+		end := min(len(edits), changes[i]+context+1)
+		// 合并间隔不超过2倍上下文的变更
+		j := i + 1
+		for j < len(changes) && changes[j]-context <= end {
+			end = min(len(edits), changes[j]+context+1)
+			j++
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(aPos[start], aPos[end]-aPos[start]),
+			hunkRange(bPos[start], bPos[end]-bPos[start]))
+		for k := start; k < end; k++ {
+			switch edits[k].Kind {
+			case OpEqual:
+				buf.WriteString(" ")
+			case OpDelete:
+				buf.WriteString("-")
+			case OpInsert:
+				buf.WriteString("+")
+			}
+			buf.WriteString(edits[k].Line)
+			buf.WriteString("\n")
+		}
+		i = j
+	}
+	return buf.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func computeEdits(a, b []string) []Edit {
+	return appendEdits(make([]Edit, 0, len(a)+len(b)), a, b)
+}
+
+// appendEdits 线性空间的Myers差分算法：去掉相同的前缀和后缀后找到最短编辑路径中间的蛇形，
+// 以此将问题分为前后两个子问题递归求解，内存占用与文本行数成正比
+func appendEdits(edits []Edit, a, b []string) []Edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, Edit{Kind: OpEqual, Line: a[i]})
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if x, y, u, v, ok := middleSnake(midA, midB); ok {
+		edits = appendEdits(edits, midA[:x], midB[:y])
+		for i := x; i < u; i++ {
+			edits = append(edits, Edit{Kind: OpEqual, Line: midA[i]})
+		}
+		edits = appendEdits(edits, midA[u:], midB[v:])
+	} else {
+		edits = append(edits, replaceAll(midA, midB)...)
+	}
+	for i := len(a) - suffix; i < len(a); i++ {
+		edits = append(edits, Edit{Kind: OpEqual, Line: a[i]})
+	}
+	return edits
+}
+
+// middleSnake 从两端同时搜索最短编辑路径，返回两端路径相遇处蛇形的起点(x,y)和终点(u,v)。
+// a、b首尾行均不相同，任一为空或编辑距离超过maxEditDistance时返回false
+func middleSnake(a, b []string) (x, y, u, v int, ok bool) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return 0, 0, 0, 0, false
+	}
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	// forward[k]为正向第d轮对角线k上的最远x，backward[k]为反向(从末尾倒数)第d轮对角线k上的最远x
+	forward := make([]int, 2*maxD+3)
+	backward := make([]int, 2*maxD+3)
+	for d := 0; d <= maxD; d++ {
+		if 2*d > maxEditDistance {
+			return 0, 0, 0, 0, false
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			// 编辑距离为奇数时，正向第d轮与反向第d-1轮相遇
+			if odd && k >= delta-(d-1) && k <= delta+(d-1) && x+backward[offset+delta-k] >= n {
+				return startX, startY, x, y, true
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			// 编辑距离为偶数时，正向与反向第d轮相遇
+			if !odd && delta-k >= -d && delta-k <= d && x+forward[offset+delta-k] >= n {
+				return n - x, m - y, n - startX, m - startY, true
+			}
+		}
+	}
+	return 0, 0, 0, 0, false
+}
+
+func replaceAll(a, b []string) []Edit {
+	edits := make([]Edit, 0, len(a)+len(b))
+	for i := range a {
+		edits = append(edits, Edit{Kind: OpDelete, Line: a[i]})
+	}
+	for i := range b {
+		edits = append(edits, Edit{Kind: OpInsert, Line: b[i]})
+	}
+	return edits
+}
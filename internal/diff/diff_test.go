@@ -0,0 +1,178 @@
+package diff
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  string
+		wantEdits int // 删除和插入的行数之和
+	}{
+		{name: "both empty", from: "", to: "", wantEdits: 0},
+		{name: "equal", from: "a\nb\nc\n", to: "a\nb\nc\n", wantEdits: 0},
+		{name: "insert into empty", from: "", to: "a\nb\n", wantEdits: 2},
+		{name: "delete all", from: "a\nb\n", to: "", wantEdits: 2},
+		{name: "append line", from: "a\nb\n", to: "a\nb\nc\n", wantEdits: 1},
+		{name: "change middle line", from: "a\nb\nc\n", to: "a\nx\nc\n", wantEdits: 2},
+		{name: "crlf equals lf", from: "a\r\nb\r\n", to: "a\nb\n", wantEdits: 0},
+		{name: "move line", from: "a\nb\nc\nd\n", to: "b\nc\nd\na\n", wantEdits: 2},
+		{name: "interleaved", from: "a\nb\nc\na\nb\nb\na\n", to: "c\nb\na\nb\na\nc\n", wantEdits: 5},
+		{name: "completely different", from: "a\nb\nc\n", to: "x\ny\n", wantEdits: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := Lines(tt.from, tt.to)
+			checkEdits(t, splitLines(tt.from), splitLines(tt.to), edits)
+			if got := countChanges(edits); got != tt.wantEdits {
+				t.Errorf("changes = %d, want %d", got, tt.wantEdits)
+			}
+		})
+	}
+}
+
+// TestLinesMinimal 与最长公共子序列比较，验证编辑脚本是最短的
+func TestLinesMinimal(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		a := randomLines(rnd, rnd.Intn(30))
+		b := randomLines(rnd, rnd.Intn(30))
+		edits := computeEdits(a, b)
+		checkEdits(t, a, b, edits)
+		if got, want := countChanges(edits), len(a)+len(b)-2*lcs(a, b); got != want {
+			t.Fatalf("case %d: changes = %d, want %d\na=%v\nb=%v", i, got, want, a, b)
+		}
+	}
+}
+
+func TestLinesExceedMaxEditDistance(t *testing.T) {
+	n := maxEditDistance
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := 0; i < n; i++ {
+		a[i] = "a" + strconv.Itoa(i)
+		b[i] = "b" + strconv.Itoa(i)
+	}
+	edits := computeEdits(a, b)
+	checkEdits(t, a, b, edits)
+	if got := countChanges(edits); got != 2*n {
+		t.Errorf("changes = %d, want %d", got, 2*n)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		context int
+		want    string
+	}{
+		{
+			name: "no change",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name:    "single change with context",
+			from:    "1\n2\n3\n4\n5\n6\n7\n",
+			to:      "1\n2\n3\nx\n5\n6\n7\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -3,3 +3,3 @@\n 3\n-4\n+x\n 5\n",
+		},
+		{
+			name:    "separate hunks",
+			from:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			to:      "x\n2\n3\n4\n5\n6\n7\ny\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -1,2 +1,2 @@\n-1\n+x\n 2\n@@ -7,2 +7,2 @@\n 7\n-8\n+y\n",
+		},
+		{
+			name:    "merged hunks",
+			from:    "1\n2\n3\n4\n",
+			to:      "x\n2\n3\ny\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n-4\n+y\n",
+		},
+		{
+			name: "insert into empty",
+			from: "",
+			to:   "a\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:    "negative context",
+			from:    "1\n2\n3\n",
+			to:      "1\nx\n3\n",
+			context: -1,
+			want:    "--- old\n+++ new\n@@ -2 +2 @@\n-2\n+x\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified("old", "new", tt.from, tt.to, tt.context); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// checkEdits 检查编辑脚本能由a还原出a和b
+func checkEdits(t *testing.T, a, b []string, edits []Edit) {
+	t.Helper()
+	var gotA, gotB []string
+	for _, edit := range edits {
+		if edit.Kind != OpInsert {
+			gotA = append(gotA, edit.Line)
+		}
+		if edit.Kind != OpDelete {
+			gotB = append(gotB, edit.Line)
+		}
+	}
+	if strings.Join(gotA, "\n") != strings.Join(a, "\n") || len(gotA) != len(a) {
+		t.Fatalf("edits do not reproduce from: got %v, want %v", gotA, a)
+	}
+	if strings.Join(gotB, "\n") != strings.Join(b, "\n") || len(gotB) != len(b) {
+		t.Fatalf("edits do not reproduce to: got %v, want %v", gotB, b)
+	}
+}
+
+func countChanges(edits []Edit) int {
+	count := 0
+	for _, edit := range edits {
+		if edit.Kind != OpEqual {
+			count++
+		}
+	}
+	return count
+}
+
+func randomLines(rnd *rand.Rand, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = string(rune('a' + rnd.Intn(4)))
+	}
+	return lines
+}
+
+func lcs(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
@@ -5,21 +5,63 @@ const (
 	UNKNOWN      = 1
 	BAD_REQUEST  = 400
 	UNAUTHORIZED = 401
+	FORBIDDEN    = 403
 	SYSTEM_ERROR = 500
 
-	ERROR_USER_NOT_EXIST       = 1001
-	ERROR_USER_ALREADY_EXIST   = 1002
-	ERROR_USER_PASSWORD_WRONG  = 1003
-	ERROR_USER_TOKEN_INVALIDE  = 1004
-	ERROR_USER_TOKEN_EXPIRE    = 1005
-	ERROR_USER_TOKEN_NOT_MATCH = 1006
-
-	ERROR_ARTICLE_TAG_NOT_EXIST      = 2001
-	ERROR_ARTICLE_CATEGORY_NOT_EXIST = 2002
-	ERROR_ARTICLE_NOT_EXIST          = 2003
-	ERROR_ARTICLE_TYPE_NOT_SUPPORT   = 2004
-	ERROR_ARTICLE_CATEGORY_EXIST     = 2005
-	ERROR_ARTICLE_TAG_EXIST          = 2006
+	ERROR_USER_NOT_EXIST               = 1001
+	ERROR_USER_ALREADY_EXIST           = 1002
+	ERROR_USER_PASSWORD_WRONG          = 1003
+	ERROR_USER_TOKEN_INVALIDE          = 1004
+	ERROR_USER_TOKEN_EXPIRE            = 1005
+	ERROR_USER_TOKEN_NOT_MATCH         = 1006
+	ERROR_USER_PENDING_REVIEW          = 1007
+	ERROR_USER_REJECTED                = 1008
+	ERROR_USER_ROLE_NOT_EXIST          = 1009
+	ERROR_USER_ROLE_SELF               = 1010
+	ERROR_USER_DISABLED                = 1011
+	ERROR_USER_NICKNAME_EXIST          = 1012
+	ERROR_USER_DISABLE_SELF            = 1013
+	ERROR_USER_RESET_INVALID           = 1014
+	ERROR_USER_LOGIN_LOCKED            = 1015
+	ERROR_USER_CAPTCHA_REQUIRE         = 1016
+	ERROR_USER_CAPTCHA_WRONG           = 1017
+	ERROR_USER_TOTP_INVALID            = 1018
+	ERROR_USER_PREAUTH_INVALID         = 1019
+	ERROR_USER_TOTP_NOT_ENROLL         = 1020
+	ERROR_USER_TOTP_ENABLED            = 1021
+	ERROR_USER_SESSION_NOT_EXIST       = 1022
+	ERROR_USER_OIDC_PROVIDER_NOT_EXIST = 1023
+	ERROR_USER_OIDC_STATE_INVALID      = 1024
+	ERROR_USER_OIDC_FAILED             = 1025
+	ERROR_USER_OIDC_SIGNUP_DISABLED    = 1026
+	ERROR_USER_IDENTITY_LINKED         = 1027
+	ERROR_USER_IDENTITY_NOT_EXIST      = 1028
+	ERROR_USER_ACCESS_TOKEN_NOT_EXIST  = 1029
+	ERROR_USER_ACCESS_TOKEN_LIMIT      = 1030
+	ERROR_USER_PASSWORD_RESET_REQUIRED = 1031
+	ERROR_USER_RESET_TOO_FREQUENT      = 1032
+
+	ERROR_ARTICLE_TAG_NOT_EXIST           = 2001
+	ERROR_ARTICLE_CATEGORY_NOT_EXIST      = 2002
+	ERROR_ARTICLE_NOT_EXIST               = 2003
+	ERROR_ARTICLE_TYPE_NOT_SUPPORT        = 2004
+	ERROR_ARTICLE_CATEGORY_EXIST          = 2005
+	ERROR_ARTICLE_TAG_EXIST               = 2006
+	ERROR_ARTICLE_REVISION_NOT_EXIST      = 2007
+	ERROR_ARTICLE_VERSION_CONFLICT        = 2008
+	ERROR_ARTICLE_DRAFT_NOT_EXIST         = 2009
+	ERROR_ARTICLE_SLUG_EXIST              = 2010
+	ERROR_ARTICLE_PERMISSION_DENIED       = 2011
+	ERROR_ARTICLE_SERIES_NOT_EXIST        = 2012
+	ERROR_ARTICLE_SERIES_EXIST            = 2013
+	ERROR_ARTICLE_SERIES_ARTICLE_CONFLICT = 2014
+
+	ERROR_COMMENT_NOT_EXIST      = 3001
+	ERROR_COMMENT_NOT_ALLOWED    = 3002
+	ERROR_COMMENT_PARENT_INVALID = 3003
+
+	ERROR_MODERATION_NOT_EXIST           = 4001
+	ERROR_MODERATION_SUBJECT_NOT_SUPPORT = 4002
 )
 
 var codeMsg = map[int]string{
@@ -27,21 +69,63 @@ var codeMsg = map[int]string{
 	UNKNOWN:      "未知错误",
 	BAD_REQUEST:  "请求参数错误",
 	UNAUTHORIZED: "未认证错误",
+	FORBIDDEN:    "无访问权限",
 	SYSTEM_ERROR: "系统内部错误",
 
-	ERROR_USER_NOT_EXIST:       "用户不存在",
-	ERROR_USER_ALREADY_EXIST:   "用户已存在",
-	ERROR_USER_PASSWORD_WRONG:  "密码错误",
-	ERROR_USER_TOKEN_INVALIDE:  "Token无效",
-	ERROR_USER_TOKEN_EXPIRE:    "登陆过期",
-	ERROR_USER_TOKEN_NOT_MATCH: "Token不匹配",
-
-	ERROR_ARTICLE_TAG_NOT_EXIST:      "标签不存在",
-	ERROR_ARTICLE_TAG_EXIST:          "标签已存在",
-	ERROR_ARTICLE_CATEGORY_NOT_EXIST: "分类不存在",
-	ERROR_ARTICLE_CATEGORY_EXIST:     "分类已存在",
-	ERROR_ARTICLE_NOT_EXIST:          "文章不存在",
-	ERROR_ARTICLE_TYPE_NOT_SUPPORT:   "不支持的文章类型",
+	ERROR_USER_NOT_EXIST:               "用户不存在",
+	ERROR_USER_ALREADY_EXIST:           "用户已存在",
+	ERROR_USER_PASSWORD_WRONG:          "密码错误",
+	ERROR_USER_TOKEN_INVALIDE:          "Token无效",
+	ERROR_USER_TOKEN_EXPIRE:            "登陆过期",
+	ERROR_USER_TOKEN_NOT_MATCH:         "Token不匹配",
+	ERROR_USER_PENDING_REVIEW:          "账号待审核",
+	ERROR_USER_REJECTED:                "账号审核未通过",
+	ERROR_USER_ROLE_NOT_EXIST:          "角色不存在",
+	ERROR_USER_ROLE_SELF:               "不能撤销自己的管理员角色",
+	ERROR_USER_DISABLED:                "账号已被禁用",
+	ERROR_USER_NICKNAME_EXIST:          "昵称已被使用",
+	ERROR_USER_DISABLE_SELF:            "不能禁用自己的账号",
+	ERROR_USER_RESET_INVALID:           "重置链接无效或已过期",
+	ERROR_USER_LOGIN_LOCKED:            "登录失败次数过多，请稍后重试",
+	ERROR_USER_CAPTCHA_REQUIRE:         "请输入验证码",
+	ERROR_USER_CAPTCHA_WRONG:           "验证码错误或已过期",
+	ERROR_USER_TOTP_INVALID:            "动态验证码或恢复码错误",
+	ERROR_USER_PREAUTH_INVALID:         "登录验证已过期，请重新登录",
+	ERROR_USER_TOTP_NOT_ENROLL:         "未绑定两步验证",
+	ERROR_USER_TOTP_ENABLED:            "已开启两步验证",
+	ERROR_USER_SESSION_NOT_EXIST:       "会话不存在或已失效",
+	ERROR_USER_OIDC_PROVIDER_NOT_EXIST: "不支持该第三方登录方式",
+	ERROR_USER_OIDC_STATE_INVALID:      "第三方登录已过期，请重新登录",
+	ERROR_USER_OIDC_FAILED:             "第三方登录失败",
+	ERROR_USER_OIDC_SIGNUP_DISABLED:    "该第三方账号未关联用户",
+	ERROR_USER_IDENTITY_LINKED:         "该第三方账号已关联其他用户",
+	ERROR_USER_IDENTITY_NOT_EXIST:      "未关联该第三方账号",
+	ERROR_USER_ACCESS_TOKEN_NOT_EXIST:  "访问令牌不存在",
+	ERROR_USER_ACCESS_TOKEN_LIMIT:      "访问令牌数量已达上限",
+	ERROR_USER_PASSWORD_RESET_REQUIRED: "密码已被管理员重置，请先修改密码",
+	ERROR_USER_RESET_TOO_FREQUENT:      "重置密码请求过于频繁，请稍后重试",
+
+	ERROR_ARTICLE_TAG_NOT_EXIST:           "标签不存在",
+	ERROR_ARTICLE_TAG_EXIST:               "标签已存在",
+	ERROR_ARTICLE_CATEGORY_NOT_EXIST:      "分类不存在",
+	ERROR_ARTICLE_CATEGORY_EXIST:          "分类已存在",
+	ERROR_ARTICLE_NOT_EXIST:               "文章不存在",
+	ERROR_ARTICLE_TYPE_NOT_SUPPORT:        "不支持的文章类型",
+	ERROR_ARTICLE_REVISION_NOT_EXIST:      "文章版本不存在",
+	ERROR_ARTICLE_VERSION_CONFLICT:        "文章已被其他会话修改，请刷新后重试",
+	ERROR_ARTICLE_DRAFT_NOT_EXIST:         "草稿不存在",
+	ERROR_ARTICLE_SLUG_EXIST:              "文章别名已被使用",
+	ERROR_ARTICLE_PERMISSION_DENIED:       "无权操作该文章",
+	ERROR_ARTICLE_SERIES_NOT_EXIST:        "系列不存在",
+	ERROR_ARTICLE_SERIES_EXIST:            "系列已存在",
+	ERROR_ARTICLE_SERIES_ARTICLE_CONFLICT: "文章已属于其他系列",
+
+	ERROR_COMMENT_NOT_EXIST:      "评论不存在",
+	ERROR_COMMENT_NOT_ALLOWED:    "该文章不允许评论",
+	ERROR_COMMENT_PARENT_INVALID: "回复的评论无效",
+
+	ERROR_MODERATION_NOT_EXIST:           "审核记录不存在或已处理",
+	ERROR_MODERATION_SUBJECT_NOT_SUPPORT: "不支持的审核类型",
 }
 
 func GetMessage(code int) string {
@@ -0,0 +1,341 @@
+package feed
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	MODE_SUMMARY = "summary"
+	MODE_FULL    = "full"
+)
+
+var Config = NewDefaultFeedCfg()
+
+type FeedConfig struct {
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`             // 订阅源标题
+	Description string `json:"description,omitempty" yaml:"description,omitempty"` // 订阅源描述
+	Limit       int    `json:"limit,omitempty" yaml:"limit,omitempty"`             // 输出的最新文章数
+	Mode        string `json:"mode,omitempty" yaml:"mode,omitempty"`               // 默认输出模式，summary: 只输出摘要，full: 输出全文
+	PathPrefix  string `json:"pathPrefix,omitempty" yaml:"pathPrefix,omitempty"`   // 接口经反向代理后的路径前缀，用于生成订阅源自身链接
+}
+
+func NewDefaultFeedCfg() FeedConfig {
+	return FeedConfig{
+		Title:       "Narcissus Blog",
+		Description: "",
+		Limit:       20,
+		Mode:        MODE_SUMMARY,
+		PathPrefix:  "/api",
+	}
+}
+
+func MustInit(cfg FeedConfig) {
+	if cfg.Mode != MODE_SUMMARY && cfg.Mode != MODE_FULL {
+		panic("feed mode must be summary or full")
+	}
+	if cfg.Limit <= 0 {
+		panic("feed limit must be greater than 0")
+	}
+	Config = cfg
+}
+
+// Feed 与输出格式无关的订阅源
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // 站点页面链接
+	FeedURL     string // 订阅源自身链接
+	Language    string
+	Updated     time.Time
+	Items       []Item
+}
+
+type Item struct {
+	ID         string
+	Title      string
+	Link       string
+	Author     string
+	Summary    string
+	Content    string // 全文模式下渲染后的HTML正文，为空时只输出摘要
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// Version 订阅源中文章的ID和更新时间，无需生成订阅源即可判断是否变化
+type Version struct {
+	ID      int64
+	Updated time.Time
+}
+
+// ETag 由请求地址、文章ID和更新时间计算，文章增删改都会改变ETag
+func ETag(requestURI string, versions []Version) string {
+	h := md5.New()
+	h.Write([]byte(requestURI))
+	for _, version := range versions {
+		fmt.Fprintf(h, "|%d:%d", version.ID, version.Updated.UnixMilli())
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+// LastModified 文章的最新更新时间，没有文章时为零值
+func LastModified(versions []Version) time.Time {
+	var lastModified time.Time
+	for _, version := range versions {
+		if version.Updated.After(lastModified) {
+			lastModified = version.Updated
+		}
+	}
+	return lastModified
+}
+
+// NotModified 优先使用If-None-Match判断，没有时再使用If-Modified-Since
+func NotModified(ifNoneMatch, ifModifiedSince, etag string, lastModified time.Time) bool {
+	if ifNoneMatch != "" {
+		// If-None-Match使用弱比较，可以携带多个ETag
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if ifModifiedSince != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Creator     string   `xml:"dc:creator,omitempty"` // RSS的author要求为邮箱，作者名使用dc:creator
+	Description string   `xml:"description"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// ToRSS 生成RSS 2.0格式
+func (f *Feed) ToRSS() ([]byte, error) {
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Description,
+		Language:      f.Language,
+		LastBuildDate: f.Updated.Format(time.RFC1123Z),
+		AtomLink:      rssLink{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"},
+		Items:         make([]rssItem, 0, len(f.Items)),
+	}
+	for _, item := range f.Items {
+		description := item.Summary
+		if item.Content != "" {
+			description = item.Content
+		}
+		channel.Items = append(channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: item.Link},
+			Creator:     item.Author,
+			Description: description,
+			Categories:  item.Categories,
+			PubDate:     item.Published.Format(time.RFC1123Z),
+		})
+	}
+	return marshalXML(rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: channel,
+	})
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	NS       string      `xml:"xmlns,attr"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// ToAtom 生成Atom 1.0格式
+func (f *Feed) ToAtom() ([]byte, error) {
+	feed := atomFeed{
+		NS:       "http://www.w3.org/2005/Atom",
+		Lang:     f.Language,
+		ID:       f.FeedURL,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  f.Updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.FeedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: make([]atomEntry, 0, len(f.Items)),
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			Published: item.Published.Format(time.RFC3339),
+			Updated:   item.Updated.Format(time.RFC3339),
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: item.Summary}
+		}
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return marshalXML(feed)
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Language    string         `json:"language,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// ToJSON 生成JSON Feed 1.1格式
+func (f *Feed) ToJSON() ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Language:    f.Language,
+		Items:       make([]jsonFeedItem, 0, len(f.Items)),
+	}
+	for _, item := range f.Items {
+		jsonItem := jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.Content,
+			Summary:       item.Summary,
+			DatePublished: item.Published.Format(time.RFC3339),
+			DateModified:  item.Updated.Format(time.RFC3339),
+			Tags:          item.Categories,
+		}
+		// content_html和content_text至少需要一个，摘要模式下使用摘要
+		if item.Content == "" {
+			jsonItem.ContentText = item.Summary
+		}
+		if item.Author != "" {
+			jsonItem.Authors = []jsonFeedAuthor{{Name: item.Author}}
+		}
+		feed.Items = append(feed.Items, jsonItem)
+	}
+	return json.Marshal(feed)
+}
+
+func marshalXML(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,304 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)
+	return &Feed{
+		Title:       "Narcissus Blog",
+		Description: "随笔",
+		Link:        "https://example.com/",
+		FeedURL:     "https://example.com/api/feed/rss",
+		Language:    "zh-CN",
+		Updated:     updated,
+		Items: []Item{
+			{
+				ID:         "https://example.com/post/1",
+				Title:      "全文 & <转义>",
+				Link:       "https://example.com/post/1",
+				Author:     "alice",
+				Summary:    "摘要",
+				Content:    "<p>正文</p>",
+				Categories: []string{"golang", "基础"},
+				Published:  published,
+				Updated:    updated,
+			},
+			{
+				ID:        "https://example.com/post/2",
+				Title:     "只有摘要",
+				Link:      "https://example.com/post/2",
+				Summary:   "只有摘要的文章",
+				Published: published,
+				Updated:   published,
+			},
+		},
+	}
+}
+
+func TestToRSS(t *testing.T) {
+	data, err := testFeed().ToRSS()
+	if err != nil {
+		t.Fatalf("ToRSS() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Errorf("ToRSS() missing xml header")
+	}
+	var got struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			AtomLink      struct {
+				Href string `xml:"href,attr"`
+				Rel  string `xml:"rel,attr"`
+			} `xml:"http://www.w3.org/2005/Atom link"`
+			Items []struct {
+				Title string `xml:"title"`
+				GUID  struct {
+					IsPermaLink bool   `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Description string   `xml:"description"`
+				Categories  []string `xml:"category"`
+				PubDate     string   `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal rss: %v", err)
+	}
+	if len(got.Channel.Items) != 2 {
+		t.Fatalf("len(items) = %d, want 2", len(got.Channel.Items))
+	}
+	first, second := got.Channel.Items[0], got.Channel.Items[1]
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"version", got.Version, "2.0"},
+		{"title", got.Channel.Title, "Narcissus Blog"},
+		{"last build date", got.Channel.LastBuildDate, "Thu, 02 May 2024 09:30:00 +0000"},
+		{"self link", got.Channel.AtomLink.Href, "https://example.com/api/feed/rss"},
+		{"self rel", got.Channel.AtomLink.Rel, "self"},
+		{"escaped title", first.Title, "全文 & <转义>"},
+		{"guid", first.GUID.Value, "https://example.com/post/1"},
+		{"creator", first.Creator, "alice"},
+		{"full content as description", first.Description, "<p>正文</p>"},
+		{"pub date", first.PubDate, "Wed, 01 May 2024 08:00:00 +0000"},
+		{"summary as description", second.Description, "只有摘要的文章"},
+		{"empty creator", second.Creator, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+	if !first.GUID.IsPermaLink {
+		t.Error("guid isPermaLink = false, want true")
+	}
+	if !reflect.DeepEqual(first.Categories, []string{"golang", "基础"}) {
+		t.Errorf("categories = %v", first.Categories)
+	}
+}
+
+func TestToAtom(t *testing.T) {
+	data, err := testFeed().ToAtom()
+	if err != nil {
+		t.Fatalf("ToAtom() error = %v", err)
+	}
+	type text struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	}
+	var got struct {
+		XMLName xml.Name
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID     string `xml:"id"`
+			Author *struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+			Summary    *text `xml:"summary"`
+			Content    *text `xml:"content"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal atom: %v", err)
+	}
+	if got.XMLName.Space != "http://www.w3.org/2005/Atom" || got.XMLName.Local != "feed" {
+		t.Errorf("root element = %v", got.XMLName)
+	}
+	if got.ID != "https://example.com/api/feed/rss" || got.Updated != "2024-05-02T09:30:00Z" {
+		t.Errorf("id = %q, updated = %q", got.ID, got.Updated)
+	}
+	if len(got.Links) != 2 || got.Links[0].Rel != "alternate" || got.Links[1].Rel != "self" {
+		t.Errorf("links = %+v", got.Links)
+	}
+	if len(got.Entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(got.Entries))
+	}
+	first, second := got.Entries[0], got.Entries[1]
+	if first.Author == nil || first.Author.Name != "alice" {
+		t.Errorf("first author = %+v", first.Author)
+	}
+	if first.Content == nil || first.Content.Type != "html" || first.Content.Value != "<p>正文</p>" {
+		t.Errorf("first content = %+v", first.Content)
+	}
+	if first.Summary == nil || first.Summary.Type != "text" || first.Summary.Value != "摘要" {
+		t.Errorf("first summary = %+v", first.Summary)
+	}
+	if len(first.Categories) != 2 || first.Categories[1].Term != "基础" {
+		t.Errorf("first categories = %+v", first.Categories)
+	}
+	if first.Published != "2024-05-01T08:00:00Z" || first.Updated != "2024-05-02T09:30:00Z" {
+		t.Errorf("first published = %q, updated = %q", first.Published, first.Updated)
+	}
+	if second.Author != nil || second.Content != nil {
+		t.Errorf("second author = %+v, content = %+v, want both omitted", second.Author, second.Content)
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	data, err := testFeed().ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal json feed: %v", err)
+	}
+	if got["version"] != "https://jsonfeed.org/version/1.1" || got["feed_url"] != "https://example.com/api/feed/rss" {
+		t.Errorf("version = %v, feed_url = %v", got["version"], got["feed_url"])
+	}
+	items, _ := got["items"].([]any)
+	if len(items) != 2 {
+		t.Fatalf("len(items) = %d, want 2", len(items))
+	}
+	first, _ := items[0].(map[string]any)
+	second, _ := items[1].(map[string]any)
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"content html", first["content_html"], "<p>正文</p>"},
+		{"no content text with html", first["content_text"], nil},
+		{"summary", first["summary"], "摘要"},
+		{"authors", first["authors"], []any{map[string]any{"name": "alice"}}},
+		{"tags", first["tags"], []any{"golang", "基础"}},
+		{"date published", first["date_published"], "2024-05-01T08:00:00Z"},
+		{"date modified", first["date_modified"], "2024-05-02T09:30:00Z"},
+		{"summary as content text", second["content_text"], "只有摘要的文章"},
+		{"no content html", second["content_html"], nil},
+		{"no authors", second["authors"], nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+
+	empty, err := (&Feed{}).ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	if !strings.Contains(string(empty), `"items":[]`) {
+		t.Errorf("empty feed items = %s, want []", empty)
+	}
+}
+
+func TestETag(t *testing.T) {
+	base := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	versions := []Version{{ID: 1, Updated: base}, {ID: 2, Updated: base}}
+	etag := ETag("/feed/rss", versions)
+	if !strings.HasPrefix(etag, `W/"`) || !strings.HasSuffix(etag, `"`) {
+		t.Errorf("ETag() = %s, want weak etag", etag)
+	}
+	tests := []struct {
+		name       string
+		requestURI string
+		versions   []Version
+		same       bool
+	}{
+		{name: "same versions", requestURI: "/feed/rss", versions: []Version{{ID: 1, Updated: base}, {ID: 2, Updated: base}}, same: true},
+		{name: "different query", requestURI: "/feed/rss?mode=full", versions: versions},
+		{name: "article updated", requestURI: "/feed/rss", versions: []Version{{ID: 1, Updated: base.Add(time.Millisecond)}, {ID: 2, Updated: base}}},
+		{name: "article removed", requestURI: "/feed/rss", versions: versions[:1]},
+		{name: "article replaced", requestURI: "/feed/rss", versions: []Version{{ID: 1, Updated: base}, {ID: 3, Updated: base}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ETag(tt.requestURI, tt.versions) == etag; got != tt.same {
+				t.Errorf("ETag() equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	base := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	versions := []Version{{ID: 1, Updated: base}, {ID: 2, Updated: base.Add(time.Hour)}, {ID: 3, Updated: base.Add(time.Minute)}}
+	if got := LastModified(versions); !got.Equal(base.Add(time.Hour)) {
+		t.Errorf("LastModified() = %v, want %v", got, base.Add(time.Hour))
+	}
+	if got := LastModified(nil); !got.IsZero() {
+		t.Errorf("LastModified(nil) = %v, want zero", got)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	etag := `W/"abc"`
+	lastModified := time.Date(2024, 5, 1, 8, 0, 0, 500*int(time.Millisecond), time.UTC)
+	httpTime := func(t time.Time) string { return t.Format(http.TimeFormat) }
+	tests := []struct {
+		name            string
+		ifNoneMatch     string
+		ifModifiedSince string
+		lastModified    time.Time
+		want            bool
+	}{
+		{name: "no condition", lastModified: lastModified},
+		{name: "etag match", ifNoneMatch: `W/"abc"`, lastModified: lastModified, want: true},
+		{name: "strong etag match", ifNoneMatch: `"abc"`, lastModified: lastModified, want: true},
+		{name: "etag in list", ifNoneMatch: `"x", W/"abc"`, lastModified: lastModified, want: true},
+		{name: "wildcard", ifNoneMatch: "*", lastModified: lastModified, want: true},
+		{name: "etag mismatch", ifNoneMatch: `W/"def"`, lastModified: lastModified},
+		{name: "etag mismatch ignores if-modified-since", ifNoneMatch: `W/"def"`, ifModifiedSince: httpTime(lastModified.Add(time.Hour)), lastModified: lastModified},
+		{name: "not modified since", ifModifiedSince: httpTime(lastModified), lastModified: lastModified, want: true},
+		{name: "modified after", ifModifiedSince: httpTime(lastModified.Add(-time.Second)), lastModified: lastModified},
+		{name: "invalid date", ifModifiedSince: "yesterday", lastModified: lastModified},
+		{name: "empty feed", ifModifiedSince: httpTime(lastModified), lastModified: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NotModified(tt.ifNoneMatch, tt.ifModifiedSince, etag, tt.lastModified); got != tt.want {
+				t.Errorf("NotModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package jwt
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ALGORITHM_HS256 = "HS256"
+	ALGORITHM_RS256 = "RS256"
+	ALGORITHM_EDDSA = "EdDSA"
+
+	// 未配置密钥时使用的默认密钥，该密钥已公开，只允许在开发模式下使用
+	defaultSecret = "gflksadh8y9saslk"
+)
+
+var Config = NewDefaultJwtCfg()
+
+type JwtConfig struct {
+	Issuer             string      `json:"issuer,omitempty" yaml:"issuer,omitempty"`                         // token签发者，解析时校验
+	AccessTokenExpire  int         `json:"accessTokenExpire,omitempty" yaml:"accessTokenExpire,omitempty"`   // access token有效期，单位: 分钟
+	RefreshTokenExpire int         `json:"refreshTokenExpire,omitempty" yaml:"refreshTokenExpire,omitempty"` // refresh token有效期，单位: 小时
+	SigningKid         string      `json:"signingKid,omitempty" yaml:"signingKid,omitempty"`                 // 签发新token使用的密钥kid
+	Keys               []KeyConfig `json:"keys,omitempty" yaml:"keys,omitempty"`                             // 密钥环，轮换时保留旧密钥直到其签发的token全部过期
+	Dev                bool        `json:"dev,omitempty" yaml:"dev,omitempty"`                               // 开发模式，允许使用默认密钥，禁止在生产环境开启
+}
+
+type KeyConfig struct {
+	Kid            string `json:"kid,omitempty" yaml:"kid,omitempty"`                       // 密钥ID，写入token header
+	Algorithm      string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`           // HS256、RS256或EdDSA
+	Secret         string `json:"secret,omitempty" yaml:"secret,omitempty"`                 // HS256密钥
+	PrivateKeyFile string `json:"privateKeyFile,omitempty" yaml:"privateKeyFile,omitempty"` // RS256/EdDSA PEM私钥文件，只用于验证的旧密钥可为空
+	PublicKeyFile  string `json:"publicKeyFile,omitempty" yaml:"publicKeyFile,omitempty"`   // RS256/EdDSA PEM公钥文件，为空时由私钥导出
+}
+
+func NewDefaultJwtCfg() JwtConfig {
+	return JwtConfig{
+		Issuer:             "jwt",
+		AccessTokenExpire:  2 * 60,
+		RefreshTokenExpire: 24 * 10,
+		SigningKid:         "default",
+		Keys: []KeyConfig{
+			{
+				Kid:       "default",
+				Algorithm: ALGORITHM_HS256,
+				Secret:    defaultSecret,
+			},
+		},
+	}
+}
+
+func MustInit(cfg JwtConfig) {
+	if cfg.Issuer == "" {
+		panic("jwt issuer must not be empty")
+	}
+	if cfg.AccessTokenExpire <= 0 || cfg.RefreshTokenExpire <= 0 {
+		panic("jwt token expire must be greater than 0")
+	}
+	if time.Duration(cfg.AccessTokenExpire)*time.Minute > time.Duration(cfg.RefreshTokenExpire)*time.Hour {
+		panic("jwt access token expire must not be longer than refresh token expire")
+	}
+	keys, err := newKeyRing(cfg)
+	if err != nil {
+		panic(err.Error())
+	}
+	for _, key := range cfg.Keys {
+		if key.Secret != defaultSecret {
+			continue
+		}
+		// 默认密钥已公开，使用它签发的token可被任意伪造
+		if !cfg.Dev {
+			panic(fmt.Sprintf("jwt key %s uses the default secret, configure jwt.keys", key.Kid))
+		}
+		zap.L().Warn("JWT key uses the default secret, only allowed in dev mode", zap.String("kid", key.Kid))
+	}
+	Config = cfg
+	ring = keys
+}
+
+func accessTokenLifetime() time.Duration {
+	return time.Duration(Config.AccessTokenExpire) * time.Minute
+}
+
+// MaxTokenLifetime token最长有效期，即refresh token有效期
+func MaxTokenLifetime() time.Duration {
+	return time.Duration(Config.RefreshTokenExpire) * time.Hour
+}
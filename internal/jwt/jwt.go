@@ -7,45 +7,66 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// todo 写入配置文件？
-var (
-	secret               = "gflksadh8y9saslk"
-	accessTokenExpire    = 2                // h
-	refreshTokenExpire   = 24 * 10          // h
-	RefreshTokenInterval = 60 * 60 * 24 * 1 // ms
-	DefaultIssuse        = "jwt"
+// token类型
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
 )
 
+func init() {
+	// 签发时间精确到毫秒，与用户token撤销时间比较，避免与撤销同一秒内签发的旧token仍然有效
+	jwt.TimePrecision = time.Millisecond
+}
+
 type MyClaims struct {
-	UserID int
+	UserID    int
+	Roles     []int32 `json:"roles,omitempty"` // 用户角色，签发时从数据库读取
+	SessionID string  `json:"sid,omitempty"`   // 所属会话ID
+	TokenType string  `json:"typ,omitempty"`   // token类型，access或refresh
+	// 管理员已强制重置密码，只能访问修改密码和登出接口
+	PasswordResetRequired bool `json:"prr,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenToken(issuer string, expireHour int, userId int) (string, error) {
+// 使用当前签名密钥签发token，tokenID为refresh token ID，access token为空
+func GenToken(expire time.Duration, tokenType string, userId int, roles []int32, sessionID string, tokenID string, passwordResetRequired bool) (string, error) {
 	c := MyClaims{
-		UserID: userId,
+		UserID:                userId,
+		Roles:                 roles,
+		SessionID:             sessionID,
+		TokenType:             tokenType,
+		PasswordResetRequired: passwordResetRequired,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    issuer,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expireHour) * time.Hour)),
+			ID:        tokenID,
+			Issuer:    Config.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expire)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
-	return token.SignedString([]byte(secret))
+	signing := ring.signing
+	token := jwt.NewWithClaims(signing.method, c)
+	token.Header["kid"] = signing.kid
+	return token.SignedString(signing.signKey)
+}
+
+func GenAccessToken(userId int, roles []int32, sessionID string, passwordResetRequired bool) (string, error) {
+	return GenToken(accessTokenLifetime(), TokenTypeAccess, userId, roles, sessionID, "", passwordResetRequired)
 }
 
-func GenAccessToken(issuer string, userId int) (string, error) {
-	return GenToken(issuer, accessTokenExpire, userId)
+// refresh token不携带角色，刷新时重新从数据库读取；refreshID用于轮换和重放检测
+func GenRefreshToken(userId int, sessionID string, refreshID string, passwordResetRequired bool) (string, error) {
+	return GenToken(MaxTokenLifetime(), TokenTypeRefresh, userId, nil, sessionID, refreshID, passwordResetRequired)
 }
 
-func GenRefreshToken(issuer string, userId int) (string, error) {
-	return GenToken(issuer, refreshTokenExpire, userId)
+// IsRefreshToken 是否为refresh token，未携带类型的旧token按是否有refresh token ID判断
+func (c *MyClaims) IsRefreshToken() bool {
+	return c.TokenType == TokenTypeRefresh || c.ID != ""
 }
 
 func ParseToken(tokenString string) (*MyClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &MyClaims{}, func(t *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+	keys := ring
+	token, err := jwt.ParseWithClaims(tokenString, &MyClaims{}, keys.verifyKey,
+		jwt.WithValidMethods(keys.validMethods()), jwt.WithIssuer(Config.Issuer))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 默认配置只包含HS256密钥，不会加载失败
+var ring, _ = newKeyRing(Config)
+
+type key struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   interface{} // 为空表示只用于验证
+	verifyKey interface{}
+}
+
+type keyRing struct {
+	signing *key
+	keys    map[string]*key
+	ordered []*key // 按配置顺序，用于未携带kid的token
+}
+
+func newKeyRing(cfg JwtConfig) (*keyRing, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("jwt keys must not be empty")
+	}
+	r := &keyRing{keys: make(map[string]*key, len(cfg.Keys))}
+	for _, keyCfg := range cfg.Keys {
+		if keyCfg.Kid == "" {
+			return nil, errors.New("jwt key kid must not be empty")
+		}
+		if _, ok := r.keys[keyCfg.Kid]; ok {
+			return nil, fmt.Errorf("jwt key kid %s is duplicated", keyCfg.Kid)
+		}
+		k, err := loadKey(keyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %w", keyCfg.Kid, err)
+		}
+		r.keys[k.kid] = k
+		r.ordered = append(r.ordered, k)
+	}
+	r.signing = r.keys[cfg.SigningKid]
+	if r.signing == nil {
+		return nil, fmt.Errorf("jwt signing kid %s not found in keys", cfg.SigningKid)
+	}
+	if r.signing.signKey == nil {
+		return nil, fmt.Errorf("jwt signing key %s has no private key", cfg.SigningKid)
+	}
+	return r, nil
+}
+
+func loadKey(cfg KeyConfig) (*key, error) {
+	k := &key{kid: cfg.Kid}
+	switch cfg.Algorithm {
+	case ALGORITHM_HS256:
+		if cfg.Secret == "" {
+			return nil, errors.New("secret must not be empty")
+		}
+		k.method = jwt.SigningMethodHS256
+		k.signKey = []byte(cfg.Secret)
+		k.verifyKey = []byte(cfg.Secret)
+	case ALGORITHM_RS256:
+		k.method = jwt.SigningMethodRS256
+		if cfg.PrivateKeyFile != "" {
+			data, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			k.signKey = privateKey
+			k.verifyKey = &privateKey.PublicKey
+		}
+		if cfg.PublicKeyFile != "" {
+			data, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			if k.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(data); err != nil {
+				return nil, err
+			}
+		}
+	case ALGORITHM_EDDSA:
+		k.method = jwt.SigningMethodEdDSA
+		if cfg.PrivateKeyFile != "" {
+			data, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			k.signKey = privateKey
+			k.verifyKey = privateKey.(crypto.Signer).Public()
+		}
+		if cfg.PublicKeyFile != "" {
+			data, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			if k.verifyKey, err = jwt.ParseEdPublicKeyFromPEM(data); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("algorithm %s not supported", cfg.Algorithm)
+	}
+	if k.verifyKey == nil {
+		return nil, errors.New("private key file or public key file must be configured")
+	}
+	return k, nil
+}
+
+// verifyKey 按token header中的kid选择验证密钥，未携带kid时使用第一个算法一致的密钥
+func (r *keyRing) verifyKey(t *jwt.Token) (interface{}, error) {
+	alg := t.Method.Alg()
+	if kid, ok := t.Header["kid"].(string); ok {
+		k := r.keys[kid]
+		if k == nil {
+			return nil, fmt.Errorf("unknown kid %s", kid)
+		}
+		if k.method.Alg() != alg {
+			return nil, fmt.Errorf("algorithm %s does not match key %s", alg, kid)
+		}
+		return k.verifyKey, nil
+	}
+	for _, k := range r.ordered {
+		if k.method.Alg() == alg {
+			return k.verifyKey, nil
+		}
+	}
+	return nil, fmt.Errorf("no key for algorithm %s", alg)
+}
+
+func (r *keyRing) validMethods() []string {
+	methods := []string{}
+	seen := map[string]bool{}
+	for _, k := range r.ordered {
+		if !seen[k.method.Alg()] {
+			seen[k.method.Alg()] = true
+			methods = append(methods, k.method.Alg())
+		}
+	}
+	return methods
+}
+
+// JWK 公钥的JSON Web Key表示
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP曲线
+	X   string `json:"x,omitempty"`   // OKP公钥
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS 返回密钥环中所有非对称密钥的公钥，不包含HS256密钥
+func JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, k := range ring.ordered {
+		if jwk, ok := toJWK(k); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+func toJWK(k *key) (JWK, bool) {
+	jwk := JWK{Kid: k.kid, Use: "sig", Alg: k.method.Alg()}
+	switch publicKey := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	default:
+		return jwk, false
+	}
+	return jwk, true
+}
@@ -0,0 +1,317 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var errAny = errors.New("any error")
+
+// testKeyFiles 测试用的PEM密钥文件
+type testKeyFiles struct {
+	rsaPrivate, rsaPublic string
+	edPrivate, edPublic   string
+	rsaKey                *rsa.PrivateKey
+	edKey                 ed25519.PrivateKey
+}
+
+func writePEM(t *testing.T, dir, name, typ string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func newTestKeyFiles(t *testing.T) testKeyFiles {
+	t.Helper()
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	files := testKeyFiles{rsaKey: rsaKey, edKey: edKey}
+	for _, item := range []struct {
+		path *string
+		name string
+		typ  string
+		key  interface{}
+	}{
+		{&files.rsaPrivate, "rsa.pem", "PRIVATE KEY", rsaKey},
+		{&files.rsaPublic, "rsa.pub", "PUBLIC KEY", &rsaKey.PublicKey},
+		{&files.edPrivate, "ed25519.pem", "PRIVATE KEY", edKey},
+		{&files.edPublic, "ed25519.pub", "PUBLIC KEY", edKey.Public()},
+	} {
+		var der []byte
+		if item.typ == "PRIVATE KEY" {
+			der, err = x509.MarshalPKCS8PrivateKey(item.key)
+		} else {
+			der, err = x509.MarshalPKIXPublicKey(item.key)
+		}
+		if err != nil {
+			t.Fatalf("marshal %s: %v", item.name, err)
+		}
+		*item.path = writePEM(t, dir, item.name, item.typ, der)
+	}
+	return files
+}
+
+// useKeyRing 使用指定密钥初始化，测试结束后恢复原配置
+func useKeyRing(t *testing.T, signingKid string, keys ...KeyConfig) {
+	t.Helper()
+	oldConfig, oldRing := Config, ring
+	t.Cleanup(func() {
+		Config, ring = oldConfig, oldRing
+	})
+	cfg := NewDefaultJwtCfg()
+	cfg.SigningKid = signingKid
+	cfg.Keys = keys
+	MustInit(cfg)
+}
+
+func TestNewKeyRing(t *testing.T) {
+	files := newTestKeyFiles(t)
+	hs := KeyConfig{Kid: "hs", Algorithm: ALGORITHM_HS256, Secret: "secret"}
+	tests := []struct {
+		name       string
+		signingKid string
+		keys       []KeyConfig
+		wantErr    error
+	}{
+		{name: "hs256", signingKid: "hs", keys: []KeyConfig{hs}},
+		{name: "rs256", signingKid: "rs", keys: []KeyConfig{{Kid: "rs", Algorithm: ALGORITHM_RS256, PrivateKeyFile: files.rsaPrivate}}},
+		{name: "eddsa", signingKid: "ed", keys: []KeyConfig{{Kid: "ed", Algorithm: ALGORITHM_EDDSA, PrivateKeyFile: files.edPrivate}}},
+		{name: "verify only key", signingKid: "hs", keys: []KeyConfig{hs, {Kid: "rs", Algorithm: ALGORITHM_RS256, PublicKeyFile: files.rsaPublic}}},
+		{name: "empty keys", signingKid: "hs", wantErr: errAny},
+		{name: "empty kid", signingKid: "", keys: []KeyConfig{{Algorithm: ALGORITHM_HS256, Secret: "secret"}}, wantErr: errAny},
+		{name: "duplicated kid", signingKid: "hs", keys: []KeyConfig{hs, hs}, wantErr: errAny},
+		{name: "unsupported algorithm", signingKid: "es", keys: []KeyConfig{{Kid: "es", Algorithm: "ES256", Secret: "secret"}}, wantErr: errAny},
+		{name: "hs256 without secret", signingKid: "hs", keys: []KeyConfig{{Kid: "hs", Algorithm: ALGORITHM_HS256}}, wantErr: errAny},
+		{name: "rs256 without key file", signingKid: "rs", keys: []KeyConfig{{Kid: "rs", Algorithm: ALGORITHM_RS256}}, wantErr: errAny},
+		{name: "eddsa with rsa key file", signingKid: "ed", keys: []KeyConfig{{Kid: "ed", Algorithm: ALGORITHM_EDDSA, PrivateKeyFile: files.rsaPrivate}}, wantErr: errAny},
+		{name: "signing kid not found", signingKid: "missing", keys: []KeyConfig{hs}, wantErr: errAny},
+		{name: "signing key without private key", signingKid: "rs", keys: []KeyConfig{hs, {Kid: "rs", Algorithm: ALGORITHM_RS256, PublicKeyFile: files.rsaPublic}}, wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newKeyRing(JwtConfig{SigningKid: tt.signingKid, Keys: tt.keys})
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("newKeyRing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyKey(t *testing.T) {
+	files := newTestKeyFiles(t)
+	r, err := newKeyRing(JwtConfig{
+		SigningKid: "rs",
+		Keys: []KeyConfig{
+			{Kid: "hs-old", Algorithm: ALGORITHM_HS256, Secret: "old"},
+			{Kid: "hs-new", Algorithm: ALGORITHM_HS256, Secret: "new"},
+			{Kid: "rs", Algorithm: ALGORITHM_RS256, PrivateKeyFile: files.rsaPrivate},
+			{Kid: "ed", Algorithm: ALGORITHM_EDDSA, PublicKeyFile: files.edPublic},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newKeyRing() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		kid     interface{}
+		want    interface{}
+		wantErr error
+	}{
+		{name: "hs256 by kid", method: jwt.SigningMethodHS256, kid: "hs-new", want: []byte("new")},
+		{name: "rs256 by kid", method: jwt.SigningMethodRS256, kid: "rs", want: &files.rsaKey.PublicKey},
+		{name: "eddsa by kid", method: jwt.SigningMethodEdDSA, kid: "ed", want: files.edKey.Public()},
+		{name: "without kid uses first key of algorithm", method: jwt.SigningMethodHS256, want: []byte("old")},
+		{name: "unknown kid", method: jwt.SigningMethodHS256, kid: "missing", wantErr: errAny},
+		{name: "hs256 token with rs256 kid", method: jwt.SigningMethodHS256, kid: "rs", wantErr: errAny},
+		{name: "rs256 token with eddsa kid", method: jwt.SigningMethodRS256, kid: "ed", wantErr: errAny},
+		{name: "without kid and no key of algorithm", method: jwt.SigningMethodES256, wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &jwt.Token{Method: tt.method, Header: map[string]interface{}{"alg": tt.method.Alg()}}
+			if tt.kid != nil {
+				token.Header["kid"] = tt.kid
+			}
+			got, err := r.verifyKey(token)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("verifyKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !keyEqual(got, tt.want) {
+				t.Errorf("verifyKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	methods := r.validMethods()
+	if len(methods) != 3 || methods[0] != "HS256" || methods[1] != "RS256" || methods[2] != "EdDSA" {
+		t.Errorf("validMethods() = %v, want [HS256 RS256 EdDSA]", methods)
+	}
+}
+
+func keyEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case []byte:
+		b, ok := b.([]byte)
+		return ok && string(a) == string(b)
+	case interface{ Equal(crypto.PublicKey) bool }:
+		return a.Equal(b)
+	}
+	return false
+}
+
+// TestKeyRotation 轮换签名密钥后，新token使用新kid签发，旧密钥签发的token在旧密钥保留期间仍可验证
+func TestKeyRotation(t *testing.T) {
+	files := newTestKeyFiles(t)
+	hs := KeyConfig{Kid: "hs", Algorithm: ALGORITHM_HS256, Secret: "secret"}
+	rs := KeyConfig{Kid: "rs", Algorithm: ALGORITHM_RS256, PrivateKeyFile: files.rsaPrivate}
+	ed := KeyConfig{Kid: "ed", Algorithm: ALGORITHM_EDDSA, PrivateKeyFile: files.edPrivate}
+	edPublic := KeyConfig{Kid: "ed", Algorithm: ALGORITHM_EDDSA, PublicKeyFile: files.edPublic}
+
+	signed := map[string]string{}
+	for _, step := range []struct {
+		signingKid string
+		keys       []KeyConfig
+	}{
+		{signingKid: "hs", keys: []KeyConfig{hs}},
+		{signingKid: "ed", keys: []KeyConfig{hs, ed}},
+		{signingKid: "rs", keys: []KeyConfig{hs, edPublic, rs}},
+	} {
+		useKeyRing(t, step.signingKid, step.keys...)
+		token, err := GenAccessToken(1, nil, "s1", false)
+		if err != nil {
+			t.Fatalf("GenAccessToken() with %s error = %v", step.signingKid, err)
+		}
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &MyClaims{})
+		if err != nil {
+			t.Fatalf("ParseUnverified() error = %v", err)
+		}
+		if parsed.Header["kid"] != step.signingKid {
+			t.Errorf("token kid = %v, want %s", parsed.Header["kid"], step.signingKid)
+		}
+		signed[step.signingKid] = token
+	}
+
+	tests := []struct {
+		name    string
+		kid     string
+		keys    []KeyConfig
+		wantErr error
+	}{
+		{name: "hs256 token verified by kept old key", kid: "hs", keys: []KeyConfig{hs, edPublic, rs}},
+		{name: "eddsa token verified by kept public key", kid: "ed", keys: []KeyConfig{hs, edPublic, rs}},
+		{name: "rs256 token verified by signing key", kid: "rs", keys: []KeyConfig{hs, edPublic, rs}},
+		{name: "hs256 token after old key removed", kid: "hs", keys: []KeyConfig{edPublic, rs}, wantErr: errAny},
+		{name: "eddsa token after old key removed", kid: "ed", keys: []KeyConfig{hs, rs}, wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useKeyRing(t, "rs", tt.keys...)
+			claims, err := ParseToken(signed[tt.kid])
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("ParseToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && claims.UserID != 1 {
+				t.Errorf("claims.UserID = %d, want 1", claims.UserID)
+			}
+		})
+	}
+}
+
+// TestParseTokenForged 伪造kid或算法的token无法通过验证
+func TestParseTokenForged(t *testing.T) {
+	files := newTestKeyFiles(t)
+	useKeyRing(t, "rs", KeyConfig{Kid: "rs", Algorithm: ALGORITHM_RS256, PrivateKeyFile: files.rsaPrivate})
+	publicPEM, err := os.ReadFile(files.rsaPublic)
+	if err != nil {
+		t.Fatalf("read public key: %v", err)
+	}
+	claims := MyClaims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{Issuer: Config.Issuer}}
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		kid     string
+		signKey interface{}
+	}{
+		// 使用公开的RSA公钥作为HMAC密钥伪造token
+		{name: "hs256 signed with rsa public key", method: jwt.SigningMethodHS256, kid: "rs", signKey: publicPEM},
+		{name: "unknown kid", method: jwt.SigningMethodRS256, kid: "missing", signKey: files.rsaKey},
+		{name: "eddsa with rs256 kid", method: jwt.SigningMethodEdDSA, kid: "rs", signKey: files.edKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(tt.method, claims)
+			token.Header["kid"] = tt.kid
+			signed, err := token.SignedString(tt.signKey)
+			if err != nil {
+				t.Fatalf("SignedString() error = %v", err)
+			}
+			if _, err := ParseToken(signed); err == nil {
+				t.Error("ParseToken() accepted forged token")
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	files := newTestKeyFiles(t)
+	useKeyRing(t, "rs",
+		KeyConfig{Kid: "hs", Algorithm: ALGORITHM_HS256, Secret: "secret"},
+		KeyConfig{Kid: "rs", Algorithm: ALGORITHM_RS256, PrivateKeyFile: files.rsaPrivate},
+		KeyConfig{Kid: "ed", Algorithm: ALGORITHM_EDDSA, PublicKeyFile: files.edPublic},
+	)
+	set := JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("len(JWKS().Keys) = %d, want 2 without hs256 key", len(set.Keys))
+	}
+
+	rsaJWK, edJWK := set.Keys[0], set.Keys[1]
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"rsa kid", rsaJWK.Kid, "rs"},
+		{"rsa kty", rsaJWK.Kty, "RSA"},
+		{"rsa alg", rsaJWK.Alg, "RS256"},
+		{"rsa use", rsaJWK.Use, "sig"},
+		{"rsa n", rsaJWK.N, base64.RawURLEncoding.EncodeToString(files.rsaKey.N.Bytes())},
+		{"rsa e", rsaJWK.E, base64.RawURLEncoding.EncodeToString(big.NewInt(int64(files.rsaKey.E)).Bytes())},
+		{"ed kid", edJWK.Kid, "ed"},
+		{"ed kty", edJWK.Kty, "OKP"},
+		{"ed alg", edJWK.Alg, "EdDSA"},
+		{"ed crv", edJWK.Crv, "Ed25519"},
+		{"ed x", edJWK.X, base64.RawURLEncoding.EncodeToString(files.edKey.Public().(ed25519.PublicKey))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// RevokeUserTokens 使用户在此之前签发的所有token失效并删除用户的所有会话，记录保留到最长的token过期
+func RevokeUserTokens(ctx context.Context, userIDs ...int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	for _, userID := range userIDs {
+		if err := DeleteUserSessions(ctx, int(userID), ""); err != nil {
+			return err
+		}
+	}
+	now := time.Now().UnixMilli()
+	pipe := cache.Client.Pipeline()
+	for _, userID := range userIDs {
+		pipe.Set(ctx, fmt.Sprintf(utils.USER_TOKEN_REVOKE_KEY_TEMPLATE, userID), now, MaxTokenLifetime())
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// IsUserTokenRevoked token是否签发于用户token撤销之前
+// 撤销后签发的token都携带会话ID，未携带会话ID的token为启用会话管理前签发，存在撤销记录时直接视为已撤销
+func IsUserTokenRevoked(ctx context.Context, claims *MyClaims) (bool, error) {
+	revokedAt, err := cache.Client.Get(ctx, fmt.Sprintf(utils.USER_TOKEN_REVOKE_KEY_TEMPLATE, claims.UserID)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+	if claims.SessionID == "" || claims.IssuedAt == nil {
+		return true, nil
+	}
+	return claims.IssuedAt.UnixMilli() < revokedAt, nil
+}
@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+func TestRevokeUserTokens(t *testing.T) {
+	server := setupRedis(t)
+	ctx := context.Background()
+	createTestSession(t, 1, "s1", "r1", 100)
+	createTestSession(t, 1, "s2", "r2", 100)
+	createTestSession(t, 2, "s3", "r3", 100)
+
+	before := time.Now()
+	if err := RevokeUserTokens(ctx, 1); err != nil {
+		t.Fatalf("RevokeUserTokens() error = %v", err)
+	}
+	for _, id := range []string{"s1", "s2"} {
+		if _, err := GetSession(ctx, id); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("GetSession(%s) error = %v, want %v", id, err, ErrSessionNotFound)
+		}
+	}
+	if _, err := GetSession(ctx, "s3"); err != nil {
+		t.Errorf("session of other user deleted: %v", err)
+	}
+	key := fmt.Sprintf(utils.USER_TOKEN_REVOKE_KEY_TEMPLATE, 1)
+	revokedAt, err := cache.Client.Get(ctx, key).Int64()
+	if err != nil {
+		t.Fatalf("get revoke record: %v", err)
+	}
+	if revokedAt < before.UnixMilli() || revokedAt > time.Now().UnixMilli() {
+		t.Errorf("revoke record = %d, want millisecond timestamp between %d and now", revokedAt, before.UnixMilli())
+	}
+	if ttl := server.TTL(key); ttl != MaxTokenLifetime() {
+		t.Errorf("revoke record ttl = %v, want %v", ttl, MaxTokenLifetime())
+	}
+	if err := RevokeUserTokens(ctx); err != nil {
+		t.Errorf("RevokeUserTokens() without users error = %v", err)
+	}
+}
+
+func TestIsUserTokenRevoked(t *testing.T) {
+	setupRedis(t)
+	ctx := context.Background()
+	revokedAt := time.UnixMilli(time.Now().UnixMilli())
+	if err := cache.Client.Set(ctx, fmt.Sprintf(utils.USER_TOKEN_REVOKE_KEY_TEMPLATE, 1), revokedAt.UnixMilli(), time.Hour).Err(); err != nil {
+		t.Fatalf("set revoke record: %v", err)
+	}
+	claims := func(userID int, sessionID string, issuedAt time.Time) *MyClaims {
+		c := &MyClaims{UserID: userID, SessionID: sessionID}
+		if !issuedAt.IsZero() {
+			c.IssuedAt = jwt.NewNumericDate(issuedAt)
+		}
+		return c
+	}
+	tests := []struct {
+		name   string
+		claims *MyClaims
+		want   bool
+	}{
+		{name: "issued long before revoke", claims: claims(1, "s1", revokedAt.Add(-time.Hour)), want: true},
+		{name: "issued in the revoke second before revoke", claims: claims(1, "s1", revokedAt.Add(-time.Millisecond)), want: true},
+		{name: "issued at revoke", claims: claims(1, "s1", revokedAt), want: false},
+		{name: "issued after revoke", claims: claims(1, "s1", revokedAt.Add(time.Second)), want: false},
+		{name: "token without session", claims: claims(1, "", revokedAt.Add(time.Second)), want: true},
+		{name: "token without issued time", claims: claims(1, "s1", time.Time{}), want: true},
+		{name: "user not revoked", claims: claims(2, "s1", revokedAt.Add(-time.Hour)), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsUserTokenRevoked(ctx, tt.claims)
+			if err != nil {
+				t.Fatalf("IsUserTokenRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsUserTokenRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRevokeSignedToken 签发时间精确到毫秒，撤销前同一秒内签发的token解析后仍判定为已撤销
+func TestRevokeSignedToken(t *testing.T) {
+	setupRedis(t)
+	useKeyRing(t, "hs", KeyConfig{Kid: "hs", Algorithm: ALGORITHM_HS256, Secret: "secret"})
+	ctx := context.Background()
+
+	before, err := GenAccessToken(1, nil, "s1", false)
+	if err != nil {
+		t.Fatalf("GenAccessToken() error = %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if err := RevokeUserTokens(ctx, 1); err != nil {
+		t.Fatalf("RevokeUserTokens() error = %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	after, err := GenAccessToken(1, nil, "s2", false)
+	if err != nil {
+		t.Fatalf("GenAccessToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{name: "issued before revoke", token: before, want: true},
+		{name: "issued after revoke", token: after, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseToken(tt.token)
+			if err != nil {
+				t.Fatalf("ParseToken() error = %v", err)
+			}
+			got, err := IsUserTokenRevoked(ctx, claims)
+			if err != nil {
+				t.Fatalf("IsUserTokenRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsUserTokenRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
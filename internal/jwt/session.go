@@ -0,0 +1,245 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// Session 服务端会话，每次登录创建一个，会话ID同时作为refresh token族ID
+type Session struct {
+	ID             string `redis:"id"`
+	UserID         int    `redis:"user_id"`
+	Device         string `redis:"device"`
+	UserAgent      string `redis:"user_agent"`
+	IP             string `redis:"ip"`
+	RefreshID      string `redis:"refresh_id"`       // 当前有效的refresh token ID，轮换后旧token失效
+	CreatedTime    int64  `redis:"created_time"`     // 毫秒时间戳
+	LastActiveTime int64  `redis:"last_active_time"` // 最近一次刷新token的毫秒时间戳
+}
+
+// 轮换refresh token，旧ID与当前ID不一致说明已轮换的token被重放，删除整个会话
+var rotateSessionScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'refresh_id')
+if not current then
+	return -1
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	redis.call('SREM', KEYS[2], ARGV[6])
+	return 0
+end
+redis.call('HSET', KEYS[1], 'refresh_id', ARGV[2], 'ip', ARGV[3], 'last_active_time', ARGV[4])
+redis.call('EXPIRE', KEYS[1], ARGV[5])
+redis.call('EXPIRE', KEYS[2], ARGV[5])
+return 1
+`)
+
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf(utils.USER_SESSION_KEY_TEMPLATE, sessionID)
+}
+
+func userSessionsKey(userID int) string {
+	return fmt.Sprintf(utils.USER_SESSIONS_KEY_TEMPLATE, userID)
+}
+
+// NewTokenID 生成会话ID或refresh token ID
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateSession 记录新会话，有效期与refresh token一致
+func CreateSession(ctx context.Context, session *Session) error {
+	ttl := MaxTokenLifetime()
+	pipe := cache.Client.TxPipeline()
+	pipe.HSet(ctx, sessionKey(session.ID), session)
+	pipe.Expire(ctx, sessionKey(session.ID), ttl)
+	pipe.SAdd(ctx, userSessionsKey(session.UserID), session.ID)
+	pipe.Expire(ctx, userSessionsKey(session.UserID), ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetSession 查询会话，不存在时返回ErrSessionNotFound
+func GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	var session Session
+	result := cache.Client.HGetAll(ctx, sessionKey(sessionID))
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	if len(result.Val()) == 0 {
+		return nil, ErrSessionNotFound
+	}
+	if err := result.Scan(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListUserSessions 查询用户的有效会话，按最近活跃时间倒序
+func ListUserSessions(ctx context.Context, userID int) ([]Session, error) {
+	sessionIDs, err := cache.Client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]Session, 0, len(sessionIDs))
+	expired := []interface{}{}
+	for _, sessionID := range sessionIDs {
+		session, err := GetSession(ctx, sessionID)
+		if err != nil {
+			if errors.Is(err, ErrSessionNotFound) {
+				expired = append(expired, sessionID)
+				continue
+			}
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	// 清理已过期的会话ID
+	if len(expired) > 0 {
+		if err := cache.Client.SRem(ctx, userSessionsKey(userID), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActiveTime > sessions[j].LastActiveTime
+	})
+	return sessions, nil
+}
+
+// RotateSession 将会话的refresh token ID由oldRefreshID替换为newRefreshID
+// 会话不存在返回ErrSessionNotFound，oldRefreshID已被轮换过返回ErrRefreshTokenReused并删除整个会话
+func RotateSession(ctx context.Context, userID int, sessionID, oldRefreshID, newRefreshID, ip string) error {
+	session, err := GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	result, err := rotateSessionScript.Run(ctx, cache.Client,
+		[]string{sessionKey(sessionID), userSessionsKey(userID)},
+		oldRefreshID, newRefreshID, ip, time.Now().UnixMilli(), int64(MaxTokenLifetime().Seconds()), sessionID).Int()
+	if err != nil {
+		return err
+	}
+	switch result {
+	case -1:
+		return ErrSessionNotFound
+	case 0:
+		return ErrRefreshTokenReused
+	}
+	return nil
+}
+
+// DeleteSession 删除用户的指定会话，会话不存在或不属于该用户时返回ErrSessionNotFound
+func DeleteSession(ctx context.Context, userID int, sessionID string) error {
+	session, err := GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	pipe := cache.Client.TxPipeline()
+	pipe.Del(ctx, sessionKey(sessionID))
+	pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// DeleteUserSessions 删除用户除exceptSessionID外的所有会话，exceptSessionID为空时全部删除
+func DeleteUserSessions(ctx context.Context, userID int, exceptSessionID string) error {
+	sessionIDs, err := cache.Client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+	pipe := cache.Client.TxPipeline()
+	for _, sessionID := range sessionIDs {
+		if sessionID == exceptSessionID {
+			continue
+		}
+		pipe.Del(ctx, sessionKey(sessionID))
+		pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// IsSessionValid token所属会话是否有效，refresh token还需是会话当前的refresh token
+// 未携带会话ID的token为启用会话管理前签发，不做校验
+func IsSessionValid(ctx context.Context, claims *MyClaims) (bool, error) {
+	if claims.SessionID == "" {
+		return true, nil
+	}
+	refreshID, err := cache.Client.HGet(ctx, sessionKey(claims.SessionID), "refresh_id").Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+	if claims.ID != "" && claims.ID != refreshID {
+		return false, nil
+	}
+	return true, nil
+}
+
+// ParseDevice 从User-Agent中解析浏览器和操作系统，用于展示会话设备
+func ParseDevice(userAgent string) string {
+	browser := ""
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/") || strings.Contains(userAgent, "Opera"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/") || strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	os := ""
+	switch {
+	case strings.Contains(userAgent, "Windows"):
+		os = "Windows"
+	case strings.Contains(userAgent, "Android"):
+		os = "Android"
+	case strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPad"):
+		os = "iOS"
+	case strings.Contains(userAgent, "Mac OS X"):
+		os = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		os = "Linux"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	}
+	return "Unknown"
+}
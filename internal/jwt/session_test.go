@@ -0,0 +1,272 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// setupRedis 使用miniredis替换全局redis客户端，测试结束后恢复
+func setupRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	old := cache.Client
+	cache.Client = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		cache.Client.Close()
+		cache.Client = old
+	})
+	return server
+}
+
+func createTestSession(t *testing.T, userID int, sessionID, refreshID string, lastActive int64) {
+	t.Helper()
+	if err := CreateSession(context.Background(), &Session{
+		ID:             sessionID,
+		UserID:         userID,
+		RefreshID:      refreshID,
+		CreatedTime:    lastActive,
+		LastActiveTime: lastActive,
+	}); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+}
+
+func TestGetSession(t *testing.T) {
+	server := setupRedis(t)
+	createTestSession(t, 1, "s1", "r1", 100)
+
+	session, err := GetSession(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if session.UserID != 1 || session.RefreshID != "r1" || session.LastActiveTime != 100 {
+		t.Errorf("GetSession() = %+v", session)
+	}
+	if ttl := server.TTL(sessionKey("s1")); ttl != MaxTokenLifetime() {
+		t.Errorf("session ttl = %v, want %v", ttl, MaxTokenLifetime())
+	}
+	if _, err := GetSession(context.Background(), "missing"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("GetSession(missing) error = %v, want %v", err, ErrSessionNotFound)
+	}
+}
+
+func TestListUserSessions(t *testing.T) {
+	server := setupRedis(t)
+	ctx := context.Background()
+	createTestSession(t, 1, "old", "r1", 100)
+	createTestSession(t, 1, "new", "r2", 200)
+	createTestSession(t, 1, "expired", "r3", 300)
+	createTestSession(t, 2, "other", "r4", 400)
+	server.Del(sessionKey("expired"))
+
+	sessions, err := ListUserSessions(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListUserSessions() error = %v", err)
+	}
+	var ids []string
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+	if len(ids) != 2 || ids[0] != "new" || ids[1] != "old" {
+		t.Errorf("ListUserSessions() ids = %v, want [new old]", ids)
+	}
+	if ok, _ := server.SIsMember(userSessionsKey(1), "expired"); ok {
+		t.Error("expired session id not removed from user sessions")
+	}
+}
+
+func TestRotateSession(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       int
+		sessionID    string
+		oldRefreshID string
+		wantErr      error
+		// wantSession 轮换后会话是否仍然存在
+		wantSession bool
+	}{
+		{name: "rotate current refresh token", userID: 1, sessionID: "s1", oldRefreshID: "r1", wantSession: true},
+		{name: "replay rotated refresh token revokes family", userID: 1, sessionID: "s1", oldRefreshID: "r0", wantErr: ErrRefreshTokenReused},
+		{name: "unknown session", userID: 1, sessionID: "missing", oldRefreshID: "r1", wantErr: ErrSessionNotFound, wantSession: true},
+		{name: "session of another user", userID: 2, sessionID: "s1", oldRefreshID: "r1", wantErr: ErrSessionNotFound, wantSession: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupRedis(t)
+			ctx := context.Background()
+			createTestSession(t, 1, "s1", "r1", 100)
+
+			err := RotateSession(ctx, tt.userID, tt.sessionID, tt.oldRefreshID, "r2", "127.0.0.1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RotateSession() error = %v, want %v", err, tt.wantErr)
+			}
+			_, getErr := GetSession(ctx, "s1")
+			if exists := getErr == nil; exists != tt.wantSession {
+				t.Fatalf("session exists = %v, want %v (err %v)", exists, tt.wantSession, getErr)
+			}
+			if !tt.wantSession {
+				if ok, _ := server.SIsMember(userSessionsKey(1), "s1"); ok {
+					t.Error("revoked session id not removed from user sessions")
+				}
+			}
+		})
+	}
+}
+
+// TestRotateSessionReplay 轮换后旧refresh token失效，重放旧token撤销整个会话，新token随之失效
+func TestRotateSessionReplay(t *testing.T) {
+	setupRedis(t)
+	ctx := context.Background()
+	createTestSession(t, 1, "s1", "r1", 100)
+
+	if err := RotateSession(ctx, 1, "s1", "r1", "r2", "10.0.0.1"); err != nil {
+		t.Fatalf("first RotateSession() error = %v", err)
+	}
+	session, err := GetSession(ctx, "s1")
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if session.RefreshID != "r2" || session.IP != "10.0.0.1" || session.LastActiveTime <= 100 {
+		t.Errorf("rotated session = %+v", session)
+	}
+
+	steps := []struct {
+		name   string
+		claims *MyClaims
+		want   bool
+	}{
+		{name: "new refresh token valid", claims: refreshClaims(1, "s1", "r2"), want: true},
+		{name: "old refresh token invalid", claims: refreshClaims(1, "s1", "r1"), want: false},
+		{name: "access token valid", claims: &MyClaims{UserID: 1, SessionID: "s1"}, want: true},
+	}
+	for _, step := range steps {
+		if got, err := IsSessionValid(ctx, step.claims); err != nil || got != step.want {
+			t.Errorf("%s: IsSessionValid() = %v, %v, want %v", step.name, got, err, step.want)
+		}
+	}
+
+	if err := RotateSession(ctx, 1, "s1", "r1", "r3", "10.0.0.2"); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("replay RotateSession() error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	for _, claims := range []*MyClaims{refreshClaims(1, "s1", "r2"), {UserID: 1, SessionID: "s1"}} {
+		if got, err := IsSessionValid(ctx, claims); err != nil || got {
+			t.Errorf("IsSessionValid(%+v) after replay = %v, %v, want false", claims, got, err)
+		}
+	}
+}
+
+func TestIsSessionValid(t *testing.T) {
+	setupRedis(t)
+	createTestSession(t, 1, "s1", "r1", 100)
+	tests := []struct {
+		name   string
+		claims *MyClaims
+		want   bool
+	}{
+		{name: "token without session", claims: &MyClaims{UserID: 1}, want: true},
+		{name: "access token", claims: &MyClaims{UserID: 1, SessionID: "s1"}, want: true},
+		{name: "current refresh token", claims: refreshClaims(1, "s1", "r1"), want: true},
+		{name: "rotated refresh token", claims: refreshClaims(1, "s1", "r0"), want: false},
+		{name: "deleted session", claims: &MyClaims{UserID: 1, SessionID: "missing"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsSessionValid(context.Background(), tt.claims)
+			if err != nil {
+				t.Fatalf("IsSessionValid() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsSessionValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteSessions(t *testing.T) {
+	tests := []struct {
+		name      string
+		delete    func(ctx context.Context) error
+		wantErr   error
+		wantAlive []string
+	}{
+		{
+			name:      "delete one",
+			delete:    func(ctx context.Context) error { return DeleteSession(ctx, 1, "s1") },
+			wantAlive: []string{"s2", "s3"},
+		},
+		{
+			name:      "delete session of another user",
+			delete:    func(ctx context.Context) error { return DeleteSession(ctx, 2, "s1") },
+			wantErr:   ErrSessionNotFound,
+			wantAlive: []string{"s1", "s2", "s3"},
+		},
+		{
+			name:      "delete others",
+			delete:    func(ctx context.Context) error { return DeleteUserSessions(ctx, 1, "s2") },
+			wantAlive: []string{"s2", "s3"},
+		},
+		{
+			name:      "delete all",
+			delete:    func(ctx context.Context) error { return DeleteUserSessions(ctx, 1, "") },
+			wantAlive: []string{"s3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupRedis(t)
+			ctx := context.Background()
+			createTestSession(t, 1, "s1", "r1", 100)
+			createTestSession(t, 1, "s2", "r2", 200)
+			createTestSession(t, 2, "s3", "r3", 300)
+
+			if err := tt.delete(ctx); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("delete error = %v, want %v", err, tt.wantErr)
+			}
+			var alive []string
+			for _, id := range []string{"s1", "s2", "s3"} {
+				if _, err := GetSession(ctx, id); err == nil {
+					alive = append(alive, id)
+				}
+			}
+			if len(alive) != len(tt.wantAlive) {
+				t.Fatalf("alive sessions = %v, want %v", alive, tt.wantAlive)
+			}
+			for i := range alive {
+				if alive[i] != tt.wantAlive[i] {
+					t.Fatalf("alive sessions = %v, want %v", alive, tt.wantAlive)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDevice(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36 Edg/120.0", "Edge on Windows"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15", "Safari on macOS"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0 Mobile/15E148 Safari/604.1", "Chrome on iOS"},
+		{"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox on Linux"},
+		{"curl/8.0", "Unknown"},
+		{"", "Unknown"},
+	}
+	for _, tt := range tests {
+		if got := ParseDevice(tt.userAgent); got != tt.want {
+			t.Errorf("ParseDevice(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
+
+func refreshClaims(userID int, sessionID, refreshID string) *MyClaims {
+	claims := &MyClaims{UserID: userID, SessionID: sessionID, TokenType: TokenTypeRefresh}
+	claims.ID = refreshID
+	return claims
+}
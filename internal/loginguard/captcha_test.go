@@ -0,0 +1,45 @@
+package loginguard
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCaptcha(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+	}{
+		{name: "default length", length: 4},
+		{name: "longer", length: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultLoginGuardCfg()
+			cfg.CaptchaLength = tt.length
+			useConfig(t, cfg)
+
+			code, data, err := GenerateCaptcha()
+			if err != nil {
+				t.Fatalf("GenerateCaptcha() error = %v", err)
+			}
+			if len(code) != tt.length {
+				t.Errorf("len(code) = %d, want %d", len(code), tt.length)
+			}
+			for _, ch := range code {
+				if !strings.ContainsRune(captchaChars, ch) {
+					t.Errorf("code %q contains unexpected char %q", code, ch)
+				}
+			}
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("decode png: %v", err)
+			}
+			if size := img.Bounds().Size(); size.X != captchaWidth || size.Y != captchaHeight {
+				t.Errorf("image size = %v, want %dx%d", size, captchaWidth, captchaHeight)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package loginguard
+
+import (
+	"time"
+)
+
+var Config = NewDefaultLoginGuardCfg()
+
+type LoginGuardConfig struct {
+	CaptchaThreshold int `json:"captchaThreshold,omitempty" yaml:"captchaThreshold,omitempty"` // 用户名或IP失败次数达到后要求验证码
+	LockThreshold    int `json:"lockThreshold,omitempty" yaml:"lockThreshold,omitempty"`       // 用户名失败次数达到后锁定
+	IPLockThreshold  int `json:"ipLockThreshold,omitempty" yaml:"ipLockThreshold,omitempty"`   // IP失败次数达到后锁定
+	BaseLock         int `json:"baseLock,omitempty" yaml:"baseLock,omitempty"`                 // 首次锁定时长，之后每次失败翻倍，单位: 秒
+	MaxLock          int `json:"maxLock,omitempty" yaml:"maxLock,omitempty"`                   // 最长锁定时长，单位: 秒
+	FailWindow       int `json:"failWindow,omitempty" yaml:"failWindow,omitempty"`             // 失败计数在最后一次失败后保留的时间，单位: 分钟
+	CaptchaLength    int `json:"captchaLength,omitempty" yaml:"captchaLength,omitempty"`       // 验证码字符数
+	CaptchaExpire    int `json:"captchaExpire,omitempty" yaml:"captchaExpire,omitempty"`       // 验证码有效期，单位: 秒
+}
+
+func NewDefaultLoginGuardCfg() LoginGuardConfig {
+	return LoginGuardConfig{
+		CaptchaThreshold: 3,
+		LockThreshold:    5,
+		IPLockThreshold:  20,
+		BaseLock:         60,
+		MaxLock:          60 * 60,
+		FailWindow:       60,
+		CaptchaLength:    4,
+		CaptchaExpire:    300,
+	}
+}
+
+func MustInit(cfg LoginGuardConfig) {
+	if cfg.CaptchaThreshold <= 0 || cfg.LockThreshold <= 0 || cfg.IPLockThreshold <= 0 {
+		panic("login guard thresholds must be greater than 0")
+	}
+	if cfg.BaseLock <= 0 || cfg.MaxLock < cfg.BaseLock {
+		panic("login guard max lock must be greater than or equal to base lock")
+	}
+	if cfg.FailWindow <= 0 {
+		panic("login guard fail window must be greater than 0")
+	}
+	if cfg.CaptchaLength <= 0 || cfg.CaptchaExpire <= 0 {
+		panic("login guard captcha length and expire must be greater than 0")
+	}
+	Config = cfg
+}
+
+// LockDuration 根据失败次数计算锁定时长，达到阈值时锁定BaseLock，之后每多失败一次翻倍，不超过MaxLock
+// 未达到阈值时返回0
+func LockDuration(fails int64, threshold int) time.Duration {
+	if fails < int64(threshold) {
+		return 0
+	}
+	maxLock := time.Duration(Config.MaxLock) * time.Second
+	lock := time.Duration(Config.BaseLock) * time.Second
+	for i := int64(threshold); i < fails; i++ {
+		lock *= 2
+		if lock >= maxLock {
+			return maxLock
+		}
+	}
+	return lock
+}
+
+// CaptchaRequired 失败次数是否已达到需要验证码的阈值
+func CaptchaRequired(fails ...int64) bool {
+	for _, f := range fails {
+		if f >= int64(Config.CaptchaThreshold) {
+			return true
+		}
+	}
+	return false
+}
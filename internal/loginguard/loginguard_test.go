@@ -0,0 +1,97 @@
+package loginguard
+
+import (
+	"testing"
+	"time"
+)
+
+// useConfig 测试期间替换全局配置，结束后恢复
+func useConfig(t *testing.T, cfg LoginGuardConfig) {
+	t.Helper()
+	old := Config
+	MustInit(cfg)
+	t.Cleanup(func() { Config = old })
+}
+
+func TestLockDuration(t *testing.T) {
+	cfg := NewDefaultLoginGuardCfg()
+	cfg.BaseLock = 60
+	cfg.MaxLock = 600
+	useConfig(t, cfg)
+
+	tests := []struct {
+		name      string
+		fails     int64
+		threshold int
+		want      time.Duration
+	}{
+		{name: "no failure", fails: 0, threshold: 5, want: 0},
+		{name: "below threshold", fails: 4, threshold: 5, want: 0},
+		{name: "reach threshold", fails: 5, threshold: 5, want: time.Minute},
+		{name: "one more doubles", fails: 6, threshold: 5, want: 2 * time.Minute},
+		{name: "two more doubles again", fails: 7, threshold: 5, want: 4 * time.Minute},
+		{name: "capped at max lock", fails: 9, threshold: 5, want: 10 * time.Minute},
+		{name: "huge fails stay capped", fails: 1 << 40, threshold: 5, want: 10 * time.Minute},
+		{name: "ip threshold", fails: 20, threshold: 20, want: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LockDuration(tt.fails, tt.threshold); got != tt.want {
+				t.Errorf("LockDuration(%d, %d) = %v, want %v", tt.fails, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptchaRequired(t *testing.T) {
+	cfg := NewDefaultLoginGuardCfg()
+	cfg.CaptchaThreshold = 3
+	useConfig(t, cfg)
+
+	tests := []struct {
+		name  string
+		fails []int64
+		want  bool
+	}{
+		{name: "no counters", fails: nil, want: false},
+		{name: "below threshold", fails: []int64{2, 2}, want: false},
+		{name: "username reaches threshold", fails: []int64{3, 0}, want: true},
+		{name: "ip reaches threshold", fails: []int64{0, 5}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CaptchaRequired(tt.fails...); got != tt.want {
+				t.Errorf("CaptchaRequired(%v) = %v, want %v", tt.fails, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustInit(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(cfg *LoginGuardConfig)
+		wantPanic bool
+	}{
+		{name: "default", modify: func(*LoginGuardConfig) {}},
+		{name: "zero captcha threshold", modify: func(cfg *LoginGuardConfig) { cfg.CaptchaThreshold = 0 }, wantPanic: true},
+		{name: "zero ip lock threshold", modify: func(cfg *LoginGuardConfig) { cfg.IPLockThreshold = 0 }, wantPanic: true},
+		{name: "max lock below base lock", modify: func(cfg *LoginGuardConfig) { cfg.MaxLock = cfg.BaseLock - 1 }, wantPanic: true},
+		{name: "zero fail window", modify: func(cfg *LoginGuardConfig) { cfg.FailWindow = 0 }, wantPanic: true},
+		{name: "zero captcha length", modify: func(cfg *LoginGuardConfig) { cfg.CaptchaLength = 0 }, wantPanic: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := Config
+			t.Cleanup(func() { Config = old })
+			cfg := NewDefaultLoginGuardCfg()
+			tt.modify(&cfg)
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("MustInit() panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+			MustInit(cfg)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// 连接SMTP服务器的超时时间
+const dialTimeout = 10 * time.Second
+
+var (
+	Config = NewDefaultMailCfg()
+	// Default 默认邮件发送实现，未配置SMTP服务器时发送返回ErrNotConfigured
+	Default Mailer = NewSMTPMailer(Config)
+
+	ErrNotConfigured = errors.New("mail server is not configured")
+)
+
+type MailConfig struct {
+	Host        string `json:"host,omitempty" yaml:"host,omitempty"`               // SMTP服务器地址，为空表示不发送邮件
+	Port        int    `json:"port,omitempty" yaml:"port,omitempty"`               // SMTP服务器端口
+	Username    string `json:"username,omitempty" yaml:"username,omitempty"`       // 认证用户名，为空表示不认证
+	Password    string `json:"password,omitempty" yaml:"password,omitempty"`       // 认证密码
+	From        string `json:"from,omitempty" yaml:"from,omitempty"`               // 发件人地址
+	ImplicitTLS bool   `json:"implicitTLS,omitempty" yaml:"implicitTLS,omitempty"` // 是否使用隐式TLS(如465端口)，否则在服务器支持时使用STARTTLS
+	ResetExpire int    `json:"resetExpire,omitempty" yaml:"resetExpire,omitempty"` // 密码重置链接有效期，单位: 分钟
+}
+
+func NewDefaultMailCfg() MailConfig {
+	return MailConfig{
+		Host:        "",
+		Port:        587,
+		ResetExpire: 30,
+	}
+}
+
+func MustInit(cfg MailConfig) {
+	if cfg.Host != "" && (cfg.Port <= 0 || cfg.From == "") {
+		panic("mail port and from must be set when host is set")
+	}
+	if cfg.ResetExpire <= 0 {
+		panic("mail reset expire must be greater than 0")
+	}
+	Config = cfg
+	Default = NewSMTPMailer(cfg)
+}
+
+// Message 纯文本邮件
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type smtpMailer struct {
+	cfg MailConfig
+}
+
+func NewSMTPMailer(cfg MailConfig) Mailer {
+	return &smtpMailer{cfg: cfg}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	if m.cfg.Host == "" {
+		return ErrNotConfigured
+	}
+	if len(msg.To) == 0 {
+		return errors.New("mail recipient is empty")
+	}
+	data, err := buildMessage(m.cfg.From, msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: m.cfg.Host}
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	if m.cfg.ImplicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !m.cfg.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage 生成邮件内容，正文使用base64编码
+func buildMessage(from string, msg Message) ([]byte, error) {
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	for _, to := range msg.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, fmt.Errorf("invalid recipient address: %w", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	for _, to := range msg.To {
+		fmt.Fprintf(&buf, "To: %s\r\n", to)
+	}
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	// base64正文每行不超过76个字符
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,287 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"mime"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// received 假SMTP服务器收到的一封邮件
+type received struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer 基于net.Listen的SMTP服务器，只实现发送邮件需要的命令，不支持STARTTLS
+type fakeSMTPServer struct {
+	t        *testing.T
+	listener net.Listener
+	// rejectRcpt 拒绝的收件人
+	rejectRcpt string
+	mails      chan received
+}
+
+func newFakeSMTPServer(t *testing.T, rejectRcpt string) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTPServer{t: t, listener: listener, rejectRcpt: rejectRcpt, mails: make(chan received, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	tp := textproto.NewConn(conn)
+	reply := func(format string, args ...any) bool {
+		return tp.PrintfLine(format, args...) == nil
+	}
+
+	var mail received
+	if !reply("220 localhost ESMTP") {
+		return
+	}
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			if !reply("250-localhost") || !reply("250-AUTH PLAIN") || !reply("250 8BITMIME") {
+				return
+			}
+		case "AUTH":
+			_, encoded, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(encoded)
+			mail.auth = string(decoded)
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			// 去掉BODY=8BITMIME等参数
+			from, _, _ := strings.Cut(strings.TrimPrefix(arg, "FROM:"), " ")
+			mail.from = strings.Trim(from, "<>")
+			reply("250 OK")
+		case "RCPT":
+			to := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			if to == s.rejectRcpt {
+				reply("550 5.1.1 mailbox unavailable")
+				continue
+			}
+			mail.to = append(mail.to, to)
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			mail.data = strings.Join(lines, "\n")
+			reply("250 OK")
+			s.mails <- mail
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        func(port int) MailConfig
+		msg        Message
+		rejectRcpt string
+		wantAuth   string
+		wantErr    error
+	}{
+		{
+			name: "plain auth",
+			cfg: func(port int) MailConfig {
+				return MailConfig{Host: "127.0.0.1", Port: port, Username: "user", Password: "pass", From: "blog@example.com"}
+			},
+			msg:      Message{To: []string{"alice@example.com", "bob@example.com"}, Subject: "重置密码", Body: "您好"},
+			wantAuth: "\x00user\x00pass",
+		},
+		{
+			name: "without auth",
+			cfg: func(port int) MailConfig {
+				return MailConfig{Host: "127.0.0.1", Port: port, From: "blog@example.com"}
+			},
+			msg: Message{To: []string{"alice@example.com"}, Subject: "subject", Body: "body"},
+		},
+		{
+			name: "recipient rejected",
+			cfg: func(port int) MailConfig {
+				return MailConfig{Host: "127.0.0.1", Port: port, From: "blog@example.com"}
+			},
+			msg:        Message{To: []string{"nobody@example.com"}, Subject: "subject", Body: "body"},
+			rejectRcpt: "nobody@example.com",
+			wantErr:    errAny,
+		},
+		{
+			name: "not configured",
+			cfg: func(int) MailConfig {
+				return MailConfig{}
+			},
+			msg:     Message{To: []string{"alice@example.com"}},
+			wantErr: ErrNotConfigured,
+		},
+		{
+			name: "empty recipient",
+			cfg: func(port int) MailConfig {
+				return MailConfig{Host: "127.0.0.1", Port: port, From: "blog@example.com"}
+			},
+			msg:     Message{},
+			wantErr: errAny,
+		},
+		{
+			name: "invalid recipient",
+			cfg: func(port int) MailConfig {
+				return MailConfig{Host: "127.0.0.1", Port: port, From: "blog@example.com"}
+			},
+			msg:     Message{To: []string{"not an address"}},
+			wantErr: errAny,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSMTPServer(t, tt.rejectRcpt)
+			cfg := tt.cfg(server.port())
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err := NewSMTPMailer(cfg).Send(ctx, tt.msg)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatal("Send() error = nil, want error")
+				}
+				if tt.wantErr != errAny && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Send() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			var got received
+			select {
+			case got = <-server.mails:
+			case <-ctx.Done():
+				t.Fatal("fake smtp server received no mail")
+			}
+			if got.auth != tt.wantAuth {
+				t.Errorf("auth = %q, want %q", got.auth, tt.wantAuth)
+			}
+			if got.from != cfg.From {
+				t.Errorf("from = %q, want %q", got.from, cfg.From)
+			}
+			if strings.Join(got.to, ",") != strings.Join(tt.msg.To, ",") {
+				t.Errorf("to = %v, want %v", got.to, tt.msg.To)
+			}
+			subject, body := parseMessage(t, got.data)
+			if subject != tt.msg.Subject {
+				t.Errorf("subject = %q, want %q", subject, tt.msg.Subject)
+			}
+			if body != tt.msg.Body {
+				t.Errorf("body = %q, want %q", body, tt.msg.Body)
+			}
+		})
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		msg     Message
+		wantErr bool
+	}{
+		{name: "short body", from: "blog@example.com", msg: Message{To: []string{"a@example.com"}, Subject: "hi", Body: "hello"}},
+		{name: "long body wraps", from: "blog@example.com", msg: Message{To: []string{"a@example.com"}, Subject: "长主题", Body: strings.Repeat("重置密码链接", 40)}},
+		{name: "empty body", from: "blog@example.com", msg: Message{To: []string{"a@example.com"}}},
+		{name: "invalid from", from: "blog", msg: Message{To: []string{"a@example.com"}}, wantErr: true},
+		{name: "invalid to", from: "blog@example.com", msg: Message{To: []string{"a"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := buildMessage(tt.from, tt.msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			subject, body := parseMessage(t, strings.ReplaceAll(string(data), "\r\n", "\n"))
+			if subject != tt.msg.Subject {
+				t.Errorf("subject = %q, want %q", subject, tt.msg.Subject)
+			}
+			if body != tt.msg.Body {
+				t.Errorf("body = %q, want %q", body, tt.msg.Body)
+			}
+		})
+	}
+}
+
+// parseMessage 解析buildMessage生成的邮件，返回解码后的主题和正文
+func parseMessage(t *testing.T, data string) (string, string) {
+	t.Helper()
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(data)))
+	header, err := reader.ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("read header: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	var encoded strings.Builder
+	for {
+		line, err := reader.ReadLine()
+		if err != nil {
+			break
+		}
+		if len(line) > 76 {
+			t.Fatalf("body line length %d exceeds 76", len(line))
+		}
+		encoded.WriteString(line)
+	}
+	body, err := base64.StdEncoding.DecodeString(encoded.String())
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return subject, string(body)
+}
+
+// errAny 表示只要求返回错误，不关心具体类型
+var errAny = errors.New("any error")
@@ -0,0 +1,206 @@
+package markdown
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// 渲染规则变化时修改版本号，使旧的渲染缓存失效
+const Version = "1"
+
+// 每分钟阅读的中文字数和英文单词数
+const (
+	cjkCharsPerMinute   = 300
+	latinWordsPerMinute = 200
+)
+
+var (
+	md = goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		// 允许原始HTML，输出前统一由sanitizer过滤
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// 代码高亮、脚注等使用的class
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[\w\- ]+$`)).Globally()
+	// 标题锚点和脚注锚点
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_\-:]+$`)).Globally()
+	// GFM任务列表
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// TOCItem 目录项，按标题层级嵌套
+type TOCItem struct {
+	Level    int       `json:"level"`
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Children []TOCItem `json:"children,omitempty"`
+}
+
+// Result 渲染结果
+type Result struct {
+	HTML        string    `json:"html"`
+	TOC         []TOCItem `json:"toc"`
+	ReadingTime int       `json:"readingTime"` // 预计阅读时间，单位: 分钟
+}
+
+// Render 将Markdown渲染为过滤后的HTML，并生成目录和预计阅读时间
+func Render(source string) (*Result, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newIDs()))
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+	return &Result{
+		HTML:        policy.Sanitize(buf.String()),
+		TOC:         buildTOC(doc, src),
+		ReadingTime: readingTime(source),
+	}, nil
+}
+
+// buildTOC 按标题出现顺序生成嵌套目录，层级跳跃的标题挂到最近的上级标题下
+func buildTOC(doc ast.Node, src []byte) []TOCItem {
+	var headings []TOCItem
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		item := TOCItem{Level: heading.Level, Title: nodeText(heading, src)}
+		if id, ok := heading.AttributeString("id"); ok {
+			if idBytes, ok := id.([]byte); ok {
+				item.ID = string(idBytes)
+			}
+		}
+		headings = append(headings, item)
+		return ast.WalkSkipChildren, nil
+	})
+
+	// 用栈记录当前路径上的各级标题，遇到同级或更高级标题时出栈
+	root := &TOCItem{}
+	stack := []*TOCItem{root}
+	for _, heading := range headings {
+		for len(stack) > 1 && stack[len(stack)-1].Level >= heading.Level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, heading)
+		stack = append(stack, &parent.Children[len(parent.Children)-1])
+	}
+	if root.Children == nil {
+		return []TOCItem{}
+	}
+	return root.Children
+}
+
+// nodeText 提取节点下的纯文本
+func nodeText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := child.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(src))
+			if t.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
+
+// readingTime 中文按字数、其他语言按单词数估算阅读时间，至少1分钟
+func readingTime(source string) int {
+	cjkChars, latinWords := 0, 0
+	inWord := false
+	for _, r := range source {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			cjkChars++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				latinWords++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	minutes := float64(cjkChars)/cjkCharsPerMinute + float64(latinWords)/latinWordsPerMinute
+	return max(int(math.Ceil(minutes)), 1)
+}
+
+// ids 标题锚点生成器，保留中文等字符，重复时追加序号
+type ids struct {
+	used map[string]bool
+}
+
+func newIDs() *ids {
+	return &ids{used: map[string]bool{}}
+}
+
+func (s *ids) Generate(value []byte, kind ast.NodeKind) []byte {
+	var b strings.Builder
+	lastHyphen := false
+	for _, r := range strings.ToLower(string(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			b.WriteRune(r)
+			lastHyphen = false
+		case (unicode.IsSpace(r) || r == '-') && !lastHyphen && b.Len() > 0:
+			b.WriteByte('-')
+			lastHyphen = true
+		}
+	}
+	id := strings.TrimSuffix(b.String(), "-")
+	if id == "" {
+		id = "heading"
+	}
+	result := id
+	for i := 1; s.used[result]; i++ {
+		result = id + "-" + strconv.Itoa(i)
+	}
+	s.used[result] = true
+	return []byte(result)
+}
+
+func (s *ids) Put(value []byte) {
+	s.used[string(value)] = true
+}
@@ -0,0 +1,204 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+func TestRenderSanitize(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		contains   []string
+		notContain []string
+	}{
+		{
+			name:       "script removed",
+			source:     "<script>alert(1)</script>hi",
+			contains:   []string{"hi"},
+			notContain: []string{"<script", "alert"},
+		},
+		{
+			name:       "javascript link and event handler removed",
+			source:     `<a href="javascript:alert(1)" onclick="x()">x</a>`,
+			notContain: []string{"javascript:", "onclick"},
+		},
+		{
+			name:       "img onerror removed",
+			source:     "<img src=x onerror=alert(1)>",
+			contains:   []string{`<img src="x">`},
+			notContain: []string{"onerror"},
+		},
+		{
+			name:       "style removed and class kept",
+			source:     `<div class="note" style="color:red">d</div>`,
+			contains:   []string{`<div class="note">d</div>`},
+			notContain: []string{"style"},
+		},
+		{
+			name:       "invalid class removed",
+			source:     `<span class="a&quot;b">x</span>`,
+			notContain: []string{"class="},
+		},
+		{
+			name:     "task list",
+			source:   "- [x] done\n- [ ] todo",
+			contains: []string{`<input checked="" disabled="" type="checkbox"> done`, `<input disabled="" type="checkbox"> todo`},
+		},
+		{
+			name:     "code highlight classes",
+			source:   "```go\nfmt.Println(1)\n```",
+			contains: []string{`<pre class="chroma">`, `<span class="nf">Println</span>`},
+		},
+		{
+			name:     "heading anchor",
+			source:   "# 标题 一",
+			contains: []string{`<h1 id="标题-一">标题 一</h1>`},
+		},
+		{
+			name:     "footnote",
+			source:   "text[^1]\n\n[^1]: note",
+			contains: []string{`<sup id="fnref:1">`, `<li id="fn:1">`, `class="footnote-backref"`},
+		},
+		{
+			name:     "gfm table",
+			source:   "| a | b |\n| - | - |\n| 1 | 2 |",
+			contains: []string{"<table>", "<td>1</td>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(result.HTML, want) {
+					t.Errorf("HTML %q does not contain %q", result.HTML, want)
+				}
+			}
+			for _, unwanted := range tt.notContain {
+				if strings.Contains(result.HTML, unwanted) {
+					t.Errorf("HTML %q contains %q", result.HTML, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTOC(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "no heading", source: "text", want: ""},
+		{
+			name:   "nested",
+			source: "# A\n## B\n### C\n## D\n# E",
+			want:   "1:a:A(2:b:B(3:c:C),2:d:D),1:e:E",
+		},
+		{
+			name:   "level jump attaches to nearest parent",
+			source: "## A\n#### B\n### C\n# D",
+			want:   "2:a:A(4:b:B,3:c:C),1:d:D",
+		},
+		{
+			name:   "starts below top level",
+			source: "### A\n# B\n## C",
+			want:   "3:a:A,1:b:B(2:c:C)",
+		},
+		{
+			name:   "duplicate ids",
+			source: "## Hello `code` World\n## Hello `code` World",
+			want:   "2:hello-code-world:Hello code World,2:hello-code-world-1:Hello code World",
+		},
+		{
+			name:   "cjk and emphasis",
+			source: "# Go *并发* 编程",
+			want:   "1:go-并发-编程:Go 并发 编程",
+		},
+		{
+			name:   "heading attribute syntax is plain text",
+			source: "# Title {#custom}\n## Sub",
+			want:   "1:title-custom:Title {#custom}(2:sub:Sub)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.TOC == nil {
+				t.Fatal("TOC = nil, want empty slice")
+			}
+			if got := formatTOC(result.TOC); got != tt.want {
+				t.Errorf("TOC = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   int
+	}{
+		{name: "empty", source: "", want: 1},
+		{name: "short", source: "hello world", want: 1},
+		{name: "cjk", source: strings.Repeat("字", cjkCharsPerMinute*2+1), want: 3},
+		{name: "latin words", source: strings.Repeat("word ", latinWordsPerMinute*3), want: 3},
+		{name: "mixed", source: strings.Repeat("字", cjkCharsPerMinute) + strings.Repeat(" word", latinWordsPerMinute/2), want: 2},
+		{name: "punctuation split words", source: strings.Repeat("a,", latinWordsPerMinute+1), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readingTime(tt.source); got != tt.want {
+				t.Errorf("readingTime() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDsGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{name: "lower case and hyphen", values: []string{"Hello World"}, want: []string{"hello-world"}},
+		{name: "collapse separators", values: []string{"  a -- b  "}, want: []string{"a-b"}},
+		{name: "drop punctuation", values: []string{"What's new?"}, want: []string{"whats-new"}},
+		{name: "keep cjk and underscore", values: []string{"并发 snake_case"}, want: []string{"并发-snake_case"}},
+		{name: "empty falls back", values: []string{"!!!"}, want: []string{"heading"}},
+		{name: "duplicates numbered", values: []string{"a", "a", "a"}, want: []string{"a", "a-1", "a-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := newIDs()
+			for i, value := range tt.values {
+				if got := string(generator.Generate([]byte(value), ast.KindHeading)); got != tt.want[i] {
+					t.Errorf("Generate(%q) = %q, want %q", value, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// formatTOC 将目录格式化为level:id:title(children)的紧凑形式，便于比较
+func formatTOC(items []TOCItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		part := strconv.Itoa(item.Level) + ":" + item.ID + ":" + item.Title
+		if len(item.Children) > 0 {
+			part += "(" + formatTOC(item.Children) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ",")
+}
@@ -1,15 +1,39 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
 	"github.com/narcissus1949/narcissus-blog/internal/jwt"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
 	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
 	"go.uber.org/zap"
 )
 
-func JWTAuth() gin.HandlerFunc {
+// 刷新token接口，只有该接口接受refresh token
+const refreshTokenPath = "/user/token/refresh"
+
+// 需修改密码的用户只能访问的接口，刷新后的token仍需修改密码
+var passwordResetAllowedPaths = map[string]bool{
+	"/user/password/change": true,
+	"/user/logout":          true,
+	refreshTokenPath:        true,
+}
+
+// PersonalTokenAuthenticator 校验个人访问token，token无效时返回nil
+type PersonalTokenAuthenticator func(c *gin.Context, token string) (*rbac.TokenPrincipal, error)
+
+var personalTokenAuthenticator PersonalTokenAuthenticator
+
+// SetPersonalTokenAuthenticator 设置个人访问token的校验方法，未设置时不接受个人访问token
+func SetPersonalTokenAuthenticator(authenticator PersonalTokenAuthenticator) {
+	personalTokenAuthenticator = authenticator
+}
+
+// JWTAuth 校验JWT，scopes不为空时同时接受拥有这些权限范围的个人访问token
+func JWTAuth(scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 存放token一般都是在请求头部的Authorization按该格式存放"Bearer [token]"
 		authorization := c.Request.Header.Get("Authorization")
@@ -28,6 +52,12 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 个人访问token
+		if strings.HasPrefix(token, utils.PERSONAL_TOKEN_PREFIX) {
+			personalTokenAuth(c, token, scopes)
+			return
+		}
+
 		// 解析token
 		claims, parseTokenErr := jwt.ParseToken(token)
 		if parseTokenErr != nil {
@@ -42,6 +72,14 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// refresh token只能用于刷新token
+		if claims.IsRefreshToken() && c.FullPath() != refreshTokenPath {
+			zap.L().Warn("Refresh token is not allowed", zap.Int("user id", claims.UserID), zap.String("path", c.FullPath()))
+			resp.UnauthorizedFail(c)
+			c.Abort()
+			return
+		}
+
 		// 验证token是否在黑名单
 		redisClient := cache.Client
 		count, tokenExistsErr := redisClient.Exists(c, utils.ACCESS_TOKEN_BLACKLIST+token, utils.REFRESH_TOKEN_BLACKLIST+token).Result()
@@ -59,9 +97,88 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 验证用户token是否已被整体撤销，如账号被禁用
+		revoked, checkRevokedErr := jwt.IsUserTokenRevoked(c, claims)
+		if checkRevokedErr != nil {
+			zap.L().Error("Failed to check user token revoked", zap.Error(checkRevokedErr))
+			resp.UnauthorizedFail(c)
+			c.Abort()
+			return
+		}
+		if revoked {
+			zap.L().Error("Token is invalid, revoked", zap.Int("user id", claims.UserID))
+			resp.UnauthorizedFail(c)
+			c.Abort()
+			return
+		}
+
+		// 验证会话是否已被撤销，refresh token还需未被轮换
+		sessionValid, checkSessionErr := jwt.IsSessionValid(c, claims)
+		if checkSessionErr != nil {
+			zap.L().Error("Failed to check session", zap.Error(checkSessionErr))
+			resp.UnauthorizedFail(c)
+			c.Abort()
+			return
+		}
+		if !sessionValid {
+			zap.L().Error("Token is invalid, session revoked", zap.Int("user id", claims.UserID), zap.String("session id", claims.SessionID))
+			resp.UnauthorizedFail(c)
+			c.Abort()
+			return
+		}
+
+		// 管理员强制重置密码后，修改密码前不允许访问其他接口
+		if claims.PasswordResetRequired && !passwordResetAllowedPaths[c.FullPath()] {
+			zap.L().Warn("Password reset required", zap.Int("user id", claims.UserID), zap.String("path", c.FullPath()))
+			resp.PasswordResetRequiredFail(c)
+			c.Abort()
+			return
+		}
+
 		// 向context中存放信息
 		c.Set(utils.CONTEXT_USER_ID, claims.UserID)
+		c.Set(utils.CONTEXT_USER_ROLES, claims.Roles)
+		c.Set(utils.CONTEXT_SESSION_ID, claims.SessionID)
 
 		c.Next()
 	}
 }
+
+func personalTokenAuth(c *gin.Context, token string, scopes []string) {
+	// 未声明权限范围的接口不接受个人访问token
+	if len(scopes) == 0 || personalTokenAuthenticator == nil {
+		zap.L().Warn("Personal access token is not allowed", zap.String("path", c.FullPath()))
+		resp.ForbiddenFail(c)
+		c.Abort()
+		return
+	}
+	principal, authErr := personalTokenAuthenticator(c, token)
+	if authErr != nil {
+		zap.L().Error("Failed to authenticate personal access token", zap.Error(authErr))
+		resp.UnauthorizedFail(c)
+		c.Abort()
+		return
+	}
+	if principal == nil {
+		zap.L().Error("Personal access token is invalid")
+		resp.UnauthorizedFail(c)
+		c.Abort()
+		return
+	}
+	if !rbac.HasAllScopes(principal.Scopes, scopes...) {
+		zap.L().Warn("Personal access token scope denied",
+			zap.Int("user id", principal.UserID),
+			zap.Strings("scopes", principal.Scopes),
+			zap.Strings("required scopes", scopes))
+		resp.ForbiddenFail(c)
+		c.Abort()
+		return
+	}
+
+	// 向context中存放信息
+	c.Set(utils.CONTEXT_USER_ID, principal.UserID)
+	c.Set(utils.CONTEXT_USER_ROLES, principal.Roles)
+	c.Set(utils.CONTEXT_TOKEN_SCOPES, principal.Scopes)
+
+	c.Next()
+}
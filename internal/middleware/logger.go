@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"regexp"
 	"runtime/debug"
 	"strings"
 	"time"
@@ -16,15 +17,18 @@ import (
 	"go.uber.org/zap"
 )
 
+// 请求ID只允许字母、数字和._:-，长度不超过审计日志request_id字段的64
+var requestIDReg = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
 // GinLogger 接收gin框架默认的日志
 func GinLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
-		// x-request-id
+		// x-request-id，客户端传入的值不合法时重新生成，避免超出审计日志字段长度或注入日志
 		requestID := c.GetHeader(utils.X_REQUEST_ID)
-		if requestID == "" {
+		if !requestIDReg.MatchString(requestID) {
 			requestID = utils.GenerateUUID()
 		}
 		c.Set(utils.X_REQUEST_ID, requestID)
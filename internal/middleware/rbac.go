@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+// RequireRole 要求用户拥有roles中的任一角色，需在JWTAuth之后使用
+func RequireRole(roles ...int32) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		owned := rbac.RolesFromContext(c)
+		if !rbac.HasAnyRole(owned, roles...) {
+			logger.FromContext(c.Request.Context()).Warn("Permission denied",
+				zap.Int("user id", c.GetInt(utils.CONTEXT_USER_ID)),
+				zap.Int32s("roles", owned),
+				zap.Int32s("required roles", roles))
+			resp.ForbiddenFail(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
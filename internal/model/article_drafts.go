@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+const TableNameArticleDraft = "article_drafts"
+
+// ArticleDraft mapped from table <article_drafts>
+type ArticleDraft struct {
+	ID          int64     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ArticleID   int64     `json:"article_id" gorm:"column:article_id;not null"` // 新建文章的草稿为0
+	UserID      int       `json:"user_id" gorm:"column:user_id;not null"`
+	Title       string    `json:"title" gorm:"column:title"`
+	Summary     string    `json:"summary" gorm:"column:summary"`
+	Content     string    `json:"content" gorm:"column:content;type:mediumtext"`
+	Category    string    `json:"category" gorm:"column:category"`
+	Tags        string    `json:"tags" gorm:"column:tags"`                 // 标签名称，用,隔开
+	BaseVersion int       `json:"base_version" gorm:"column:base_version"` // 草稿所基于的文章版本号
+	CreatedTime time.Time `json:"created_time" gorm:"column:created_time;autoCreateTime"`
+	UpdatedTime time.Time `json:"updated_time" gorm:"column:updated_time;autoUpdateTime"`
+}
+
+// TableName ArticleDraft's table name
+func (*ArticleDraft) TableName() string {
+	return TableNameArticleDraft
+}
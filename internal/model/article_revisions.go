@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+const TableNameArticleRevision = "article_revisions"
+
+// ArticleRevision mapped from table <article_revisions>
+type ArticleRevision struct {
+	ID           int64     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ArticleID    int64     `json:"article_id" gorm:"column:article_id;not null"`
+	Revision     int       `json:"revision" gorm:"column:revision;not null"` // 版本号，每篇文章从1开始递增
+	Title        string    `json:"title" gorm:"column:title;not null"`
+	Summary      string    `json:"summary" gorm:"column:summary"`
+	Content      string    `json:"content" gorm:"column:content;type:mediumtext"`
+	CategoryID   *int      `json:"category_id" gorm:"column:category_id;null"`
+	CategoryName string    `json:"category_name" gorm:"column:category_name"`
+	TagNameList  string    `json:"tag_name_list" gorm:"column:tag_name_list"` // 标签名称，用,隔开
+	EditorID     int       `json:"editor_id" gorm:"column:editor_id"`
+	CreatedTime  time.Time `json:"created_time" gorm:"column:created_time;autoCreateTime"`
+}
+
+// TableName ArticleRevision's table name
+func (*ArticleRevision) TableName() string {
+	return TableNameArticleRevision
+}
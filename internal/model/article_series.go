@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+const (
+	TableNameArticleSeries         = "article_series"
+	TableNameArticleSeriesRelation = "article_series_relations"
+)
+
+// ArticleSeries mapped from table <article_series>
+type ArticleSeries struct {
+	ID          int64     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"column:name;not null"`
+	Description string    `json:"description" gorm:"column:description"`
+	CreatedTime time.Time `json:"created_time" gorm:"column:created_time;autoCreateTime"`
+	UpdatedTime time.Time `json:"updated_time" gorm:"column:updated_time;autoUpdateTime"`
+}
+
+// TableName ArticleSeries's table name
+func (*ArticleSeries) TableName() string {
+	return TableNameArticleSeries
+}
+
+// ArticleSeriesRelation 文章 - 系列关系，每篇文章最多属于1个系列
+type ArticleSeriesRelation struct {
+	ID        int64 `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	SeriesID  int64 `json:"series_id" gorm:"column:series_id;not null"`
+	ArticleID int64 `json:"article_id" gorm:"column:article_id;not null"`
+	SortOrder int   `json:"sort_order" gorm:"column:sort_order;not null"` // 文章在系列中的顺序，从小到大
+}
+
+func (*ArticleSeriesRelation) TableName() string {
+	return TableNameArticleSeriesRelation
+}
+
+// 系列中的文章，用于生成系列目录
+type ArticleSeriesArticle struct {
+	SeriesID  int64
+	ArticleID int64
+	Title     string
+	Slug      string
+	SortOrder int
+}
+
+// 系列及其下文章数
+type ArticleSeriesCount struct {
+	ArticleSeries
+	ArticleCount int
+}
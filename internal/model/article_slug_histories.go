@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+const TableNameArticleSlugHistory = "article_slug_histories"
+
+// ArticleSlugHistory mapped from table <article_slug_histories>
+type ArticleSlugHistory struct {
+	ID          int64     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ArticleID   int64     `json:"article_id" gorm:"column:article_id;not null"`
+	Slug        string    `json:"slug" gorm:"column:slug;not null"` // 文章曾经使用的URL别名
+	CreatedTime time.Time `json:"created_time" gorm:"column:created_time;autoCreateTime"`
+}
+
+// TableName ArticleSlugHistory's table name
+func (*ArticleSlugHistory) TableName() string {
+	return TableNameArticleSlugHistory
+}
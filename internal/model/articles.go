@@ -8,21 +8,26 @@ const TableNameArticle = "articles"
 
 // Article mapped from table <articles>
 type Article struct {
-	ID                  int64     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
-	Title               string    `json:"title" gorm:"column:title;not null"`
-	Summary             string    `json:"summary" gorm:"column:summary;type:text"`
-	Type                uint8     `json:"type" gorm:"column:type;not null"`
-	CategoryID          *int      `json:"category_id" gorm:"column:category_id;null"`
-	Author              string    `json:"author" gorm:"column:author;not null"`
-	AllowComment        bool      `json:"allow_comment" gorm:"column:allow_comment"`
-	Views               int       `json:"views" gorm:"column:views"`
-	Weight              int       `json:"weight" gorm:"column:weight"`
-	IsSticky            bool      `json:"is_sticky" gorm:"column:is_sticky"`
-	IsOriginal          bool      `json:"is_original" gorm:"column:is_original"`
-	OriginalArticleLink string    `json:"original_article_link" gorm:"column:original_article_link;null"`
-	Status              uint8     `json:"status" gorm:"column:status"`
-	CreatedTime         time.Time `json:"created_time" gorm:"column:created_time;autoCreateTime"`
-	UpdatedTime         time.Time `json:"updated_time" gorm:"column:updated_time;autoUpdateTime"`
+	ID                  int64      `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Title               string     `json:"title" gorm:"column:title;not null"`
+	Slug                string     `json:"slug" gorm:"column:slug;not null"` // URL别名，全局唯一
+	Summary             string     `json:"summary" gorm:"column:summary;type:text"`
+	Type                uint8      `json:"type" gorm:"column:type;not null"`
+	CategoryID          *int       `json:"category_id" gorm:"column:category_id;null"`
+	Author              string     `json:"author" gorm:"column:author;not null"`
+	OwnerID             int        `json:"owner_id" gorm:"column:owner_id"` // 所属用户ID，0表示无所属用户
+	AllowComment        bool       `json:"allow_comment" gorm:"column:allow_comment"`
+	Views               int        `json:"views" gorm:"column:views"`
+	Weight              int        `json:"weight" gorm:"column:weight"`
+	IsSticky            bool       `json:"is_sticky" gorm:"column:is_sticky"`
+	IsOriginal          bool       `json:"is_original" gorm:"column:is_original"`
+	OriginalArticleLink string     `json:"original_article_link" gorm:"column:original_article_link;null"`
+	Status              uint8      `json:"status" gorm:"column:status"`
+	PublishAt           *time.Time `json:"publish_at" gorm:"column:publish_at;null"`     // 定时发布时间，为空表示没有待执行的定时发布
+	Version             int        `json:"version" gorm:"column:version"`                // 乐观锁版本号，每次更新加1
+	DeletedTime         *time.Time `json:"deleted_time" gorm:"column:deleted_time;null"` // 移入回收站的时间，为空表示未删除
+	CreatedTime         time.Time  `json:"created_time" gorm:"column:created_time;autoCreateTime"`
+	UpdatedTime         time.Time  `json:"updated_time" gorm:"column:updated_time;autoUpdateTime"`
 }
 
 // TableName Article's table name
@@ -37,3 +42,15 @@ type ArticleDetail struct {
 	TagNameList  string
 	Content      string
 }
+
+// 文章ID及其更新时间，用于判断文章列表是否变化
+type ArticleVersion struct {
+	ID          int64
+	UpdatedTime time.Time
+}
+
+// 分类、标签名称及其下已发布文章的最后更新时间
+type NameLastmod struct {
+	Name        string
+	UpdatedTime time.Time
+}
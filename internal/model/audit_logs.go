@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const TableNameAuditLog = "audit_logs"
+
+// AuditLog mapped from table <audit_logs>
+type AuditLog struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	ActorID     int       `gorm:"column:actor_id;not null" json:"actor_id"`       // 操作人ID
+	Action      string    `gorm:"column:action;not null" json:"action"`           // 操作类型，如create、update、delete
+	EntityType  string    `gorm:"column:entity_type;not null" json:"entity_type"` // 实体类型，如article、user
+	EntityID    int64     `gorm:"column:entity_id;not null" json:"entity_id"`
+	BeforeData  string    `gorm:"column:before_data;type:text" json:"before_data"` // 修改前的JSON快照，新建时为空
+	AfterData   string    `gorm:"column:after_data;type:text" json:"after_data"`   // 修改后的JSON快照，删除时为空
+	RequestID   string    `gorm:"column:request_id" json:"request_id"`
+	IP          string    `gorm:"column:ip" json:"ip"`
+	CreatedTime time.Time `gorm:"column:created_time;default:CURRENT_TIMESTAMP" json:"created_time"`
+}
+
+// TableName AuditLog's table name
+func (*AuditLog) TableName() string {
+	return TableNameAuditLog
+}
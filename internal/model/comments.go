@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+const TableNameComment = "comments"
+
+// Comment mapped from table <comments>
+type Comment struct {
+	ID          int64     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ArticleID   int64     `json:"article_id" gorm:"column:article_id;not null"`
+	RootID      int64     `json:"root_id" gorm:"column:root_id;not null"`     // 所属顶层评论ID，顶层评论为0
+	ParentID    int64     `json:"parent_id" gorm:"column:parent_id;not null"` // 回复的评论ID，顶层评论为0
+	Nickname    string    `json:"nickname" gorm:"column:nickname;not null"`
+	Email       string    `json:"email" gorm:"column:email"`
+	Website     string    `json:"website" gorm:"column:website"`
+	Content     string    `json:"content" gorm:"column:content;type:text;not null"`
+	Status      uint8     `json:"status" gorm:"column:status"` // 0:待审核;1:已通过;2:已隐藏
+	IP          string    `json:"ip" gorm:"column:ip"`
+	UserAgent   string    `json:"user_agent" gorm:"column:user_agent"`
+	CreatedTime time.Time `json:"created_time" gorm:"column:created_time;autoCreateTime"`
+	UpdatedTime time.Time `json:"updated_time" gorm:"column:updated_time;autoUpdateTime"`
+}
+
+// TableName Comment's table name
+func (*Comment) TableName() string {
+	return TableNameComment
+}
+
+// 评论及其所属文章标题
+type CommentDetail struct {
+	Comment
+	ArticleTitle string
+}
+
+// 文章评论数
+type ArticleCommentCount struct {
+	ArticleID int64
+	Count     int
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+const TableNameModeration = "moderations"
+
+// Moderation mapped from table <moderations>
+type Moderation struct {
+	ID           int64      `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	SubjectType  string     `json:"subject_type" gorm:"column:subject_type;not null"` // 提交类型，如user_signup
+	SubjectID    int64      `json:"subject_id" gorm:"column:subject_id;not null"`     // 提交内容对应的实体ID
+	Content      string     `json:"content" gorm:"column:content;type:text"`          // 参与检测的文本快照
+	Fingerprint  string     `json:"fingerprint" gorm:"column:fingerprint"`            // 提交者指纹
+	IP           string     `json:"ip" gorm:"column:ip"`
+	Score        int        `json:"score" gorm:"column:score"`     // 可疑分数，越高越可疑
+	Reasons      string     `json:"reasons" gorm:"column:reasons"` // 命中的规则，用,隔开
+	Status       uint8      `json:"status" gorm:"column:status"`   // 0:待审核;1:已通过;2:已拒绝
+	ReviewerID   int        `json:"reviewer_id" gorm:"column:reviewer_id"`
+	ReviewedTime *time.Time `json:"reviewed_time" gorm:"column:reviewed_time"`
+	CreatedTime  time.Time  `json:"created_time" gorm:"column:created_time;autoCreateTime"`
+	UpdatedTime  time.Time  `json:"updated_time" gorm:"column:updated_time;autoUpdateTime"`
+}
+
+// TableName Moderation's table name
+func (*Moderation) TableName() string {
+	return TableNameModeration
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const TableNameUserAccessToken = "user_access_tokens"
+
+// UserAccessToken mapped from table <user_access_tokens>
+type UserAccessToken struct {
+	ID           int        `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	UserID       int        `gorm:"column:user_id;not null" json:"user_id"`
+	Name         string     `gorm:"column:name;not null" json:"name"`
+	TokenHash    string     `gorm:"column:token_hash;not null" json:"token_hash"`     // token的sha256
+	TokenPrefix  string     `gorm:"column:token_prefix;not null" json:"token_prefix"` // token前几位，用于识别
+	Scopes       string     `gorm:"column:scopes;not null" json:"scopes"`             // 权限范围，逗号分隔
+	ExpireTime   *time.Time `gorm:"column:expire_time" json:"expire_time"`            // 为空表示永不过期
+	LastUsedTime *time.Time `gorm:"column:last_used_time" json:"last_used_time"`
+	LastUsedIP   string     `gorm:"column:last_used_ip" json:"last_used_ip"`
+	CreatedTime  time.Time  `gorm:"column:created_time;default:CURRENT_TIMESTAMP" json:"created_time"`
+}
+
+// TableName UserAccessToken's table name
+func (*UserAccessToken) TableName() string {
+	return TableNameUserAccessToken
+}
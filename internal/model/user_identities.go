@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+const TableNameUserIdentity = "user_identities"
+
+// UserIdentity mapped from table <user_identities>
+type UserIdentity struct {
+	ID            int        `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	UserID        int        `gorm:"column:user_id;not null" json:"user_id"`
+	Provider      string     `gorm:"column:provider;not null" json:"provider"` // 身份提供方标识
+	Subject       string     `gorm:"column:subject;not null" json:"subject"`   // 提供方中的用户唯一标识(sub)
+	Email         string     `gorm:"column:email" json:"email"`
+	CreatedTime   time.Time  `gorm:"column:created_time;default:CURRENT_TIMESTAMP" json:"created_time"`
+	LastLoginTime *time.Time `gorm:"column:last_login_time" json:"last_login_time"`
+}
+
+// TableName UserIdentity's table name
+func (*UserIdentity) TableName() string {
+	return TableNameUserIdentity
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+const TableNameUserRole = "user_roles"
+
+// UserRole mapped from table <user_roles>
+type UserRole struct {
+	ID          int       `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	UserID      int       `gorm:"column:user_id;not null" json:"user_id"`
+	RoleID      int       `gorm:"column:role_id;not null" json:"role_id"`
+	CreatedTime time.Time `gorm:"column:created_time;default:CURRENT_TIMESTAMP" json:"created_time"`
+}
+
+// TableName UserRole's table name
+func (*UserRole) TableName() string {
+	return TableNameUserRole
+}
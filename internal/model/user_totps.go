@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const TableNameUserTotp = "user_totps"
+
+// UserTotp mapped from table <user_totps>
+type UserTotp struct {
+	ID          int       `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	UserID      int       `gorm:"column:user_id;not null" json:"user_id"`
+	Secret      string    `gorm:"column:secret;not null" json:"secret"`   // base32编码的TOTP密钥
+	Enabled     bool      `gorm:"column:enabled;not null" json:"enabled"` // 是否已启用，首次验证通过前为false
+	CreatedTime time.Time `gorm:"column:created_time;default:CURRENT_TIMESTAMP" json:"created_time"`
+	UpdatedTime time.Time `gorm:"column:updated_time;default:CURRENT_TIMESTAMP" json:"updated_time"`
+}
+
+// TableName UserTotp's table name
+func (*UserTotp) TableName() string {
+	return TableNameUserTotp
+}
+
+const TableNameUserRecoveryCode = "user_recovery_codes"
+
+// UserRecoveryCode mapped from table <user_recovery_codes>
+type UserRecoveryCode struct {
+	ID          int        `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	UserID      int        `gorm:"column:user_id;not null" json:"user_id"`
+	CodeHash    string     `gorm:"column:code_hash;not null" json:"code_hash"` // 恢复码sha256
+	UsedTime    *time.Time `gorm:"column:used_time" json:"used_time"`          // 为空表示未使用
+	CreatedTime time.Time  `gorm:"column:created_time;default:CURRENT_TIMESTAMP" json:"created_time"`
+}
+
+// TableName UserRecoveryCode's table name
+func (*UserRecoveryCode) TableName() string {
+	return TableNameUserRecoveryCode
+}
@@ -8,15 +8,17 @@ const TableNameUser = "users"
 
 // User mapped from table <users>
 type User struct {
-	ID          int       `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
-	Username    string    `gorm:"column:username;not null" json:"username"`
-	Nickname    string    `gorm:"column:nickname;not null" json:"nickname"`
-	Password    string    `gorm:"column:password;not null" json:"password"`
-	Email       string    `gorm:"column:email" json:"email"`
-	PhoneNumber string    `gorm:"column:phone_number" json:"phone_number"`
-	AvatarPath  string    `gorm:"column:avatar_path" json:"avatar_path"`
-	CreatedAt   time.Time `gorm:"column:created_at;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"column:updated_at;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID                    int       `gorm:"column:id;primaryKey;autoIncrement:true" json:"id"`
+	Username              string    `gorm:"column:username;not null" json:"username"`
+	Nickname              string    `gorm:"column:nickname;not null" json:"nickname"`
+	Password              string    `gorm:"column:password;not null" json:"password"`
+	Email                 string    `gorm:"column:email" json:"email"`
+	PhoneNumber           string    `gorm:"column:phone_number" json:"phone_number"`
+	AvatarPath            string    `gorm:"column:avatar_path" json:"avatar_path"`
+	Status                uint8     `gorm:"column:status;not null;comment:0:待审核;1:正常;2:已拒绝;3:已禁用" json:"status"`    // 0:待审核;1:正常;2:已拒绝;3:已禁用
+	PasswordResetRequired bool      `gorm:"column:password_reset_required;not null" json:"password_reset_required"` // 是否需要重置密码
+	CreatedAt             time.Time `gorm:"column:created_at;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt             time.Time `gorm:"column:updated_at;default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 // TableName User's table name
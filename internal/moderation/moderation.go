@@ -0,0 +1,157 @@
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	SUBJECT_TYPE_USER_SIGNUP = "user_signup"
+)
+
+var (
+	Config   = NewDefaultModerationCfg()
+	checkers []Checker
+	mu       sync.RWMutex
+	once     sync.Once
+)
+
+type ModerationConfig struct {
+	Enable       bool     `json:"enable,omitempty" yaml:"enable,omitempty"`             // 是否开启审核，关闭时提交内容直接通过
+	MaxLinks     int      `json:"maxLinks,omitempty" yaml:"maxLinks,omitempty"`         // 允许的最大链接数，超过则计分
+	BannedWords  []string `json:"bannedWords,omitempty" yaml:"bannedWords,omitempty"`   // 违禁词列表，不区分大小写
+	RepeatWindow int      `json:"repeatWindow,omitempty" yaml:"repeatWindow,omitempty"` // 重复提交统计窗口，单位: 分钟
+	RepeatLimit  int      `json:"repeatLimit,omitempty" yaml:"repeatLimit,omitempty"`   // 统计窗口内允许的提交次数，超过则计分
+}
+
+func NewDefaultModerationCfg() ModerationConfig {
+	return ModerationConfig{
+		Enable:       true,
+		MaxLinks:     2,
+		BannedWords:  []string{},
+		RepeatWindow: 60,
+		RepeatLimit:  3,
+	}
+}
+
+// Submission 待审核的提交内容
+type Submission struct {
+	SubjectType string // 提交类型
+	Content     string // 参与检测的文本
+	Fingerprint string // 提交者指纹，由IP和User-Agent在服务端计算
+	RecentCount int    // 提交者在统计窗口内的提交次数，包含本次
+}
+
+// Result 检测结果，分数越高越可疑
+type Result struct {
+	Score   int
+	Reasons []string
+}
+
+// Checker 本地启发式检测规则，未命中时返回0和空字符串
+type Checker interface {
+	Name() string
+	Check(sub Submission) (int, string)
+}
+
+func MustInit(cfg ModerationConfig) {
+	once.Do(func() {
+		Config = cfg
+		RegisterChecker(&linkCountChecker{maxLinks: cfg.MaxLinks})
+		RegisterChecker(newBannedWordChecker(cfg.BannedWords))
+		RegisterChecker(&repeatSubmitterChecker{limit: cfg.RepeatLimit})
+	})
+}
+
+// RegisterChecker 注册检测规则
+func RegisterChecker(checker Checker) {
+	mu.Lock()
+	defer mu.Unlock()
+	checkers = append(checkers, checker)
+}
+
+// Evaluate 依次执行所有检测规则并累加分数
+func Evaluate(sub Submission) Result {
+	mu.RLock()
+	defer mu.RUnlock()
+	result := Result{Reasons: []string{}}
+	for _, checker := range checkers {
+		score, reason := checker.Check(sub)
+		if score <= 0 {
+			continue
+		}
+		result.Score += score
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s:%s", checker.Name(), reason))
+	}
+	return result
+}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)`)
+
+// linkCountChecker 链接数量检测
+type linkCountChecker struct {
+	maxLinks int
+}
+
+func (c *linkCountChecker) Name() string {
+	return "link_count"
+}
+
+func (c *linkCountChecker) Check(sub Submission) (int, string) {
+	count := len(linkPattern.FindAllStringIndex(sub.Content, -1))
+	if count <= c.maxLinks {
+		return 0, ""
+	}
+	return 20 * (count - c.maxLinks), fmt.Sprintf("%d", count)
+}
+
+// bannedWordChecker 违禁词检测
+type bannedWordChecker struct {
+	words []string
+}
+
+func newBannedWordChecker(words []string) *bannedWordChecker {
+	lowerWords := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			lowerWords = append(lowerWords, word)
+		}
+	}
+	return &bannedWordChecker{words: lowerWords}
+}
+
+func (c *bannedWordChecker) Name() string {
+	return "banned_word"
+}
+
+func (c *bannedWordChecker) Check(sub Submission) (int, string) {
+	content := strings.ToLower(sub.Content)
+	var hits []string
+	for _, word := range c.words {
+		if strings.Contains(content, word) {
+			hits = append(hits, word)
+		}
+	}
+	if len(hits) == 0 {
+		return 0, ""
+	}
+	return 50 * len(hits), strings.Join(hits, "|")
+}
+
+// repeatSubmitterChecker 重复提交检测
+type repeatSubmitterChecker struct {
+	limit int
+}
+
+func (c *repeatSubmitterChecker) Name() string {
+	return "repeat_submitter"
+}
+
+func (c *repeatSubmitterChecker) Check(sub Submission) (int, string) {
+	if sub.RecentCount <= c.limit {
+		return 0, ""
+	}
+	return 30, fmt.Sprintf("%d", sub.RecentCount)
+}
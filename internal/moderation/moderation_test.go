@@ -0,0 +1,121 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLinkCountChecker(t *testing.T) {
+	checker := &linkCountChecker{maxLinks: 2}
+	tests := []struct {
+		name       string
+		content    string
+		wantScore  int
+		wantReason string
+	}{
+		{name: "no link", content: "hello", wantScore: 0},
+		{name: "within limit", content: "https://a.com http://b.com", wantScore: 0},
+		{name: "one over limit", content: "https://a.com http://b.com www.c.com", wantScore: 20, wantReason: "3"},
+		{name: "case insensitive", content: "HTTPS://a.com HTTP://b.com WWW.c.com https://d.com", wantScore: 40, wantReason: "4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reason := checker.Check(Submission{Content: tt.content})
+			if score != tt.wantScore || reason != tt.wantReason {
+				t.Errorf("Check() = (%d, %q), want (%d, %q)", score, reason, tt.wantScore, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestBannedWordChecker(t *testing.T) {
+	checker := newBannedWordChecker([]string{" Casino ", "", "免费", "BUY NOW"})
+	tests := []struct {
+		name       string
+		content    string
+		wantScore  int
+		wantReason string
+	}{
+		{name: "clean", content: "正常的个人简介", wantScore: 0},
+		{name: "case insensitive", content: "best CASINO online", wantScore: 50, wantReason: "casino"},
+		{name: "multiple words", content: "免费 casino, buy now", wantScore: 150, wantReason: "casino|免费|buy now"},
+		{name: "empty word ignored", content: " ", wantScore: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reason := checker.Check(Submission{Content: tt.content})
+			if score != tt.wantScore || reason != tt.wantReason {
+				t.Errorf("Check() = (%d, %q), want (%d, %q)", score, reason, tt.wantScore, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRepeatSubmitterChecker(t *testing.T) {
+	checker := &repeatSubmitterChecker{limit: 3}
+	tests := []struct {
+		name        string
+		recentCount int
+		wantScore   int
+		wantReason  string
+	}{
+		{name: "count unavailable", recentCount: 0, wantScore: 0},
+		{name: "first submission", recentCount: 1, wantScore: 0},
+		{name: "at limit", recentCount: 3, wantScore: 0},
+		{name: "over limit", recentCount: 4, wantScore: 30, wantReason: "4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reason := checker.Check(Submission{RecentCount: tt.recentCount})
+			if score != tt.wantScore || reason != tt.wantReason {
+				t.Errorf("Check() = (%d, %q), want (%d, %q)", score, reason, tt.wantScore, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	old := checkers
+	t.Cleanup(func() { checkers = old })
+	checkers = nil
+	RegisterChecker(&linkCountChecker{maxLinks: 1})
+	RegisterChecker(newBannedWordChecker([]string{"casino"}))
+	RegisterChecker(&repeatSubmitterChecker{limit: 2})
+
+	tests := []struct {
+		name        string
+		sub         Submission
+		wantScore   int
+		wantReasons []string
+	}{
+		{
+			name:        "clean",
+			sub:         Submission{Content: "hello", RecentCount: 1},
+			wantScore:   0,
+			wantReasons: []string{},
+		},
+		{
+			name:        "single rule",
+			sub:         Submission{Content: "casino", RecentCount: 1},
+			wantScore:   50,
+			wantReasons: []string{"banned_word:casino"},
+		},
+		{
+			name:        "all rules add up",
+			sub:         Submission{Content: "casino https://a.com https://b.com", RecentCount: 3},
+			wantScore:   20 + 50 + 30,
+			wantReasons: []string{"link_count:2", "banned_word:casino", "repeat_submitter:3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Evaluate(tt.sub)
+			if result.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", result.Score, tt.wantScore)
+			}
+			if result.Reasons == nil || strings.Join(result.Reasons, ",") != strings.Join(tt.wantReasons, ",") {
+				t.Errorf("Reasons = %#v, want %#v", result.Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
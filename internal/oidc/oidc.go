@@ -0,0 +1,225 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	Config = NewDefaultOidcCfg()
+
+	ErrProviderNotFound = errors.New("oidc provider not found")
+	ErrNonceMismatch    = errors.New("oidc nonce mismatch")
+
+	registryMu sync.RWMutex
+	registry   = map[string]Provider{}
+)
+
+type OidcConfig struct {
+	Providers   []ProviderConfig `json:"providers,omitempty" yaml:"providers,omitempty"`
+	StateExpire int              `json:"stateExpire,omitempty" yaml:"stateExpire,omitempty"` // 授权请求有效期，单位: 秒
+	AllowSignup bool             `json:"allowSignup,omitempty" yaml:"allowSignup,omitempty"` // 外部身份首次登录时是否自动创建账号
+}
+
+type ProviderConfig struct {
+	Name         string   `json:"name,omitempty" yaml:"name,omitempty"`                 // 提供方标识，用于路由，如 google
+	DisplayName  string   `json:"displayName,omitempty" yaml:"displayName,omitempty"`   // 前端展示名称
+	Issuer       string   `json:"issuer,omitempty" yaml:"issuer,omitempty"`             // issuer地址，通过 /.well-known/openid-configuration 发现端点
+	ClientID     string   `json:"clientID,omitempty" yaml:"clientID,omitempty"`         // 客户端ID
+	ClientSecret string   `json:"clientSecret,omitempty" yaml:"clientSecret,omitempty"` // 客户端密钥，公开客户端可为空
+	RedirectURL  string   `json:"redirectURL,omitempty" yaml:"redirectURL,omitempty"`   // 回调地址，为前端页面，由前端将code和state提交给后端
+	Scopes       []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`             // 额外申请的scope，openid总是申请
+}
+
+func NewDefaultOidcCfg() OidcConfig {
+	return OidcConfig{
+		Providers:   []ProviderConfig{},
+		StateExpire: 600,
+		AllowSignup: true,
+	}
+}
+
+func MustInit(cfg OidcConfig) {
+	if cfg.StateExpire <= 0 {
+		panic("oidc state expire must be greater than 0")
+	}
+	providers := make(map[string]Provider, len(cfg.Providers))
+	for _, providerCfg := range cfg.Providers {
+		if providerCfg.Name == "" || providerCfg.Issuer == "" || providerCfg.ClientID == "" || providerCfg.RedirectURL == "" {
+			panic("oidc provider name, issuer, clientID and redirectURL must not be empty")
+		}
+		if _, ok := providers[providerCfg.Name]; ok {
+			panic(fmt.Sprintf("oidc provider %s is duplicated", providerCfg.Name))
+		}
+		providers[providerCfg.Name] = NewProvider(providerCfg)
+	}
+	registryMu.Lock()
+	registry = providers
+	registryMu.Unlock()
+	Config = cfg
+}
+
+// Identity 外部身份提供方返回的用户信息
+type Identity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Picture           string
+}
+
+// Provider 外部身份提供方，默认实现基于OIDC发现，可通过Register替换或扩展
+type Provider interface {
+	Name() string
+	DisplayName() string
+	// AuthCodeURL 生成授权地址，verifier为PKCE code verifier
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	// Exchange 使用授权码换取token并校验ID Token，返回外部身份
+	Exchange(ctx context.Context, code, nonce, verifier string) (*Identity, error)
+}
+
+// Register 注册或替换身份提供方
+func Register(provider Provider) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[provider.Name()] = provider
+}
+
+// Get 按名称查询身份提供方
+func Get(name string) (Provider, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	provider, ok := registry[name]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+	return provider, nil
+}
+
+// List 按名称排序返回所有身份提供方
+func List() []Provider {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	providers := make([]Provider, 0, len(registry))
+	for _, provider := range registry {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name() < providers[j].Name()
+	})
+	return providers
+}
+
+// GenerateVerifier 生成PKCE code verifier
+func GenerateVerifier() string {
+	return oauth2.GenerateVerifier()
+}
+
+type oidcProvider struct {
+	cfg ProviderConfig
+
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewProvider 基于OIDC发现的身份提供方，首次使用时请求发现文档，失败时下次重试
+func NewProvider(cfg ProviderConfig) Provider {
+	return &oidcProvider{cfg: cfg}
+}
+
+func (p *oidcProvider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *oidcProvider) DisplayName() string {
+	if p.cfg.DisplayName == "" {
+		return p.cfg.Name
+	}
+	return p.cfg.DisplayName
+}
+
+func (p *oidcProvider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauth2 != nil {
+		return p.oauth2, p.verifier, nil
+	}
+	provider, err := gooidc.NewProvider(ctx, p.cfg.Issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+	scopes := []string{gooidc.ScopeOpenID}
+	for _, scope := range p.cfg.Scopes {
+		if scope != gooidc.ScopeOpenID {
+			scopes = append(scopes, scope)
+		}
+	}
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  p.cfg.RedirectURL,
+		Scopes:       scopes,
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+	return p.oauth2, p.verifier, nil
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oauth2Cfg, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return oauth2Cfg.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code, nonce, verifier string) (*Identity, error) {
+	oauth2Cfg, idTokenVerifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := oauth2Cfg.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("id_token missing in token response")
+	}
+	idToken, err := idTokenVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     any    `json:"email_verified"` // 部分提供方以字符串返回
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+		Picture           string `json:"picture"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return &Identity{
+		Provider:          p.cfg.Name,
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+		Picture:           claims.Picture,
+	}, nil
+}
@@ -0,0 +1,372 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+)
+
+const (
+	testClientID    = "blog"
+	testRedirectURL = "https://blog.example.com/oidc/callback"
+	testKeyID       = "test"
+)
+
+// authRequest 授权请求中需要在换取token时校验的参数
+type authRequest struct {
+	challenge string
+	nonce     string
+	claims    map[string]any
+}
+
+// mockProvider 基于httptest的OIDC提供方，支持发现、授权码换取token与JWKS
+type mockProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authRequest
+	// discoveryFailures 发现文档返回错误的剩余次数
+	discoveryFailures int
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	m := &mockProvider{t: t, key: key, codes: map[string]authRequest{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/keys", m.keys)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	fail := m.discoveryFailures > 0
+	if fail {
+		m.discoveryFailures--
+	}
+	m.mu.Unlock()
+	if fail {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, map[string]any{
+		"issuer":                                m.server.URL,
+		"authorization_endpoint":                m.server.URL + "/authorize",
+		"token_endpoint":                        m.server.URL + "/token",
+		"jwks_uri":                              m.server.URL + "/keys",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (m *mockProvider) keys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &m.key.PublicKey,
+		KeyID:     testKeyID,
+		Algorithm: string(jose.RS256),
+		Use:       "sig",
+	}}})
+}
+
+func (m *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	m.mu.Lock()
+	req, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	if !ok {
+		writeOAuthError(w, "invalid_grant")
+		return
+	}
+	if s256(r.PostForm.Get("code_verifier")) != req.challenge {
+		writeOAuthError(w, "invalid_grant")
+		return
+	}
+	claims := map[string]any{
+		"iss":   m.server.URL,
+		"aud":   testClientID,
+		"sub":   "subject-1",
+		"nonce": req.nonce,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range req.claims {
+		claims[k] = v
+	}
+	writeJSON(w, map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     m.sign(claims),
+	})
+}
+
+func (m *mockProvider) sign(claims map[string]any) string {
+	m.t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: m.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", testKeyID))
+	if err != nil {
+		m.t.Fatalf("new signer: %v", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		m.t.Fatalf("marshal claims: %v", err)
+	}
+	jws, err := signer.Sign(payload)
+	if err != nil {
+		m.t.Fatalf("sign claims: %v", err)
+	}
+	raw, err := jws.CompactSerialize()
+	if err != nil {
+		m.t.Fatalf("serialize jws: %v", err)
+	}
+	return raw
+}
+
+// authorize 模拟用户在提供方完成授权，返回授权码
+func (m *mockProvider) authorize(authURL string, claims map[string]any) string {
+	m.t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		m.t.Fatalf("parse auth url: %v", err)
+	}
+	query := u.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		m.t.Fatalf("code_challenge_method = %q, want S256", query.Get("code_challenge_method"))
+	}
+	code := "code-" + query.Get("state")
+	m.mu.Lock()
+	m.codes[code] = authRequest{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		claims:    claims,
+	}
+	m.mu.Unlock()
+	return code
+}
+
+func (m *mockProvider) providerConfig() ProviderConfig {
+	return ProviderConfig{
+		Name:        "mock",
+		Issuer:      m.server.URL,
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+		Scopes:      []string{"openid", "email", "profile"},
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	m := newMockProvider(t)
+	provider := NewProvider(m.providerConfig())
+	verifier := GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse auth url: %v", err)
+	}
+	query := u.Query()
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"endpoint", u.Scheme + "://" + u.Host + u.Path, m.server.URL + "/authorize"},
+		{"client id", query.Get("client_id"), testClientID},
+		{"redirect uri", query.Get("redirect_uri"), testRedirectURL},
+		{"response type", query.Get("response_type"), "code"},
+		{"state", query.Get("state"), "state-1"},
+		{"nonce", query.Get("nonce"), "nonce-1"},
+		{"scope", query.Get("scope"), "openid email profile"},
+		{"challenge method", query.Get("code_challenge_method"), "S256"},
+		{"challenge", query.Get("code_challenge"), s256(verifier)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoveryRetry(t *testing.T) {
+	m := newMockProvider(t)
+	m.discoveryFailures = 1
+	provider := NewProvider(m.providerConfig())
+
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", GenerateVerifier()); err == nil {
+		t.Fatal("AuthCodeURL() error = nil, want discovery error")
+	}
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", GenerateVerifier()); err != nil {
+		t.Fatalf("AuthCodeURL() after discovery recovered error = %v", err)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   map[string]any
+		code     func(code string) string
+		verifier func(verifier string) string
+		nonce    func(nonce string) string
+		want     *Identity
+		wantErr  error
+	}{
+		{
+			name: "success",
+			claims: map[string]any{
+				"email":              "alice@example.com",
+				"email_verified":     true,
+				"name":               "Alice",
+				"preferred_username": "alice",
+				"picture":            "https://example.com/alice.png",
+			},
+			want: &Identity{
+				Provider:          "mock",
+				Subject:           "subject-1",
+				Email:             "alice@example.com",
+				EmailVerified:     true,
+				Name:              "Alice",
+				PreferredUsername: "alice",
+				Picture:           "https://example.com/alice.png",
+			},
+		},
+		{
+			name:   "email verified as string",
+			claims: map[string]any{"email": "bob@example.com", "email_verified": "true"},
+			want:   &Identity{Provider: "mock", Subject: "subject-1", Email: "bob@example.com", EmailVerified: true},
+		},
+		{
+			name:   "email not verified",
+			claims: map[string]any{"email": "carol@example.com", "email_verified": false},
+			want:   &Identity{Provider: "mock", Subject: "subject-1", Email: "carol@example.com"},
+		},
+		{
+			name:     "pkce verifier mismatch",
+			verifier: func(string) string { return GenerateVerifier() },
+			wantErr:  errAny,
+		},
+		{
+			name:    "nonce mismatch",
+			nonce:   func(string) string { return "other-nonce" },
+			wantErr: ErrNonceMismatch,
+		},
+		{
+			name:    "unknown code",
+			code:    func(code string) string { return code + "-unknown" },
+			wantErr: errAny,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockProvider(t)
+			provider := NewProvider(m.providerConfig())
+			ctx := context.Background()
+			nonce, verifier := "nonce-"+tt.name, GenerateVerifier()
+
+			authURL, err := provider.AuthCodeURL(ctx, "state", nonce, verifier)
+			if err != nil {
+				t.Fatalf("AuthCodeURL() error = %v", err)
+			}
+			code := m.authorize(authURL, tt.claims)
+			if tt.code != nil {
+				code = tt.code(code)
+			}
+			if tt.verifier != nil {
+				verifier = tt.verifier(verifier)
+			}
+			if tt.nonce != nil {
+				nonce = tt.nonce(nonce)
+			}
+
+			got, err := provider.Exchange(ctx, code, nonce, verifier)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("Exchange() error = nil, want error")
+				}
+				if tt.wantErr != errAny && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Exchange() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("Exchange() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	MustInit(OidcConfig{
+		StateExpire: 600,
+		Providers: []ProviderConfig{
+			{Name: "zeta", Issuer: "https://zeta.example.com", ClientID: "c", RedirectURL: testRedirectURL},
+			{Name: "alpha", DisplayName: "Alpha", Issuer: "https://alpha.example.com", ClientID: "c", RedirectURL: testRedirectURL},
+		},
+	})
+	t.Cleanup(func() { MustInit(NewDefaultOidcCfg()) })
+
+	var names []string
+	for _, provider := range List() {
+		names = append(names, provider.Name()+":"+provider.DisplayName())
+	}
+	if got, want := strings.Join(names, ","), "alpha:Alpha,zeta:zeta"; got != want {
+		t.Errorf("List() = %s, want %s", got, want)
+	}
+	if _, err := Get("missing"); !errors.Is(err, ErrProviderNotFound) {
+		t.Errorf("Get(missing) error = %v, want %v", err, ErrProviderNotFound)
+	}
+}
+
+// errAny 表示只要求返回错误，不关心具体类型
+var errAny = errors.New("any error")
+
+func s256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeOAuthError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
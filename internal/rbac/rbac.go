@@ -0,0 +1,55 @@
+package rbac
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+const (
+	ROLE_USER   = 0 // 普通用户
+	ROLE_ADMIN  = 1 // 系统管理员
+	ROLE_EDITOR = 2 // 编辑，可管理所有文章
+	ROLE_AUTHOR = 3 // 作者，只能管理自己的文章
+)
+
+var Config = NewDefaultRbacCfg()
+
+type RbacConfig struct {
+	// 初始管理员的用户ID，须为已注册的用户。启动时系统中没有管理员才授予管理员角色，为0时不处理
+	InitialAdminUserID int `json:"initialAdminUserID,omitempty" yaml:"initialAdminUserID,omitempty"`
+}
+
+func NewDefaultRbacCfg() RbacConfig {
+	return RbacConfig{}
+}
+
+func MustInit(cfg RbacConfig) {
+	if cfg.InitialAdminUserID < 0 {
+		panic("rbac initial admin user id must not be negative")
+	}
+	Config = cfg
+}
+
+// ValidRole 是否为系统支持的角色
+func ValidRole(role int32) bool {
+	return role >= ROLE_USER && role <= ROLE_AUTHOR
+}
+
+// RolesFromContext 获取JWTAuth中间件存放的用户角色
+func RolesFromContext(c *gin.Context) []int32 {
+	value, _ := c.Get(utils.CONTEXT_USER_ROLES)
+	roles, _ := value.([]int32)
+	return roles
+}
+
+// HasAnyRole 用户是否拥有required中的任一角色
+func HasAnyRole(roles []int32, required ...int32) bool {
+	for _, role := range required {
+		if slices.Contains(roles, role) {
+			return true
+		}
+	}
+	return false
+}
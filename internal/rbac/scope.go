@@ -0,0 +1,45 @@
+package rbac
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+// 个人访问token的权限范围，token只能访问声明了对应范围的接口
+const (
+	SCOPE_ARTICLE_WRITE = "article:write" // 管理文章、草稿和版本
+	SCOPE_IMAGE_UPLOAD  = "image:upload"  // 上传图片
+)
+
+var scopes = []string{SCOPE_ARTICLE_WRITE, SCOPE_IMAGE_UPLOAD}
+
+// TokenPrincipal 个人访问token认证通过后的用户信息
+type TokenPrincipal struct {
+	UserID int
+	Roles  []int32
+	Scopes []string
+}
+
+// ValidScope 是否为系统支持的权限范围
+func ValidScope(scope string) bool {
+	return slices.Contains(scopes, scope)
+}
+
+// ScopesFromContext 获取JWTAuth中间件存放的token权限范围，使用JWT登录时为nil，表示不限制
+func ScopesFromContext(c *gin.Context) []string {
+	value, _ := c.Get(utils.CONTEXT_TOKEN_SCOPES)
+	scopes, _ := value.([]string)
+	return scopes
+}
+
+// HasAllScopes token是否拥有required中的所有权限范围
+func HasAllScopes(owned []string, required ...string) bool {
+	for _, scope := range required {
+		if !slices.Contains(owned, scope) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package related
+
+import (
+	"math"
+	"sort"
+
+	"github.com/narcissus1949/narcissus-blog/internal/search"
+)
+
+var Config = NewDefaultRelatedCfg()
+
+type RelatedConfig struct {
+	DefaultLimit    int     `json:"defaultLimit,omitempty" yaml:"defaultLimit,omitempty"`       // 未指定limit时返回的相关文章数
+	MaxLimit        int     `json:"maxLimit,omitempty" yaml:"maxLimit,omitempty"`               // 允许请求的最大相关文章数，也是预计算保存的数量
+	TagWeight       float64 `json:"tagWeight,omitempty" yaml:"tagWeight,omitempty"`             // 共同标签得分权重
+	CategoryWeight  float64 `json:"categoryWeight,omitempty" yaml:"categoryWeight,omitempty"`   // 相同分类得分权重
+	ContentWeight   float64 `json:"contentWeight,omitempty" yaml:"contentWeight,omitempty"`     // 内容相似度得分权重
+	RebuildInterval int     `json:"rebuildInterval,omitempty" yaml:"rebuildInterval,omitempty"` // 重新计算的间隔，单位: 分钟
+}
+
+func NewDefaultRelatedCfg() RelatedConfig {
+	return RelatedConfig{
+		DefaultLimit:    5,
+		MaxLimit:        20,
+		TagWeight:       0.5,
+		CategoryWeight:  0.2,
+		ContentWeight:   0.3,
+		RebuildInterval: 60,
+	}
+}
+
+func MustInit(cfg RelatedConfig) {
+	if cfg.DefaultLimit <= 0 || cfg.MaxLimit < cfg.DefaultLimit {
+		panic("related default limit must be greater than 0 and not greater than max limit")
+	}
+	if cfg.TagWeight < 0 || cfg.CategoryWeight < 0 || cfg.ContentWeight < 0 {
+		panic("related weight must not be negative")
+	}
+	if cfg.TagWeight+cfg.CategoryWeight+cfg.ContentWeight <= 0 {
+		panic("related weight must not be all 0")
+	}
+	if cfg.RebuildInterval <= 0 {
+		panic("related rebuild interval must be greater than 0")
+	}
+	Config = cfg
+}
+
+// Document 参与计算的文章，Text应为去除Markdown标记后的标题和正文
+type Document struct {
+	ID         int64
+	CategoryID *int
+	Tags       []string
+	Text       string
+}
+
+// Item 相关文章及其得分
+type Item struct {
+	ID    int64   `json:"id"`
+	Score float64 `json:"score"`
+}
+
+// Compute 计算每篇文章得分最高的limit篇相关文章，得分为共同标签的Jaccard系数、
+// 是否同一分类、正文TF-IDF余弦相似度的加权和，得分为0的文章不算相关
+func Compute(docs []Document, limit int) map[int64][]Item {
+	vectors := tfidfVectors(docs)
+	// 倒排表，只遍历与当前文章有共同词的文章计算余弦相似度
+	postings := map[string][]int{}
+	for i := range vectors {
+		for term := range vectors[i] {
+			postings[term] = append(postings[term], i)
+		}
+	}
+	tagSets := make([]map[string]bool, len(docs))
+	for i := range docs {
+		tagSets[i] = map[string]bool{}
+		for _, tag := range docs[i].Tags {
+			tagSets[i][tag] = true
+		}
+	}
+
+	result := make(map[int64][]Item, len(docs))
+	for i := range docs {
+		similarity := map[int]float64{}
+		for term, weight := range vectors[i] {
+			for _, j := range postings[term] {
+				if j != i {
+					similarity[j] += weight * vectors[j][term]
+				}
+			}
+		}
+
+		var items []Item
+		for j := range docs {
+			if j == i {
+				continue
+			}
+			score := Config.ContentWeight*similarity[j] + Config.TagWeight*jaccard(tagSets[i], tagSets[j])
+			if docs[i].CategoryID != nil && docs[j].CategoryID != nil && *docs[i].CategoryID == *docs[j].CategoryID {
+				score += Config.CategoryWeight
+			}
+			if score > 0 {
+				items = append(items, Item{ID: docs[j].ID, Score: score})
+			}
+		}
+		// 得分相同时较新的文章在前
+		sort.Slice(items, func(a, b int) bool {
+			if items[a].Score != items[b].Score {
+				return items[a].Score > items[b].Score
+			}
+			return items[a].ID > items[b].ID
+		})
+		if len(items) > limit {
+			items = items[:limit]
+		}
+		result[docs[i].ID] = items
+	}
+	return result
+}
+
+// tfidfVectors 计算每篇文章归一化后的TF-IDF向量，出现在全部文章中的词idf为0，不参与计算
+func tfidfVectors(docs []Document) []map[string]float64 {
+	freqs := make([]map[string]int, len(docs))
+	df := map[string]int{}
+	for i := range docs {
+		freqs[i] = map[string]int{}
+		for _, token := range search.Tokenize(docs[i].Text) {
+			freqs[i][token]++
+		}
+		for term := range freqs[i] {
+			df[term]++
+		}
+	}
+
+	total := float64(len(docs))
+	vectors := make([]map[string]float64, len(docs))
+	for i := range freqs {
+		vector := map[string]float64{}
+		var norm float64
+		for term, tf := range freqs[i] {
+			weight := float64(tf) * math.Log(total/float64(df[term]))
+			if weight <= 0 {
+				continue
+			}
+			vector[term] = weight
+			norm += weight * weight
+		}
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+		vectors[i] = vector
+	}
+	return vectors
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for tag := range a {
+		if b[tag] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,257 @@
+package related
+
+import (
+	"math"
+	"testing"
+)
+
+// useConfig 测试期间替换全局配置，结束后恢复
+func useConfig(t *testing.T, tagWeight, categoryWeight, contentWeight float64) {
+	t.Helper()
+	old := Config
+	cfg := NewDefaultRelatedCfg()
+	cfg.TagWeight, cfg.CategoryWeight, cfg.ContentWeight = tagWeight, categoryWeight, contentWeight
+	MustInit(cfg)
+	t.Cleanup(func() { Config = old })
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights [3]float64 // 标签、分类、内容权重
+		docs    []Document
+		limit   int
+		want    map[int64][]Item
+	}{
+		{
+			name:    "tag jaccard",
+			weights: [3]float64{1, 0, 0},
+			docs: []Document{
+				{ID: 1, Tags: []string{"go", "db"}},
+				{ID: 2, Tags: []string{"go"}},
+				{ID: 3, Tags: []string{"rust"}},
+				{ID: 4, Tags: []string{"go", "db"}},
+			},
+			limit: 5,
+			want: map[int64][]Item{
+				1: {{ID: 4, Score: 1}, {ID: 2, Score: 0.5}},
+				2: {{ID: 4, Score: 0.5}, {ID: 1, Score: 0.5}},
+				3: nil,
+				4: {{ID: 1, Score: 1}, {ID: 2, Score: 0.5}},
+			},
+		},
+		{
+			name:    "same category",
+			weights: [3]float64{0, 1, 0},
+			docs: []Document{
+				{ID: 1, CategoryID: intPtr(1)},
+				{ID: 2, CategoryID: intPtr(1)},
+				{ID: 3, CategoryID: intPtr(2)},
+				{ID: 4},
+			},
+			limit: 5,
+			want: map[int64][]Item{
+				1: {{ID: 2, Score: 1}},
+				2: {{ID: 1, Score: 1}},
+				3: nil,
+				4: nil,
+			},
+		},
+		{
+			name:    "limit keeps newest on tie",
+			weights: [3]float64{0, 1, 0},
+			docs: []Document{
+				{ID: 1, CategoryID: intPtr(1)},
+				{ID: 2, CategoryID: intPtr(1)},
+				{ID: 3, CategoryID: intPtr(1)},
+				{ID: 4, CategoryID: intPtr(1)},
+			},
+			limit: 2,
+			want: map[int64][]Item{
+				1: {{ID: 4, Score: 1}, {ID: 3, Score: 1}},
+				2: {{ID: 4, Score: 1}, {ID: 3, Score: 1}},
+				3: {{ID: 4, Score: 1}, {ID: 2, Score: 1}},
+				4: {{ID: 3, Score: 1}, {ID: 2, Score: 1}},
+			},
+		},
+		{
+			name:    "weighted sum",
+			weights: [3]float64{0.5, 0.2, 0.3},
+			docs: []Document{
+				{ID: 1, CategoryID: intPtr(1), Tags: []string{"go"}},
+				{ID: 2, CategoryID: intPtr(1), Tags: []string{"go", "db"}},
+				{ID: 3, CategoryID: intPtr(2), Tags: []string{"go"}},
+			},
+			limit: 5,
+			want: map[int64][]Item{
+				1: {{ID: 3, Score: 0.5}, {ID: 2, Score: 0.45}},
+				2: {{ID: 1, Score: 0.45}, {ID: 3, Score: 0.25}},
+				3: {{ID: 1, Score: 0.5}, {ID: 2, Score: 0.25}},
+			},
+		},
+		{
+			name:    "empty",
+			weights: [3]float64{0.5, 0.2, 0.3},
+			docs:    nil,
+			limit:   5,
+			want:    map[int64][]Item{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfig(t, tt.weights[0], tt.weights[1], tt.weights[2])
+			got := Compute(tt.docs, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(Compute()) = %d, want %d", len(got), len(tt.want))
+			}
+			for id, want := range tt.want {
+				if !equalItems(got[id], want) {
+					t.Errorf("Compute()[%d] = %v, want %v", id, got[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeContentSimilarity(t *testing.T) {
+	useConfig(t, 0, 0, 1)
+	docs := []Document{
+		{ID: 1, Text: "golang channel goroutine"},
+		{ID: 2, Text: "golang channel select"},
+		{ID: 3, Text: "golang generics"},
+		{ID: 4, Text: "rust ownership"},
+	}
+	got := Compute(docs, 5)
+	tests := []struct {
+		id   int64
+		want []int64
+	}{
+		{id: 1, want: []int64{2, 3}},
+		{id: 2, want: []int64{1, 3}},
+		{id: 4, want: nil},
+	}
+	for _, tt := range tests {
+		var ids []int64
+		for _, item := range got[tt.id] {
+			ids = append(ids, item.ID)
+			if item.Score <= 0 || item.Score > 1+1e-9 {
+				t.Errorf("Compute()[%d] score %v out of (0, 1]", tt.id, item.Score)
+			}
+		}
+		if len(ids) != len(tt.want) {
+			t.Errorf("Compute()[%d] = %v, want ids %v", tt.id, got[tt.id], tt.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.want[i] {
+				t.Errorf("Compute()[%d] = %v, want ids %v", tt.id, got[tt.id], tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestTfidfVectors(t *testing.T) {
+	vectors := tfidfVectors([]Document{
+		{ID: 1, Text: "common alpha alpha"},
+		{ID: 2, Text: "common beta"},
+		{ID: 3, Text: "common"},
+	})
+	tests := []struct {
+		name string
+		doc  int
+		want map[string]float64
+	}{
+		{name: "term in every doc dropped", doc: 0, want: map[string]float64{"alpha": 1}},
+		{name: "normalized", doc: 1, want: map[string]float64{"beta": 1}},
+		{name: "empty vector", doc: 2, want: map[string]float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := vectors[tt.doc]
+			if len(got) != len(tt.want) {
+				t.Fatalf("vector = %v, want %v", got, tt.want)
+			}
+			for term, weight := range tt.want {
+				if math.Abs(got[term]-weight) > 1e-9 {
+					t.Errorf("vector[%s] = %v, want %v", term, got[term], weight)
+				}
+			}
+		})
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	set := func(tags ...string) map[string]bool {
+		m := map[string]bool{}
+		for _, tag := range tags {
+			m[tag] = true
+		}
+		return m
+	}
+	tests := []struct {
+		name string
+		a, b map[string]bool
+		want float64
+	}{
+		{name: "both empty", a: set(), b: set(), want: 0},
+		{name: "one empty", a: set("go"), b: set(), want: 0},
+		{name: "disjoint", a: set("go"), b: set("rust"), want: 0},
+		{name: "equal", a: set("go", "db"), b: set("db", "go"), want: 1},
+		{name: "partial", a: set("go", "db", "web"), b: set("go", "db", "cli"), want: 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jaccard(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("jaccard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustInit(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(cfg *RelatedConfig)
+		wantPanic bool
+	}{
+		{name: "default", modify: func(*RelatedConfig) {}},
+		{name: "zero default limit", modify: func(cfg *RelatedConfig) { cfg.DefaultLimit = 0 }, wantPanic: true},
+		{name: "max limit below default", modify: func(cfg *RelatedConfig) { cfg.MaxLimit = cfg.DefaultLimit - 1 }, wantPanic: true},
+		{name: "negative weight", modify: func(cfg *RelatedConfig) { cfg.TagWeight = -1 }, wantPanic: true},
+		{name: "all weights zero", modify: func(cfg *RelatedConfig) {
+			cfg.TagWeight, cfg.CategoryWeight, cfg.ContentWeight = 0, 0, 0
+		}, wantPanic: true},
+		{name: "zero rebuild interval", modify: func(cfg *RelatedConfig) { cfg.RebuildInterval = 0 }, wantPanic: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := Config
+			t.Cleanup(func() { Config = old })
+			cfg := NewDefaultRelatedCfg()
+			tt.modify(&cfg)
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("MustInit() panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+			MustInit(cfg)
+		})
+	}
+}
+
+func equalItems(a, b []Item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID || math.Abs(a[i].Score-b[i].Score) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
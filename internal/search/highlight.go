@@ -0,0 +1,97 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightPre  = "<em>"
+	highlightPost = "</em>"
+)
+
+type span struct {
+	start, end int
+}
+
+// matchSpans 查找文本中所有查询词出现的位置(按rune计)，重叠的位置会被合并
+func matchSpans(runes []rune, query string) []span {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	terms := append(segments(query), TokenizeQuery(query)...)
+
+	var spans []span
+	for _, term := range terms {
+		termRunes := []rune(term)
+		for i := 0; i+len(termRunes) <= len(lower); i++ {
+			if string(lower[i:i+len(termRunes)]) == term {
+				spans = append(spans, span{i, i + len(termRunes)})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := []span{spans[0]}
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			last.end = max(last.end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// render 转义HTML并用<em>包裹命中的片段
+func render(runes []rune, spans []span, from, to int) string {
+	var b strings.Builder
+	pos := from
+	for _, s := range spans {
+		if s.end <= from || s.start >= to {
+			continue
+		}
+		start, end := max(s.start, from), min(s.end, to)
+		b.WriteString(html.EscapeString(string(runes[pos:start])))
+		b.WriteString(highlightPre)
+		b.WriteString(html.EscapeString(string(runes[start:end])))
+		b.WriteString(highlightPost)
+		pos = end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	return b.String()
+}
+
+// Highlight 高亮整段文本中的查询词，结果已做HTML转义
+func Highlight(text, query string) string {
+	runes := []rune(text)
+	return render(runes, matchSpans(runes, query), 0, len(runes))
+}
+
+// Snippet 截取第一个命中位置附近最多maxLen个字符并高亮，未命中时返回文本开头
+func Snippet(text, query string, maxLen int) string {
+	runes := []rune(text)
+	spans := matchSpans(runes, query)
+	from := 0
+	if len(spans) > 0 {
+		// 命中位置前保留约四分之一的上下文
+		from = max(spans[0].start-maxLen/4, 0)
+	}
+	to := min(from+maxLen, len(runes))
+	from = max(to-maxLen, 0)
+
+	snippet := render(runes, spans, from, to)
+	if from > 0 {
+		snippet = "..." + snippet
+	}
+	if to < len(runes) {
+		snippet += "..."
+	}
+	return snippet
+}
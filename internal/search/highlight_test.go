@@ -0,0 +1,47 @@
+package search
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{name: "no hit", text: "hello", query: "world", want: "hello"},
+		{name: "case insensitive keeps original", text: "Go and GO", query: "go", want: "<em>Go</em> and <em>GO</em>"},
+		{name: "escape html", text: "Go语言<入门>", query: "go 入门", want: "<em>Go</em>语言&lt;<em>入门</em>&gt;"},
+		{name: "overlapping terms merged", text: "Go语言入门", query: "语言入门", want: "Go<em>语言入门</em>"},
+		{name: "single cjk", text: "中文", query: "中", want: "<em>中</em>文"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, tt.query); got != tt.want {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		query  string
+		maxLen int
+		want   string
+	}{
+		{name: "short text", text: "包含搜索", query: "搜索", maxLen: 10, want: "包含<em>搜索</em>"},
+		{name: "context around hit", text: "这是一段很长的文本，其中包含关键词搜索，后面还有很多内容", query: "搜索", maxLen: 10, want: "...键词<em>搜索</em>，后面还有很..."},
+		{name: "hit near end", text: "这是一段很长的文本包含搜索", query: "搜索", maxLen: 6, want: "...文本包含<em>搜索</em>"},
+		{name: "no hit returns head", text: "没有命中的文本内容很长很长", query: "搜索", maxLen: 5, want: "没有命中的..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Snippet(tt.text, tt.query, tt.maxLen); got != tt.want {
+				t.Errorf("Snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
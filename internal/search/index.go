@@ -0,0 +1,172 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// 各字段词频权重，标题命中比正文更重要
+const (
+	titleWeight   = 3
+	summaryWeight = 2
+	contentWeight = 1
+)
+
+// Default 全局文章索引
+var Default = NewIndex()
+
+// Document 待索引的文档，Content应为去除Markdown标记后的纯文本
+type Document struct {
+	ID      int64
+	Title   string
+	Summary string
+	Content string
+}
+
+// Hit 搜索命中结果
+type Hit struct {
+	ID    int64
+	Score float64
+}
+
+type indexedDoc struct {
+	doc    Document
+	length int // 加权后的词数
+}
+
+// Index 内存倒排索引，并发安全
+type Index struct {
+	mu          sync.RWMutex
+	docs        map[int64]*indexedDoc
+	postings    map[string]map[int64]int // term -> docID -> 加权词频
+	totalLength int
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     map[int64]*indexedDoc{},
+		postings: map[string]map[int64]int{},
+	}
+}
+
+// Upsert 新增或更新文档
+func (idx *Index) Upsert(doc Document) {
+	freq := map[string]int{}
+	length := 0
+	for _, field := range []struct {
+		text   string
+		weight int
+	}{
+		{doc.Title, titleWeight},
+		{doc.Summary, summaryWeight},
+		{doc.Content, contentWeight},
+	} {
+		for _, token := range Tokenize(field.text) {
+			freq[token] += field.weight
+			length += field.weight
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(doc.ID)
+	for token, tf := range freq {
+		posting, ok := idx.postings[token]
+		if !ok {
+			posting = map[int64]int{}
+			idx.postings[token] = posting
+		}
+		posting[doc.ID] = tf
+	}
+	idx.docs[doc.ID] = &indexedDoc{doc: doc, length: length}
+	idx.totalLength += length
+}
+
+// Remove 删除文档，文档不存在时忽略
+func (idx *Index) Remove(ids ...int64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, id := range ids {
+		idx.remove(id)
+	}
+}
+
+func (idx *Index) remove(id int64) {
+	old, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for _, token := range Tokenize(old.doc.Title + " " + old.doc.Summary + " " + old.doc.Content) {
+		if posting, ok := idx.postings[token]; ok {
+			delete(posting, id)
+			if len(posting) == 0 {
+				delete(idx.postings, token)
+			}
+		}
+	}
+	idx.totalLength -= old.length
+	delete(idx.docs, id)
+}
+
+// Reset 用给定文档替换整个索引
+func (idx *Index) Reset(docs []Document) {
+	fresh := NewIndex()
+	for i := range docs {
+		fresh.Upsert(docs[i])
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs = fresh.docs
+	idx.postings = fresh.postings
+	idx.totalLength = fresh.totalLength
+}
+
+// Search 按BM25打分返回全部命中文档，命中的查询词越多排名越靠前
+func (idx *Index) Search(query string) []Hit {
+	terms := TokenizeQuery(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if len(idx.docs) == 0 {
+		return nil
+	}
+	n := float64(len(idx.docs))
+	avgLength := float64(idx.totalLength) / n
+	scores := map[int64]float64{}
+	matched := map[int64]int{}
+	for _, term := range terms {
+		posting := idx.postings[term]
+		if len(posting) == 0 {
+			continue
+		}
+		df := float64(len(posting))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range posting {
+			norm := 1 - bm25B + bm25B*float64(idx.docs[id].length)/avgLength
+			scores[id] += idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1*norm)
+			matched[id]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score * float64(matched[id]) / float64(len(terms))})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	return hits
+}
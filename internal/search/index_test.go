@@ -0,0 +1,128 @@
+package search
+
+import (
+	"testing"
+)
+
+func TestIndexSearch(t *testing.T) {
+	idx := NewIndex()
+	idx.Reset([]Document{
+		{ID: 1, Title: "Go语言入门", Content: "介绍Go语言的基础语法"},
+		{ID: 2, Title: "数据库", Content: "使用Go语言访问MySQL"},
+		{ID: 3, Title: "读书笔记", Summary: "语言学", Content: "语言与思维"},
+		{ID: 4, Title: "旅行", Content: "周末去爬山"},
+	})
+	tests := []struct {
+		name  string
+		query string
+		want  []int64
+	}{
+		{name: "title match ranks first", query: "Go语言", want: []int64{1, 2, 3}},
+		{name: "all terms matched ranks first", query: "mysql 语言", want: []int64{2, 1, 3}},
+		{name: "single cjk", query: "爬", want: []int64{4}},
+		{name: "case insensitive", query: "MYSQL", want: []int64{2}},
+		{name: "no hit", query: "rust", want: nil},
+		{name: "empty query", query: "，", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := idx.Search(tt.query)
+			if got := hitIDs(hits); !equalIDs(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := 1; i < len(hits); i++ {
+				if hits[i].Score > hits[i-1].Score {
+					t.Errorf("hits not sorted by score: %v", hits)
+				}
+			}
+		})
+	}
+}
+
+func TestIndexUpsertAndRemove(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(idx *Index)
+		query  string
+		want   []int64
+	}{
+		{
+			name:   "upsert replaces old terms",
+			update: func(idx *Index) { idx.Upsert(Document{ID: 1, Title: "Rust入门"}) },
+			query:  "golang",
+			want:   []int64{2},
+		},
+		{
+			name:   "upsert adds new terms",
+			update: func(idx *Index) { idx.Upsert(Document{ID: 1, Title: "Rust入门"}) },
+			query:  "rust",
+			want:   []int64{1},
+		},
+		{
+			name:   "remove",
+			update: func(idx *Index) { idx.Remove(2) },
+			query:  "golang",
+			want:   []int64{1},
+		},
+		{
+			name:   "remove missing id",
+			update: func(idx *Index) { idx.Remove(100) },
+			query:  "golang",
+			want:   []int64{2, 1},
+		},
+		{
+			name:   "equal score ordered by id desc",
+			update: func(*Index) {},
+			query:  "golang",
+			want:   []int64{2, 1},
+		},
+		{
+			name:   "reset to empty",
+			update: func(idx *Index) { idx.Reset(nil) },
+			query:  "golang",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := NewIndex()
+			idx.Upsert(Document{ID: 1, Title: "golang"})
+			idx.Upsert(Document{ID: 2, Title: "golang"})
+			tt.update(idx)
+			if got := hitIDs(idx.Search(tt.query)); !equalIDs(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIndexRemoveCleansPostings 删除全部文档后不应残留倒排记录和长度
+func TestIndexRemoveCleansPostings(t *testing.T) {
+	idx := NewIndex()
+	idx.Upsert(Document{ID: 1, Title: "Go语言", Summary: "摘要", Content: "正文"})
+	idx.Upsert(Document{ID: 1, Title: "新标题", Content: "新的正文"})
+	idx.Remove(1)
+	if len(idx.postings) != 0 || len(idx.docs) != 0 || idx.totalLength != 0 {
+		t.Errorf("index not empty after remove: postings=%d docs=%d totalLength=%d", len(idx.postings), len(idx.docs), idx.totalLength)
+	}
+}
+
+func hitIDs(hits []Hit) []int64 {
+	var ids []int64
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
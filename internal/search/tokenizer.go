@@ -0,0 +1,112 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	markdownCodeFenceReg = regexp.MustCompile("(?m)^\\s*(```|~~~).*$")
+	markdownImageReg     = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLinkReg      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownHtmlTagReg   = regexp.MustCompile(`<[^>]+>`)
+	markdownPrefixReg    = regexp.MustCompile(`(?m)^\s{0,3}(#{1,6}|>+|[-*+]|\d+\.)\s+`)
+	markdownEmphasisReg  = regexp.MustCompile("[*_~`]+")
+	spaceReg             = regexp.MustCompile(`\s+`)
+)
+
+// PlainText 去除Markdown标记，返回用于建立索引和生成摘要的纯文本
+func PlainText(markdown string) string {
+	text := markdownCodeFenceReg.ReplaceAllString(markdown, "")
+	text = markdownImageReg.ReplaceAllString(text, "$1")
+	text = markdownLinkReg.ReplaceAllString(text, "$1")
+	text = markdownHtmlTagReg.ReplaceAllString(text, " ")
+	text = markdownPrefixReg.ReplaceAllString(text, "")
+	text = markdownEmphasisReg.ReplaceAllString(text, "")
+	return strings.TrimSpace(spaceReg.ReplaceAllString(text, " "))
+}
+
+// isCJK 判断是否为中日韩文字，这类文字之间没有空格分隔
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// segments 将文本切分为连续的CJK片段和字母数字单词，其余字符作为分隔符
+func segments(text string) []string {
+	var result []string
+	var current []rune
+	currentCJK := false
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, string(current))
+			current = current[:0]
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			if !currentCJK {
+				flush()
+			}
+			currentCJK = true
+			current = append(current, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if currentCJK {
+				flush()
+			}
+			currentCJK = false
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return result
+}
+
+// Tokenize 文档分词：字母数字按单词切分，CJK片段同时输出单字和相邻二元组，
+// 使单字和多字查询都能命中
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, seg := range segments(text) {
+		runes := []rune(seg)
+		if !isCJK(runes[0]) {
+			tokens = append(tokens, seg)
+			continue
+		}
+		for i := range runes {
+			tokens = append(tokens, string(runes[i]))
+			if i+1 < len(runes) {
+				tokens = append(tokens, string(runes[i:i+2]))
+			}
+		}
+	}
+	return tokens
+}
+
+// TokenizeQuery 查询分词：CJK片段只输出相邻二元组，单字片段输出单字，结果去重
+func TokenizeQuery(query string) []string {
+	var tokens []string
+	seen := map[string]bool{}
+	add := func(token string) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	for _, seg := range segments(query) {
+		runes := []rune(seg)
+		if !isCJK(runes[0]) || len(runes) == 1 {
+			add(seg)
+			continue
+		}
+		for i := 0; i+1 < len(runes); i++ {
+			add(string(runes[i : i+2]))
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,72 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{name: "heading", markdown: "## 标题", want: "标题"},
+		{name: "code fence", markdown: "```go\nfmt.Println(1)\n```", want: "fmt.Println(1)"},
+		{name: "quote and emphasis", markdown: "> 引用 **加粗** _斜体_ `code`", want: "引用 加粗 斜体 code"},
+		{name: "link and image", markdown: "[链接](https://example.com) ![图片](a.png)", want: "链接 图片"},
+		{name: "html tag", markdown: "<b>html</b>", want: "html"},
+		{name: "list", markdown: "- 列表\n1. 有序\n* 星号", want: "列表 有序 星号"},
+		{name: "collapse spaces", markdown: "  a \n\n\t b  ", want: "a b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlainText(tt.markdown); got != tt.want {
+				t.Errorf("PlainText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "words lower case", text: "Hello-World 2024", want: []string{"hello", "world", "2024"}},
+		{name: "cjk unigram and bigram", text: "语言", want: []string{"语", "语言", "言"}},
+		{name: "single cjk", text: "中", want: []string{"中"}},
+		{name: "mixed", text: "Go语言 入门", want: []string{"go", "语", "语言", "言", "入", "入门", "门"}},
+		{name: "kana and hangul", text: "カナ 한국", want: []string{"カ", "カナ", "ナ", "한", "한국", "국"}},
+		{name: "punctuation only", text: "，。!?", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty", query: "  ", want: nil},
+		{name: "cjk bigram only", query: "Go语言入门", want: []string{"go", "语言", "言入", "入门"}},
+		{name: "single cjk", query: "中", want: []string{"中"}},
+		{name: "dedup", query: "go GO 语言 语言", want: []string{"go", "语言"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TokenizeQuery(tt.query); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("TokenizeQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
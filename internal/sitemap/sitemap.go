@@ -0,0 +1,120 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// 单个sitemap文件允许的最大URL数，超过后需拆分并生成sitemap index
+const MaxURLs = 50000
+
+var Config = NewDefaultSitemapCfg()
+
+type SitemapConfig struct {
+	CacheExpire int      `json:"cacheExpire,omitempty" yaml:"cacheExpire,omitempty"` // sitemap缓存时间，单位: 分钟
+	Allow       []string `json:"allow,omitempty" yaml:"allow,omitempty"`             // robots.txt允许抓取的路径
+	Disallow    []string `json:"disallow,omitempty" yaml:"disallow,omitempty"`       // robots.txt禁止抓取的路径
+}
+
+func NewDefaultSitemapCfg() SitemapConfig {
+	return SitemapConfig{
+		CacheExpire: 24 * 60,
+		Allow:       []string{"/"},
+		Disallow:    []string{"/api/"},
+	}
+}
+
+func MustInit(cfg SitemapConfig) {
+	if cfg.CacheExpire <= 0 {
+		panic("sitemap cache expire must be greater than 0")
+	}
+	Config = cfg
+}
+
+type URL struct {
+	Loc     string
+	LastMod time.Time // 为零值时不输出lastmod
+}
+
+type urlSet struct {
+	XMLName xml.Name  `xml:"urlset"`
+	NS      string    `xml:"xmlns,attr"`
+	URLs    []urlItem `xml:"url"`
+}
+
+type urlItem struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	NS       string    `xml:"xmlns,attr"`
+	Sitemaps []urlItem `xml:"sitemap"`
+}
+
+// PageCount 计算URL需要拆分的sitemap文件数，不超过MaxURLs时为1
+func PageCount(total int) int {
+	if total <= MaxURLs {
+		return 1
+	}
+	return (total + MaxURLs - 1) / MaxURLs
+}
+
+// Page 返回第page(从1开始)个sitemap文件包含的URL
+func Page(urls []URL, page int) []URL {
+	start := (page - 1) * MaxURLs
+	if page < 1 || start >= len(urls) {
+		return nil
+	}
+	return urls[start:min(start+MaxURLs, len(urls))]
+}
+
+// EncodeURLSet 生成urlset格式的sitemap
+func EncodeURLSet(urls []URL) ([]byte, error) {
+	set := urlSet{NS: "http://www.sitemaps.org/schemas/sitemap/0.9", URLs: make([]urlItem, 0, len(urls))}
+	for _, u := range urls {
+		set.URLs = append(set.URLs, newURLItem(u))
+	}
+	return marshalXML(set)
+}
+
+// EncodeIndex 生成sitemap index，sitemaps为各个子sitemap的地址和最后修改时间
+func EncodeIndex(sitemaps []URL) ([]byte, error) {
+	index := sitemapIndex{NS: "http://www.sitemaps.org/schemas/sitemap/0.9", Sitemaps: make([]urlItem, 0, len(sitemaps))}
+	for _, s := range sitemaps {
+		index.Sitemaps = append(index.Sitemaps, newURLItem(s))
+	}
+	return marshalXML(index)
+}
+
+// Robots 根据配置生成robots.txt
+func Robots(sitemapURL string) []byte {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	for _, path := range Config.Allow {
+		b.WriteString("Allow: " + path + "\n")
+	}
+	for _, path := range Config.Disallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\nSitemap: " + sitemapURL + "\n")
+	return []byte(b.String())
+}
+
+func newURLItem(u URL) urlItem {
+	item := urlItem{Loc: u.Loc}
+	if !u.LastMod.IsZero() {
+		item.LastMod = u.LastMod.Format(time.RFC3339)
+	}
+	return item
+}
+
+func marshalXML(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,160 @@
+package sitemap
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageCount(t *testing.T) {
+	tests := []struct {
+		total int
+		want  int
+	}{
+		{total: 0, want: 1},
+		{total: 1, want: 1},
+		{total: MaxURLs, want: 1},
+		{total: MaxURLs + 1, want: 2},
+		{total: 2 * MaxURLs, want: 2},
+		{total: 2*MaxURLs + 1, want: 3},
+	}
+	for _, tt := range tests {
+		if got := PageCount(tt.total); got != tt.want {
+			t.Errorf("PageCount(%d) = %d, want %d", tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	urls := make([]URL, 2*MaxURLs+10)
+	for i := range urls {
+		urls[i] = URL{Loc: "/post/" + strconv.Itoa(i)}
+	}
+	tests := []struct {
+		name      string
+		page      int
+		wantLen   int
+		wantFirst string
+	}{
+		{name: "page zero", page: 0, wantLen: 0},
+		{name: "negative page", page: -1, wantLen: 0},
+		{name: "first page", page: 1, wantLen: MaxURLs, wantFirst: "/post/0"},
+		{name: "second page", page: 2, wantLen: MaxURLs, wantFirst: "/post/" + strconv.Itoa(MaxURLs)},
+		{name: "last partial page", page: 3, wantLen: 10, wantFirst: "/post/" + strconv.Itoa(2*MaxURLs)},
+		{name: "out of range", page: 4, wantLen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Page(urls, tt.page)
+			if len(got) != tt.wantLen {
+				t.Fatalf("len(Page(%d)) = %d, want %d", tt.page, len(got), tt.wantLen)
+			}
+			if tt.wantLen > 0 && got[0].Loc != tt.wantFirst {
+				t.Errorf("Page(%d)[0] = %s, want %s", tt.page, got[0].Loc, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	lastMod := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		encode func() ([]byte, error)
+		want   string
+	}{
+		{
+			name: "urlset",
+			encode: func() ([]byte, error) {
+				return EncodeURLSet([]URL{
+					{Loc: "https://blog.example.com/"},
+					{Loc: "https://blog.example.com/post/go?a=1&b=2", LastMod: lastMod},
+				})
+			},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://blog.example.com/</loc>
+  </url>
+  <url>
+    <loc>https://blog.example.com/post/go?a=1&amp;b=2</loc>
+    <lastmod>2024-05-01T08:30:00Z</lastmod>
+  </url>
+</urlset>`,
+		},
+		{
+			name:   "empty urlset",
+			encode: func() ([]byte, error) { return EncodeURLSet(nil) },
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"></urlset>`,
+		},
+		{
+			name: "index",
+			encode: func() ([]byte, error) {
+				return EncodeIndex([]URL{{Loc: "https://blog.example.com/sitemap-1.xml", LastMod: lastMod}})
+			},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>https://blog.example.com/sitemap-1.xml</loc>
+    <lastmod>2024-05-01T08:30:00Z</lastmod>
+  </sitemap>
+</sitemapindex>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.encode()
+			if err != nil {
+				t.Fatalf("encode error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("encode =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobots(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SitemapConfig
+		want string
+	}{
+		{
+			name: "default",
+			cfg:  NewDefaultSitemapCfg(),
+			want: "User-agent: *\nAllow: /\nDisallow: /api/\n\nSitemap: https://blog.example.com/sitemap.xml\n",
+		},
+		{
+			name: "no rules",
+			cfg:  SitemapConfig{CacheExpire: 1},
+			want: "User-agent: *\n\nSitemap: https://blog.example.com/sitemap.xml\n",
+		},
+		{
+			name: "multiple disallow",
+			cfg:  SitemapConfig{CacheExpire: 1, Disallow: []string{"/api/", "/admin/"}},
+			want: "User-agent: *\nDisallow: /api/\nDisallow: /admin/\n\nSitemap: https://blog.example.com/sitemap.xml\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := Config
+			t.Cleanup(func() { Config = old })
+			MustInit(tt.cfg)
+			if got := string(Robots("https://blog.example.com/sitemap.xml")); got != tt.want {
+				t.Errorf("Robots() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustInitPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "cache expire") {
+			t.Errorf("MustInit() panic = %v, want cache expire panic", r)
+		}
+	}()
+	MustInit(SitemapConfig{})
+}
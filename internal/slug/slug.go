@@ -0,0 +1,75 @@
+package slug
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// 自动生成的slug最大长度
+const MaxLen = 100
+
+var (
+	slugReg     = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	pinyinArgs  = pinyin.NewArgs()
+	defaultSlug = "article"
+)
+
+// Valid 判断slug格式: 小写字母、数字，单词之间用-连接
+func Valid(s string) bool {
+	return len(s) <= MaxLen && slugReg.MatchString(s)
+}
+
+// Generate 由标题生成slug，汉字转为不带声调的拼音，其余非字母数字字符作为分隔符
+func Generate(title string) string {
+	var words []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range title {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			current.WriteRune(unicode.ToLower(r))
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 && py[0] != "" {
+				words = append(words, py[0])
+			}
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	// 超长时按单词截断
+	var b strings.Builder
+	for _, word := range words {
+		if b.Len() > 0 && b.Len()+1+len(word) > MaxLen {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(word)
+	}
+	if b.Len() == 0 || b.Len() > MaxLen {
+		return defaultSlug
+	}
+	return b.String()
+}
+
+// WithSuffix 为重复的slug追加序号，如 go-bing-fa-2
+func WithSuffix(s string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	if len(s)+len(suffix) > MaxLen {
+		s = strings.TrimRight(s[:MaxLen-len(suffix)], "-")
+	}
+	return s + suffix
+}
@@ -0,0 +1,84 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		slug string
+		want bool
+	}{
+		{"go-concurrency", true},
+		{"go", true},
+		{"2024-review", true},
+		{"", false},
+		{"Go", false},
+		{"go--concurrency", false},
+		{"-go", false},
+		{"go-", false},
+		{"go_concurrency", false},
+		{"go concurrency", false},
+		{"并发", false},
+		{strings.Repeat("a", MaxLen), true},
+		{strings.Repeat("a", MaxLen+1), false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.slug); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.slug, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "ascii", title: "Hello, World!", want: "hello-world"},
+		{name: "pinyin", title: "Go并发编程", want: "go-bing-fa-bian-cheng"},
+		{name: "digits", title: "2024年总结", want: "2024-nian-zong-jie"},
+		{name: "non ascii letters dropped", title: "Café Ölfass", want: "caf-lfass"},
+		{name: "symbols only", title: "!!! ???", want: defaultSlug},
+		{name: "empty", title: "", want: defaultSlug},
+		{name: "truncate by word", title: strings.Repeat("abcdefghi ", 11), want: strings.TrimSuffix(strings.Repeat("abcdefghi-", 10), "-")},
+		{name: "single word too long", title: strings.Repeat("a", MaxLen+1), want: defaultSlug},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Generate(tt.title)
+			if got != tt.want {
+				t.Errorf("Generate(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+			if !Valid(got) {
+				t.Errorf("Generate(%q) = %q is not a valid slug", tt.title, got)
+			}
+		})
+	}
+}
+
+func TestWithSuffix(t *testing.T) {
+	tests := []struct {
+		name string
+		slug string
+		n    int
+		want string
+	}{
+		{name: "short", slug: "go-bing-fa", n: 2, want: "go-bing-fa-2"},
+		{name: "truncate", slug: strings.Repeat("a", MaxLen), n: 12, want: strings.Repeat("a", MaxLen-3) + "-12"},
+		{name: "trim trailing dash", slug: strings.Repeat("a", MaxLen-3) + "-bc", n: 2, want: strings.Repeat("a", MaxLen-3) + "-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WithSuffix(tt.slug, tt.n)
+			if got != tt.want {
+				t.Errorf("WithSuffix() = %q, want %q", got, tt.want)
+			}
+			if !Valid(got) {
+				t.Errorf("WithSuffix() = %q is not a valid slug", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package trash
+
+import "time"
+
+var Config = NewDefaultTrashCfg()
+
+type TrashConfig struct {
+	Retention     int `json:"retention,omitempty" yaml:"retention,omitempty"`         // 回收站保留时间，超过后永久删除，单位: 天
+	PurgeInterval int `json:"purgeInterval,omitempty" yaml:"purgeInterval,omitempty"` // 清理过期内容的间隔，单位: 分钟
+}
+
+func NewDefaultTrashCfg() TrashConfig {
+	return TrashConfig{
+		Retention:     30,
+		PurgeInterval: 60,
+	}
+}
+
+func MustInit(cfg TrashConfig) {
+	if cfg.Retention <= 0 {
+		panic("trash retention must be greater than 0")
+	}
+	if cfg.PurgeInterval <= 0 {
+		panic("trash purge interval must be greater than 0")
+	}
+	Config = cfg
+}
+
+// ExpireBefore 移入回收站的时间早于该时间的内容已过期
+func ExpireBefore(now time.Time) time.Time {
+	return now.AddDate(0, 0, -Config.Retention)
+}
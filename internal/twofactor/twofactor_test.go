@@ -0,0 +1,157 @@
+package twofactor
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestGenerateKey(t *testing.T) {
+	key, err := GenerateKey("narcissus-blog", "alice")
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	u, err := url.Parse(key.OtpauthURI)
+	if err != nil {
+		t.Fatalf("parse otpauth uri: %v", err)
+	}
+	query := u.Query()
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"scheme", u.Scheme, "otpauth"},
+		{"type", u.Host, "totp"},
+		{"label", u.Path, "/narcissus-blog:alice"},
+		{"issuer", query.Get("issuer"), "narcissus-blog"},
+		{"secret", query.Get("secret"), key.Secret},
+		{"digits", query.Get("digits"), "6"},
+		{"period", query.Get("period"), "30"},
+		{"algorithm", query.Get("algorithm"), "SHA1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+	if !strings.HasPrefix(key.QRCode, "data:image/png;base64,") {
+		t.Errorf("QRCode = %.40q, want png data uri", key.QRCode)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	key, err := GenerateKey("narcissus-blog", "alice")
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   bool
+	}{
+		{name: "current step", offset: 0, want: true},
+		{name: "previous step", offset: -Period * time.Second, want: true},
+		{name: "next step", offset: Period * time.Second, want: true},
+		{name: "too old", offset: -3 * Period * time.Second, want: false},
+		{name: "too new", offset: 3 * Period * time.Second, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := totp.GenerateCodeCustom(key.Secret, time.Now().Add(tt.offset), totp.ValidateOpts{
+				Period:    Period,
+				Digits:    otp.DigitsSix,
+				Algorithm: otp.AlgorithmSHA1,
+			})
+			if err != nil {
+				t.Fatalf("GenerateCodeCustom() error = %v", err)
+			}
+			if got := Validate(code, key.Secret); got != tt.want {
+				t.Errorf("Validate(%s) = %v, want %v", code, got, tt.want)
+			}
+		})
+	}
+	if Validate("12345", key.Secret) {
+		t.Error("Validate() accepted a 5 digit code")
+	}
+	if Validate("123456", "not base32!") {
+		t.Error("Validate() accepted an invalid secret")
+	}
+}
+
+func TestIsTOTPCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"123456", true},
+		{"000000", true},
+		{"12345", false},
+		{"1234567", false},
+		{"12345a", false},
+		{" 123456", false},
+		{"abcde-fghij", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsTOTPCode(tt.code); got != tt.want {
+			t.Errorf("IsTOTPCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("len(codes) = %d, want %d", len(codes), RecoveryCodeCount)
+	}
+	format := regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`)
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q does not match xxxxx-xxxxx", code)
+		}
+		if IsTOTPCode(code) {
+			t.Errorf("code %q is treated as totp code", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	want := HashRecoveryCode("abcde-fghij")
+	tests := []struct {
+		name string
+		code string
+		same bool
+	}{
+		{name: "same code", code: "abcde-fghij", same: true},
+		{name: "upper case", code: "ABCDE-FGHIJ", same: true},
+		{name: "surrounding spaces", code: "  abcde-fghij\n", same: true},
+		{name: "different code", code: "abcde-fghik", same: false},
+		{name: "missing dash", code: "abcdefghij", same: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HashRecoveryCode(tt.code) == want; got != tt.same {
+				t.Errorf("HashRecoveryCode(%q) == HashRecoveryCode(abcde-fghij) is %v, want %v", tt.code, got, tt.same)
+			}
+		})
+	}
+	if len(want) != 64 {
+		t.Errorf("len(hash) = %d, want 64", len(want))
+	}
+}
@@ -13,17 +13,107 @@ const (
 	ARTICLE_TYPE_ABOUT
 )
 
+const (
+	ARTICLE_STATUS_OFFLINE = iota
+	ARTICLE_STATUS_ONLINE
+)
+
+const (
+	COMMENT_STATUS_PENDING = iota
+	COMMENT_STATUS_APPROVED
+	COMMENT_STATUS_HIDDEN
+)
+
+const (
+	USER_STATUS_PENDING = iota
+	USER_STATUS_NORMAL
+	USER_STATUS_REJECTED
+	USER_STATUS_DISABLED
+)
+
+const (
+	MODERATION_STATUS_PENDING = iota
+	MODERATION_STATUS_APPROVED
+	MODERATION_STATUS_REJECTED
+)
+
+// 审计日志操作类型
+const (
+	AUDIT_ACTION_CREATE         = "create"
+	AUDIT_ACTION_UPDATE         = "update"
+	AUDIT_ACTION_DELETE         = "delete"
+	AUDIT_ACTION_RESTORE        = "restore" // 从回收站恢复
+	AUDIT_ACTION_PURGE          = "purge"   // 从回收站永久删除
+	AUDIT_ACTION_DISABLE        = "disable"
+	AUDIT_ACTION_ENABLE         = "enable"
+	AUDIT_ACTION_PASSWORD_RESET = "password_reset"
+	AUDIT_ACTION_GRANT_ROLE     = "grant_role"
+	AUDIT_ACTION_REVOKE_ROLE    = "revoke_role"
+	AUDIT_ACTION_APPROVE        = "approve" // 审核通过
+	AUDIT_ACTION_REJECT         = "reject"  // 审核拒绝
+	AUDIT_ACTION_HIDE           = "hide"
+)
+
+// 审计日志实体类型
+const (
+	AUDIT_ENTITY_ARTICLE  = "article"
+	AUDIT_ENTITY_CATEGORY = "category"
+	AUDIT_ENTITY_TAG      = "tag"
+	AUDIT_ENTITY_USER     = "user"
+	AUDIT_ENTITY_SERIES   = "series"
+	AUDIT_ENTITY_COMMENT  = "comment"
+)
+
+// 系统任务(启动初始化、定时任务等)审计日志的操作人ID
+const AUDIT_SYSTEM_ACTOR_ID = 0
+
 const (
 	CONTEXT_USER_ID                = "UserID"
+	CONTEXT_USER_ROLES             = "UserRoles"
+	CONTEXT_SESSION_ID             = "SessionID"
+	CONTEXT_TOKEN_SCOPES           = "TokenScopes"
+	PERSONAL_TOKEN_PREFIX          = "nbp_" // 个人访问token前缀，用于与JWT区分
 	ACCESS_TOKEN_BLACKLIST         = "access_token_blacklist:"
 	REFRESH_TOKEN_BLACKLIST        = "refresh_token_blacklist:"
 	ARTICLE_PAGE_VIEW_KEY_TEMPLATE = "article_page_view:%s" // article_id
+	MODERATION_SUBMITTER_KEY       = "moderation_submitter:"
+	USER_TOKEN_REVOKE_KEY_TEMPLATE = "user_token_revoke:%d"       // user_id，值为撤销时间的毫秒时间戳，早于该时间签发的token失效
+	USER_SESSION_KEY_TEMPLATE      = "user_session:%s"            // session_id，hash，会话设备信息和当前refresh token ID
+	USER_SESSIONS_KEY_TEMPLATE     = "user_sessions:%d"           // user_id，set，用户的会话ID
+	PASSWORD_RESET_KEY_TEMPLATE    = "password_reset:%s"          // 重置token的sha256，值为user_id
+	PASSWORD_RESET_USER_KEY        = "password_reset_user:"       // user_id，值为该用户最近一次重置token的sha256
+	LOGIN_FAIL_USER_KEY            = "login_fail_user:"           // username，值为登录失败次数
+	LOGIN_FAIL_IP_KEY              = "login_fail_ip:"             // ip，值为登录失败次数
+	LOGIN_LOCK_USER_KEY            = "login_lock_user:"           // username，存在时禁止登录
+	LOGIN_LOCK_IP_KEY              = "login_lock_ip:"             // ip，存在时禁止登录
+	PASSWORD_FORGOT_USER_KEY       = "password_forgot_user:"      // 用户名或邮箱，值为申请重置密码次数
+	PASSWORD_FORGOT_IP_KEY         = "password_forgot_ip:"        // ip，值为申请重置密码次数
+	PASSWORD_FORGOT_LOCK_USER_KEY  = "password_forgot_lock_user:" // 用户名或邮箱，存在时禁止申请重置密码
+	PASSWORD_FORGOT_LOCK_IP_KEY    = "password_forgot_lock_ip:"   // ip，存在时禁止申请重置密码
+	CAPTCHA_KEY                    = "captcha:"                   // captcha_id，值为验证码文本
+	LOGIN_PREAUTH_KEY_TEMPLATE     = "login_preauth:%s"           // 预认证token的sha256，值为user_id
+	LOGIN_PREAUTH_ATTEMPT_TEMPLATE = "login_preauth_attempt:%s"   // 预认证token的sha256，值为动态验证码错误次数
+	TOTP_USED_KEY_TEMPLATE         = "totp_used:%d:%s"            // user_id, 动态验证码，防止同一验证码重复使用
+	OIDC_STATE_KEY_TEMPLATE        = "oidc_state:%s"              // state的sha256，值为授权请求的nonce、PKCE verifier等
+	SITEMAP_CACHE_KEY_TEMPLATE     = "sitemap:%d:%s"              // 缓存版本, index或分页序号
+	SITEMAP_VERSION_KEY            = "sitemap_version"            // 值为sitemap缓存版本，缓存失效时加1
+	ARTICLE_RENDER_KEY_TEMPLATE    = "article_render:%s:%s"       // 渲染器版本, 文章内容sha256
+	ARTICLE_RELATED_KEY_TEMPLATE   = "article_related:%d"         // article_id，值为预计算的相关文章ID及得分
 
 	COOKIE_TEMP_USER_ID = "temp_user_id"
 
 	X_REQUEST_ID = "X-Request-Id" // 请求ID，用于日志跟踪
 )
 
+// 前台页面路径，用于生成订阅源、站点地图、邮件中的链接
+const (
+	SITE_ARTICLE_PATH_TEMPLATE      = "/article/%d"              // article_id
+	SITE_ARTICLE_SLUG_PATH_TEMPLATE = "/post/%s"                 // article slug，与按ID访问的路径区分，避免纯数字别名冲突
+	SITE_CATEGORY_PATH_TEMPLATE     = "/category/%s"             // category name
+	SITE_TAG_PATH_TEMPLATE          = "/tag/%s"                  // tag name
+	SITE_PASSWORD_RESET_PATH        = "/password/reset?token=%s" // 重置token
+)
+
 func GetArticleIDFromPageViewKey(key string) (int64, error) {
 	parts := strings.Split(key, ":")
 	if len(parts) != 2 {
@@ -37,6 +127,35 @@ func GetArticleIDFromPageViewKey(key string) (int64, error) {
 	return articleID, nil
 }
 
+// GetSiteArticlePath 文章的前台页面路径，优先使用别名
+func GetSiteArticlePath(articleID int64, articleSlug string) string {
+	if articleSlug != "" {
+		return fmt.Sprintf(SITE_ARTICLE_SLUG_PATH_TEMPLATE, articleSlug)
+	}
+	return fmt.Sprintf(SITE_ARTICLE_PATH_TEMPLATE, articleID)
+}
+
 func GetArticlePageViewKey(articleID int64) string {
 	return fmt.Sprintf(ARTICLE_PAGE_VIEW_KEY_TEMPLATE, strconv.FormatInt(articleID, 10))
 }
+
+func ValidAuditAction(action string) bool {
+	switch action {
+	case AUDIT_ACTION_CREATE, AUDIT_ACTION_UPDATE, AUDIT_ACTION_DELETE,
+		AUDIT_ACTION_RESTORE, AUDIT_ACTION_PURGE,
+		AUDIT_ACTION_DISABLE, AUDIT_ACTION_ENABLE, AUDIT_ACTION_PASSWORD_RESET,
+		AUDIT_ACTION_GRANT_ROLE, AUDIT_ACTION_REVOKE_ROLE,
+		AUDIT_ACTION_APPROVE, AUDIT_ACTION_REJECT, AUDIT_ACTION_HIDE:
+		return true
+	}
+	return false
+}
+
+func ValidAuditEntityType(entityType string) bool {
+	switch entityType {
+	case AUDIT_ENTITY_ARTICLE, AUDIT_ENTITY_CATEGORY, AUDIT_ENTITY_TAG, AUDIT_ENTITY_USER, AUDIT_ENTITY_SERIES,
+		AUDIT_ENTITY_COMMENT:
+		return true
+	}
+	return false
+}
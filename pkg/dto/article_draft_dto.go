@@ -0,0 +1,17 @@
+package dto
+
+// 自动保存文章草稿参数，草稿允许内容不完整，仅校验长度
+type ArticleDraftDto struct {
+	ArticleID   int64    `json:"article_id" binding:"gte=0"`                 // 文章ID，新建文章为0
+	Title       string   `json:"title" binding:"max=255"`                    // 文章标题
+	Summary     string   `json:"summary" binding:"max=500"`                  // 摘要
+	Content     string   `json:"content"`                                    // 文章内容
+	Category    string   `json:"category" binding:"max=20"`                  // 文章分类名称
+	Tags        []string `json:"tags" binding:"omitempty,max=5,dive,max=20"` // 文章标签名称列表
+	BaseVersion int      `json:"base_version" binding:"gte=0"`               // 草稿所基于的文章版本号
+}
+
+// 查询、删除文章草稿参数
+type ArticleDraftQueryDto struct {
+	ArticleID int64 `json:"article_id" form:"article_id" binding:"gte=0"` // 文章ID，新建文章为0
+}
@@ -2,9 +2,11 @@ package dto
 
 import (
 	"errors"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/mcuadros/go-defaults"
+	"github.com/narcissus1949/narcissus-blog/internal/slug"
 	"github.com/narcissus1949/narcissus-blog/internal/utils"
 )
 
@@ -29,9 +31,15 @@ type ArticleDto struct {
 	IsOriginal          bool     `json:"is_original"`                                          // 原创/转载标识。0表示非原创，1表示原创。默认初始值为1，表示原创
 	OriginalArticleLink string   `json:"original_article_link"`                                // 转载文章的原始文章链接，可为空
 	Status              uint8    `json:"status" binding:"oneof=0 1"`                           // 状态，0表示offline，1表示online
+	PublishAt           *int64   `json:"publish_at" binding:"omitempty,gte=0"`                 // 定时发布时间，毫秒时间戳，晚于当前时间时文章保存为offline，到期后自动上线
+	Slug                string   `json:"slug" binding:"omitempty,max=100"`                     // URL别名，为空时新建文章由标题自动生成，更新文章保持不变
+	Version             *int     `json:"version" binding:"omitempty,gte=0"`                    // 编辑所基于的文章版本号，更新时必填，与当前版本不一致时拒绝保存
 }
 
 func (req *ArticleDto) VlidateAndDefault() error {
+	if req.ID != nil && req.Version == nil {
+		return errors.New("version is required when updating article")
+	}
 	if req.Category != "" {
 		if err := CommonValidateName(req.Category, CATEGORY_MIN_LEN, CATEGORY_MAX_LEN); err != nil {
 			return err
@@ -46,6 +54,10 @@ func (req *ArticleDto) VlidateAndDefault() error {
 	if utf8.RuneCountInString(req.Summary) > SUMMARY_MAX_LEN {
 		return errors.New("summary is too long")
 	}
+	req.Slug = strings.ToLower(strings.TrimSpace(req.Slug))
+	if req.Slug != "" && !slug.Valid(req.Slug) {
+		return errors.New("slug must consist of lowercase letters, digits and hyphens")
+	}
 	return nil
 }
 
@@ -61,6 +73,9 @@ type ArticleListDto struct {
 	StartTime  int64    `json:"start_time"`
 	EndTime    int64    `json:"end_time"`
 	Pageinate
+
+	OnlyPublished bool `json:"-"` // 只查询已到发布时间的文章，供前台查询使用
+	OwnerID       int  `json:"-"` // 所属用户ID，作者只能查看自己的文章
 }
 
 func (req *ArticleListDto) VlidateAndSetDefault() error {
@@ -98,6 +113,36 @@ func (req *ArticleDeleteDto) VlidateAndDefault() error {
 	return nil
 }
 
+// 查询回收站文章参数
+type ArticleTrashListDto struct {
+	Title string `json:"title"` // 标题，模糊匹配
+	Pageinate
+
+	OwnerID int `json:"-"` // 所属用户ID，作者只能查看自己的文章
+}
+
+func (req *ArticleTrashListDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(req)
+	return nil
+}
+
+// 恢复、永久删除回收站文章参数
+type ArticleTrashDto struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+func (req *ArticleTrashDto) VlidateAndDefault() error {
+	if len(req.IDs) <= 0 {
+		return errors.New("article id is empty")
+	}
+	for i := range req.IDs {
+		if req.IDs[i] <= 0 {
+			return errors.New("article id is invalid")
+		}
+	}
+	return nil
+}
+
 type ArticlePageViewDto struct {
 	ArticleID int64 `json:"article_id" binding:"required"`
 }
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/narcissus1949/narcissus-blog/internal/related"
+)
+
+// 查询相关文章参数
+type ArticleRelatedDto struct {
+	ID    int64 `json:"id" form:"id" binding:"required,gte=1"`
+	Limit int   `json:"limit" form:"limit"` // 返回的文章数，为0时使用默认值
+}
+
+func (req *ArticleRelatedDto) ValidateAndDefault() error {
+	if req.Limit == 0 {
+		req.Limit = related.Config.DefaultLimit
+	}
+	if req.Limit < 0 || req.Limit > related.Config.MaxLimit {
+		return fmt.Errorf("limit must be between 1 and %d", related.Config.MaxLimit)
+	}
+	return nil
+}
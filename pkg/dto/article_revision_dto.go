@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/mcuadros/go-defaults"
+)
+
+// 查询文章版本列表参数
+type ArticleRevisionListDto struct {
+	ArticleID int64 `json:"article_id" form:"article_id" binding:"required,gte=1"`
+	Pageinate
+}
+
+func (req *ArticleRevisionListDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(req)
+	return nil
+}
+
+// 查询、恢复文章指定版本参数
+type ArticleRevisionDto struct {
+	ArticleID int64 `json:"article_id" form:"article_id" binding:"required,gte=1"`
+	Revision  int   `json:"revision" form:"revision" binding:"required,gte=1"`
+}
+
+// 比较文章两个版本参数
+type ArticleRevisionDiffDto struct {
+	ArticleID int64 `json:"article_id" form:"article_id" binding:"required,gte=1"`
+	From      int   `json:"from" form:"from" binding:"required,gte=1"`
+	To        int   `json:"to" form:"to" binding:"required,gte=1"`
+}
+
+func (req *ArticleRevisionDiffDto) VlidateAndDefault() error {
+	if req.From == req.To {
+		return errors.New("from and to revision are the same")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mcuadros/go-defaults"
+)
+
+const ARTICLE_SEARCH_KEYWORD_MAX_LEN = 50
+
+// 全文搜索文章参数
+type ArticleSearchDto struct {
+	Keyword string `json:"keyword" form:"keyword" binding:"required,trim_no_empty"` // 搜索关键词
+	Pageinate
+}
+
+func (req *ArticleSearchDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(req)
+	req.Keyword = strings.TrimSpace(req.Keyword)
+	if utf8.RuneCountInString(req.Keyword) > ARTICLE_SEARCH_KEYWORD_MAX_LEN {
+		return errors.New("keyword is too long")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/mcuadros/go-defaults"
+)
+
+// 系列最多包含的文章数
+const ARTICLE_SERIES_MAX_ARTICLES = 100
+
+// 查询系列列表参数
+type ArticleSeriesListDto struct {
+	Name string `json:"name" form:"name"` // 系列名称，模糊匹配
+	Pageinate
+}
+
+func (r *ArticleSeriesListDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(r)
+	return nil
+}
+
+type ArticleSeriesQueryDto struct {
+	ID int64 `json:"id" form:"id" binding:"required,gte=1"`
+}
+
+// 创建系列参数
+type ArticleSeriesDto struct {
+	Name        string  `json:"name" binding:"required,max=50"`
+	Description string  `json:"description" binding:"max=500"`
+	ArticleIDs  []int64 `json:"article_ids"` // 系列中的文章，按数组顺序排列
+}
+
+func (r *ArticleSeriesDto) ValidateAndDefault() error {
+	if len(r.ArticleIDs) > ARTICLE_SERIES_MAX_ARTICLES {
+		return errors.New("too many articles in series")
+	}
+	exist := map[int64]bool{}
+	for _, id := range r.ArticleIDs {
+		if id <= 0 {
+			return errors.New("article id is invalid")
+		}
+		if exist[id] {
+			return errors.New("article id is duplicated")
+		}
+		exist[id] = true
+	}
+	return nil
+}
+
+// 更新系列参数，文章列表整体替换
+type ArticleSeriesUpdateDto struct {
+	ID int64 `json:"id" binding:"required,gte=1"`
+	ArticleSeriesDto
+}
+
+type ArticleSeriesDeleteDto struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+func (r *ArticleSeriesDeleteDto) ValidateAndDefault() error {
+	if len(r.IDs) <= 0 {
+		return errors.New("series id is empty")
+	}
+	for i := range r.IDs {
+		if r.IDs[i] <= 0 {
+			return errors.New("series id is invalid")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/mcuadros/go-defaults"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+// 管理员查询审计日志参数
+type AuditLogListDto struct {
+	ActorID    int      `json:"actor_id"`    // 操作人ID，为空表示全部
+	Action     []string `json:"action"`      // 操作类型，为空表示全部
+	EntityType string   `json:"entity_type"` // 实体类型，为空表示全部
+	EntityID   int64    `json:"entity_id"`   // 实体ID，需同时指定实体类型
+	RequestID  string   `json:"request_id"`
+	StartTime  int64    `json:"start_time"` // 操作时间起始，毫秒时间戳
+	EndTime    int64    `json:"end_time"`   // 操作时间截止，毫秒时间戳
+	Pageinate
+}
+
+func (req *AuditLogListDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(req)
+	for i := range req.Action {
+		if !utils.ValidAuditAction(req.Action[i]) {
+			return errors.New("audit action invalide")
+		}
+	}
+	if len(req.EntityType) > 0 && !utils.ValidAuditEntityType(req.EntityType) {
+		return errors.New("audit entity type invalide")
+	}
+	if req.EntityID > 0 && len(req.EntityType) == 0 {
+		return errors.New("entity type is required when entity id is set")
+	}
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime > req.EndTime {
+		return errors.New("start time is after end time")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mcuadros/go-defaults"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+const (
+	COMMENT_CONTENT_MAX_LEN  = 1000
+	COMMENT_NICKNAME_MIN_LEN = 2
+	COMMENT_NICKNAME_MAX_LEN = 30
+)
+
+// 发表评论参数
+type CommentCreateDto struct {
+	ArticleID int64  `json:"article_id" binding:"required,gte=1"`
+	ParentID  int64  `json:"parent_id" binding:"gte=0"`                 // 回复的评论ID，为0表示评论文章
+	Nickname  string `json:"nickname" binding:"required,no_lt_spacing"` // 评论者昵称
+	Email     string `json:"email" binding:"omitempty,email,lte=100"`   // 评论者邮箱，可为空
+	Website   string `json:"website" binding:"omitempty,url,lte=255"`   // 评论者网站，可为空
+	Content   string `json:"content" binding:"required,trim_no_empty"`  // 评论内容
+}
+
+func (req *CommentCreateDto) VlidateAndDefault() error {
+	if err := CommonValidateName(req.Nickname, COMMENT_NICKNAME_MIN_LEN, COMMENT_NICKNAME_MAX_LEN); err != nil {
+		return err
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if utf8.RuneCountInString(req.Content) > COMMENT_CONTENT_MAX_LEN {
+		return errors.New("content is too long")
+	}
+	return nil
+}
+
+// 查询文章评论列表参数
+type CommentListDto struct {
+	ArticleID int64 `json:"article_id" form:"article_id" binding:"required,gte=1"`
+	Pageinate
+}
+
+func (req *CommentListDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(req)
+	return nil
+}
+
+// 管理端查询评论列表参数
+type CommentAdminListDto struct {
+	ArticleID *int64 `json:"article_id"`
+	Status    []int  `json:"status"`
+	Keyword   string `json:"keyword"`
+	Pageinate
+}
+
+func (req *CommentAdminListDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(req)
+	for i := range req.Status {
+		if req.Status[i] != utils.COMMENT_STATUS_PENDING &&
+			req.Status[i] != utils.COMMENT_STATUS_APPROVED &&
+			req.Status[i] != utils.COMMENT_STATUS_HIDDEN {
+			return errors.New("comment status invalide")
+		}
+	}
+	if req.ArticleID != nil && *req.ArticleID <= 0 {
+		return errors.New("article id is invalid")
+	}
+	return nil
+}
+
+// 审核、隐藏、删除评论参数
+type CommentIDsDto struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+func (req *CommentIDsDto) VlidateAndDefault() error {
+	if len(req.IDs) <= 0 {
+		return errors.New("comment id is empty")
+	}
+	for i := range req.IDs {
+		if req.IDs[i] <= 0 {
+			return errors.New("comment id is invalid")
+		}
+	}
+	return nil
+}
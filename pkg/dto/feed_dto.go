@@ -0,0 +1,22 @@
+package dto
+
+// 订阅源参数，分类和标签同时为空时输出全站订阅源
+type FeedDto struct {
+	Category string `form:"category" binding:"omitempty,no_lt_spacing"`  // 只输出该分类下的文章
+	Tag      string `form:"tag" binding:"omitempty,no_lt_spacing"`       // 只输出带有该标签的文章
+	Mode     string `form:"mode" binding:"omitempty,oneof=summary full"` // 输出模式，为空时使用配置的默认模式
+}
+
+func (req *FeedDto) VlidateAndDefault() error {
+	if req.Category != "" {
+		if err := CommonValidateName(req.Category, CATEGORY_MIN_LEN, CATEGORY_MAX_LEN); err != nil {
+			return err
+		}
+	}
+	if req.Tag != "" {
+		if err := CommonValidateName(req.Tag, TAG_MIN_LEN, TAG_MAX_LEN); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/mcuadros/go-defaults"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+// 查询审核队列参数
+type ModerationListDto struct {
+	SubjectType string `json:"subject_type"` // 提交类型，为空表示全部
+	Status      []int  `json:"status"`       // 审核状态，默认查询待审核
+	MinScore    int    `json:"min_score"`    // 最低可疑分数
+	Pageinate
+}
+
+func (req *ModerationListDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(req)
+	for i := range req.Status {
+		if req.Status[i] != utils.MODERATION_STATUS_PENDING &&
+			req.Status[i] != utils.MODERATION_STATUS_APPROVED &&
+			req.Status[i] != utils.MODERATION_STATUS_REJECTED {
+			return errors.New("moderation status invalide")
+		}
+	}
+	if len(req.Status) == 0 {
+		req.Status = append(req.Status, utils.MODERATION_STATUS_PENDING)
+	}
+	return nil
+}
+
+// 批量通过、拒绝参数
+type ModerationReviewDto struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+func (req *ModerationReviewDto) VlidateAndDefault() error {
+	if len(req.IDs) <= 0 {
+		return errors.New("moderation id is empty")
+	}
+	for i := range req.IDs {
+		if req.IDs[i] <= 0 {
+			return errors.New("moderation id is invalid")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/mcuadros/go-defaults"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+)
+
+// 管理员查询用户列表参数
+type UserListDto struct {
+	Username string `json:"username"` // 用户名，模糊匹配
+	Nickname string `json:"nickname"` // 昵称，模糊匹配
+	Email    string `json:"email"`    // 邮箱，模糊匹配
+	Status   []int  `json:"status"`   // 用户状态，为空表示全部
+	Role     *int32 `json:"role"`     // 拥有的角色，为空表示全部
+	Pageinate
+}
+
+func (req *UserListDto) VlidateAndSetDefault() error {
+	defaults.SetDefaults(req)
+	for i := range req.Status {
+		if req.Status[i] != utils.USER_STATUS_PENDING &&
+			req.Status[i] != utils.USER_STATUS_NORMAL &&
+			req.Status[i] != utils.USER_STATUS_REJECTED &&
+			req.Status[i] != utils.USER_STATUS_DISABLED {
+			return errors.New("user status invalide")
+		}
+	}
+	if req.Role != nil && !rbac.ValidRole(*req.Role) {
+		return errors.New("role is invalid")
+	}
+	return nil
+}
+
+// 管理员修改用户资料参数
+type UserUpdateDto struct {
+	ID         int    `json:"id" binding:"required,gt=0"`
+	Nickname   string `json:"nickname" binding:"required,no_spacing"`
+	Email      string `json:"email" binding:"omitempty,email,max=100"`
+	AvatarPath string `json:"avatar_path" binding:"max=255"`
+}
+
+func (req *UserUpdateDto) VlidateAndDefault() error {
+	if !nicknameRegexp.MatchString(req.Nickname) {
+		return errors.New("nickname invalid")
+	}
+	return nil
+}
+
+// 批量禁用、启用、强制重置密码参数
+type UserIDsDto struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+func (req *UserIDsDto) VlidateAndDefault() error {
+	if len(req.IDs) <= 0 {
+		return errors.New("user id is empty")
+	}
+	for i := range req.IDs {
+		if req.IDs[i] <= 0 {
+			return errors.New("user id is invalid")
+		}
+	}
+	return nil
+}
@@ -8,8 +8,12 @@ import (
 	"github.com/narcissus1949/narcissus-blog/cmd/blog/app/config"
 	"github.com/narcissus1949/narcissus-blog/internal/encrypt"
 	"github.com/narcissus1949/narcissus-blog/internal/jwt"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
 )
 
+// 英文、字母、下划线、中文、5-10，与注册时一致
+var nicknameRegexp = regexp.MustCompile(`^[\w\p{Han}]{5,10}$`)
+
 type SignIn struct {
 	Username    string `json:"username" binding:"required,no_spacing,gte=5,lte=20"`
 	Nickname    string `json:"nickname" binding:"required,no_spacing,gte=5,lte=10"`
@@ -24,8 +28,6 @@ func (r *SignIn) Validate() error {
 	userNamePattern := `^[a-zA-Z0-9_]{5,20}$`
 	// 英文、字母、下划线、中文、5-10
 	nickNamePattern := `^[\w\p{Han}]{5,10}$`
-	// 字母、数字、特殊字符、8-64
-	passwdPattern := `^[a-zA-Z0-9_!@#$%^&.*]{8,64}$`
 
 	// 账号
 	if match, err := regexp.MatchString(userNamePattern, r.Username); err != nil {
@@ -42,10 +44,18 @@ func (r *SignIn) Validate() error {
 	}
 
 	// 密码
-	if len(strings.TrimSpace(r.Password)) <= 0 {
-		return errors.New("password invalid")
+	return validateEncryptedPassword(r.Password, "password")
+}
+
+// validateEncryptedPassword 解密rsa加密的密码并校验格式
+func validateEncryptedPassword(password string, field string) error {
+	// 字母、数字、特殊字符、8-64
+	passwdPattern := `^[a-zA-Z0-9_!@#$%^&.*]{8,64}$`
+
+	if len(strings.TrimSpace(password)) <= 0 {
+		return errors.New(field + " invalid")
 	}
-	passwdDecrypt, decryptErr := encrypt.RSADecryptWithBase64([]byte(r.Password), config.Config.App.PrivateKeyDir)
+	passwdDecrypt, decryptErr := encrypt.RSADecryptWithBase64([]byte(password), config.Config.App.PrivateKeyDir)
 	if decryptErr != nil {
 		return decryptErr
 	}
@@ -53,16 +63,16 @@ func (r *SignIn) Validate() error {
 	if match, err := regexp.MatchString(passwdPattern, string(passwdDecrypt)); err != nil {
 		return err
 	} else if !match {
-		return errors.New("password invalid")
+		return errors.New(field + " invalid")
 	}
-
 	return nil
 }
 
 type LoginDto struct {
 	Username         string `json:"username" binding:"required,no_spacing,gte=5,lte=20"`
 	Password         string `json:"password" binding:"required,gte=5"`
-	VerificationCode string `json:"verification_code"`
+	VerificationCode string `json:"verification_code"` // 图片验证码，登录失败次数过多后必填
+	CaptchaID        string `json:"captcha_id"`        // 获取验证码时返回的ID
 }
 
 type LogoutDto struct {
@@ -100,3 +110,109 @@ func (r *RefreshTokenDto) Validate() error {
 	}
 	return nil
 }
+
+// 修改个人资料参数
+type UserProfileDto struct {
+	Nickname    string `json:"nickname" binding:"required,no_spacing"`
+	Email       string `json:"email" binding:"omitempty,email,max=100"`
+	PhoneNumber string `json:"phone_number" binding:"max=20"`
+	AvatarPath  string `json:"avatar_path" binding:"max=255"`
+}
+
+func (r *UserProfileDto) Validate() error {
+	if !nicknameRegexp.MatchString(r.Nickname) {
+		return errors.New("nickname invalid")
+	}
+	return nil
+}
+
+// 修改密码参数，新旧密码均使用rsa公钥加密
+type ChangePasswordDto struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+func (r *ChangePasswordDto) Validate() error {
+	if len(strings.TrimSpace(r.OldPassword)) <= 0 {
+		return errors.New("old password invalid")
+	}
+	return validateEncryptedPassword(r.NewPassword, "new password")
+}
+
+// 申请重置密码参数
+type ForgotPasswordDto struct {
+	Account string `json:"account" binding:"required,no_spacing,max=100"` // 用户名或邮箱
+}
+
+// 确认重置密码参数
+type ResetPasswordDto struct {
+	Token       string `json:"token" binding:"required,no_spacing"` // 重置邮件中的token
+	NewPassword string `json:"new_password" binding:"required"`     // rsa公钥加密的新密码
+}
+
+func (r *ResetPasswordDto) Validate() error {
+	return validateEncryptedPassword(r.NewPassword, "new password")
+}
+
+// 两步验证动态验证码参数
+type TotpCodeDto struct {
+	Code string `json:"code" binding:"required,no_spacing,max=20"` // 6位动态验证码
+}
+
+// 关闭两步验证参数
+type TotpDisableDto struct {
+	Password string `json:"password" binding:"required"`               // rsa公钥加密的密码
+	Code     string `json:"code" binding:"required,no_spacing,max=20"` // 动态验证码或恢复码
+}
+
+// 两步验证登录参数
+type LoginTwoFactorDto struct {
+	PreAuthToken string `json:"pre_auth_token" binding:"required,no_spacing"` // 登录时返回的预认证token
+	Code         string `json:"code" binding:"required,no_spacing,max=20"`    // 动态验证码或恢复码
+}
+
+// 撤销会话参数
+type SessionRevokeDto struct {
+	SessionID string `json:"session_id" binding:"required,no_spacing,max=64"`
+}
+
+// 第三方登录授权参数
+type OidcAuthorizeDto struct {
+	Provider string `json:"provider" form:"provider" binding:"required,no_spacing,max=50"`
+}
+
+// 第三方登录回调参数，由前端回调页面提交
+type OidcCallbackDto struct {
+	Provider string `json:"provider" binding:"required,no_spacing,max=50"`
+	Code     string `json:"code" binding:"required,no_spacing"`
+	State    string `json:"state" binding:"required,no_spacing"`
+}
+
+// 解除第三方账号关联参数
+type OidcUnlinkDto struct {
+	Provider string `json:"provider" binding:"required,no_spacing,max=50"`
+}
+
+// 创建个人访问token参数
+type AccessTokenCreateDto struct {
+	Name       string   `json:"name" binding:"required,no_lt_spacing,max=50"`
+	Scopes     []string `json:"scopes" binding:"required"`
+	ExpireDays int      `json:"expire_days" binding:"gte=0,lte=365"` // 有效天数，0表示永不过期
+}
+
+func (r *AccessTokenCreateDto) Validate() error {
+	if len(r.Scopes) == 0 {
+		return errors.New("scopes is empty")
+	}
+	for _, scope := range r.Scopes {
+		if !rbac.ValidScope(scope) {
+			return errors.New("scope " + scope + " not supported")
+		}
+	}
+	return nil
+}
+
+// 撤销个人访问token参数
+type AccessTokenRevokeDto struct {
+	ID int `json:"id" binding:"required,gt=0"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
+)
+
+// 授予、撤销用户角色参数
+type UserRoleDto struct {
+	UserID int    `json:"user_id" binding:"required,gt=0"` // 用户ID
+	Role   *int32 `json:"role" binding:"required"`         // 角色，0表示普通用户，1表示系统管理员，2表示编辑，3表示作者
+}
+
+func (req *UserRoleDto) VlidateAndDefault() error {
+	if !rbac.ValidRole(*req.Role) {
+		return errors.New("role is invalid")
+	}
+	return nil
+}
+
+// 查询用户角色参数
+type UserRoleQueryDto struct {
+	UserID int `json:"user_id" form:"user_id" binding:"required,gt=0"` // 用户ID
+}
@@ -3,10 +3,13 @@ package route
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/narcissus1949/narcissus-blog/internal/middleware"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
 	"github.com/narcissus1949/narcissus-blog/pkg/server/handler"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
 )
 
 func Setup(gin *gin.Engine) {
+	middleware.SetPersonalTokenAuthenticator(service.PersonalTokenService.Authenticate)
 
 	g := gin.Group("")
 
@@ -18,6 +21,28 @@ func Setup(gin *gin.Engine) {
 	{
 		userRoute.POST("/signin", handler.UserHandler.SignIn)
 		userRoute.POST("/login", handler.UserHandler.Login)
+		userRoute.POST("/login/2fa", handler.UserHandler.LoginTwoFactor)
+		userRoute.POST("/password/forgot", handler.UserHandler.ForgotPassword)
+		userRoute.POST("/password/reset", handler.UserHandler.ResetPassword)
+		userRoute.GET("/oidc/providers", handler.OidcHandler.ListProviders)
+		userRoute.GET("/oidc/authorize", handler.OidcHandler.Authorize)
+		userRoute.POST("/oidc/callback", handler.OidcHandler.Callback)
+	}
+
+	// 站点地图
+	g.GET("/sitemap.xml", handler.SitemapHandler.Sitemap)
+	g.GET("/sitemap/:page", handler.SitemapHandler.SitemapPage)
+	g.GET("/robots.txt", handler.SitemapHandler.Robots)
+
+	// token验证公钥
+	g.GET("/.well-known/jwks.json", handler.CommonHandler.GetJWKS)
+
+	// 订阅源路由
+	feedRoute := g.Group("/feed")
+	{
+		feedRoute.GET("/rss.xml", handler.FeedHandler.RSS)
+		feedRoute.GET("/atom.xml", handler.FeedHandler.Atom)
+		feedRoute.GET("/feed.json", handler.FeedHandler.JSONFeed)
 	}
 
 	// 文章路由
@@ -27,6 +52,9 @@ func Setup(gin *gin.Engine) {
 
 		articleRoute.POST("/list", handler.ArticleHandler.ListArticle)
 		articleRoute.GET("/detail", handler.ArticleHandler.GetArticleeDetail)
+		articleRoute.GET("/by-slug/:slug", handler.ArticleHandler.GetArticleBySlug)
+		articleRoute.GET("/search", handler.ArticleHandler.SearchArticle)
+		articleRoute.GET("/related", handler.ArticleHandler.ListRelatedArticle)
 
 		// 文章分类路由
 		articleRoute.GET("/category/listAll", handler.CategoryHandler.ListAllCategory)
@@ -37,12 +65,21 @@ func Setup(gin *gin.Engine) {
 		articleRoute.GET("/tag/listAll", handler.TagHandler.ListAllTag)
 		articleRoute.GET("/tag/list", handler.TagHandler.ListTag)
 		articleRoute.GET("/tag/get", handler.TagHandler.GetTagDetail)
+
+		// 文章系列路由
+		articleRoute.GET("/series/list", handler.ArticleSeriesHandler.ListSeries)
+		articleRoute.GET("/series/get", handler.ArticleSeriesHandler.GetSeries)
+
+		// 文章评论路由
+		articleRoute.GET("/comment/list", handler.CommentHandler.ListComment)
+		articleRoute.POST("/comment/create", handler.CommentHandler.CreateComment)
 	}
 
 	// 通用路由
 	commonRoute := g.Group("/common")
 	commonRoute.GET("/ssl", handler.CommonHandler.GetRASPublicKey)
 	commonRoute.POST("/ssl/encrypt", handler.CommonHandler.PublicKeyEncrypt)
+	commonRoute.GET("/captcha", handler.CommonHandler.GetCaptcha)
 
 	// 需要权限路由
 	userAuthRoute := g.Group("/user", middleware.JWTAuth())
@@ -50,28 +87,100 @@ func Setup(gin *gin.Engine) {
 		userAuthRoute.POST("/logout", handler.UserHandler.Logout)
 		userAuthRoute.POST("/token/refresh", handler.UserHandler.RefreshToken)
 		userAuthRoute.POST("/checkAuth", handler.UserHandler.CheckAuth)
+		userAuthRoute.GET("/me", handler.UserHandler.GetProfile)
+		userAuthRoute.PUT("/me", handler.UserHandler.UpdateProfile)
+		userAuthRoute.POST("/password/change", handler.UserHandler.ChangePassword)
+		userAuthRoute.GET("/sessions", handler.SessionHandler.ListSessions)
+		userAuthRoute.POST("/sessions/revoke", handler.SessionHandler.RevokeSession)
+		userAuthRoute.POST("/sessions/revokeOthers", handler.SessionHandler.RevokeOtherSessions)
+		userAuthRoute.GET("/tokens", handler.AccessTokenHandler.ListTokens)
+		userAuthRoute.POST("/tokens/create", handler.AccessTokenHandler.CreateToken)
+		userAuthRoute.POST("/tokens/revoke", handler.AccessTokenHandler.RevokeToken)
+		userAuthRoute.GET("/oidc/identities", handler.OidcHandler.ListIdentities)
+		userAuthRoute.GET("/oidc/link/authorize", handler.OidcHandler.AuthorizeLink)
+		userAuthRoute.POST("/oidc/link/callback", handler.OidcHandler.LinkCallback)
+		userAuthRoute.POST("/oidc/unlink", handler.OidcHandler.Unlink)
+		userAuthRoute.GET("/totp/status", handler.TwoFactorHandler.GetStatus)
+		userAuthRoute.POST("/totp/enroll", handler.TwoFactorHandler.Enroll)
+		userAuthRoute.POST("/totp/activate", handler.TwoFactorHandler.Activate)
+		userAuthRoute.POST("/totp/disable", handler.TwoFactorHandler.Disable)
+		userAuthRoute.POST("/totp/recovery/regenerate", handler.TwoFactorHandler.RegenerateRecoveryCodes)
+	}
+
+	// 用户管理
+	userAdminRoute := g.Group("/user", middleware.JWTAuth(), middleware.RequireRole(rbac.ROLE_ADMIN))
+	{
+		userAdminRoute.GET("/role/list", handler.UserRoleHandler.ListUserRoles)
+		userAdminRoute.POST("/role/grant", handler.UserRoleHandler.GrantRole)
+		userAdminRoute.POST("/role/revoke", handler.UserRoleHandler.RevokeRole)
+		userAdminRoute.POST("/admin/list", handler.UserAdminHandler.ListUser)
+		userAdminRoute.POST("/admin/update", handler.UserAdminHandler.UpdateUser)
+		userAdminRoute.POST("/admin/disable", handler.UserAdminHandler.DisableUserList)
+		userAdminRoute.POST("/admin/enable", handler.UserAdminHandler.EnableUserList)
+		userAdminRoute.POST("/admin/password/reset", handler.UserAdminHandler.ForcePasswordReset)
 	}
 
-	// 文章
-	articleAuthRoute := g.Group("/article", middleware.JWTAuth())
+	// 文章，允许使用article:write权限的个人访问token
+	articleAuthRoute := g.Group("/article", middleware.JWTAuth(rbac.SCOPE_ARTICLE_WRITE), middleware.RequireRole(rbac.ROLE_ADMIN, rbac.ROLE_EDITOR, rbac.ROLE_AUTHOR))
 	{
 		// 文章
 		articleAuthRoute.POST("/admin/list", handler.ArticleHandler.ListArticleAdmin)
+		articleAuthRoute.GET("/admin/detail", handler.ArticleHandler.GetArticleDetailAdmin)
 		articleAuthRoute.POST("/save", handler.ArticleHandler.SaveArticle)
 		articleAuthRoute.POST("/delete", handler.ArticleHandler.DeleteArticleList)
+		// 回收站
+		articleAuthRoute.POST("/trash/list", handler.ArticleTrashHandler.ListTrash)
+		articleAuthRoute.POST("/trash/restore", handler.ArticleTrashHandler.RestoreArticleList)
+		articleAuthRoute.POST("/trash/purge", handler.ArticleTrashHandler.PurgeArticleList)
+		// 文章版本
+		articleAuthRoute.GET("/revision/list", handler.ArticleRevisionHandler.ListRevision)
+		articleAuthRoute.GET("/revision/get", handler.ArticleRevisionHandler.GetRevision)
+		articleAuthRoute.GET("/revision/diff", handler.ArticleRevisionHandler.DiffRevision)
+		articleAuthRoute.POST("/revision/restore", handler.ArticleRevisionHandler.RestoreRevision)
+		// 文章草稿
+		articleAuthRoute.POST("/draft/save", handler.ArticleDraftHandler.SaveDraft)
+		articleAuthRoute.GET("/draft/get", handler.ArticleDraftHandler.GetDraft)
+		articleAuthRoute.POST("/draft/delete", handler.ArticleDraftHandler.DeleteDraft)
+	}
 
+	// 分类、标签、系列、评论管理，作者无权限
+	articleEditorRoute := g.Group("/article", middleware.JWTAuth(), middleware.RequireRole(rbac.ROLE_ADMIN, rbac.ROLE_EDITOR))
+	{
 		// 分类
-		articleAuthRoute.POST("/category/create", handler.CategoryHandler.CreateCategoryList)
-		articleAuthRoute.POST("/category/update", handler.CategoryHandler.UpdateCategory)
-		articleAuthRoute.POST("/category/delete", handler.CategoryHandler.DeleteCategoryList)
+		articleEditorRoute.POST("/category/create", handler.CategoryHandler.CreateCategoryList)
+		articleEditorRoute.POST("/category/update", handler.CategoryHandler.UpdateCategory)
+		articleEditorRoute.POST("/category/delete", handler.CategoryHandler.DeleteCategoryList)
 		// 标签
-		articleAuthRoute.POST("/tag/create", handler.TagHandler.CreateTagList)
-		articleAuthRoute.POST("/tag/update", handler.TagHandler.UpdateTag)
-		articleAuthRoute.POST("/tag/delete", handler.TagHandler.DeleteTagList)
+		articleEditorRoute.POST("/tag/create", handler.TagHandler.CreateTagList)
+		articleEditorRoute.POST("/tag/update", handler.TagHandler.UpdateTag)
+		articleEditorRoute.POST("/tag/delete", handler.TagHandler.DeleteTagList)
+		// 系列
+		articleEditorRoute.POST("/series/create", handler.ArticleSeriesHandler.CreateSeries)
+		articleEditorRoute.POST("/series/update", handler.ArticleSeriesHandler.UpdateSeries)
+		articleEditorRoute.POST("/series/delete", handler.ArticleSeriesHandler.DeleteSeriesList)
+		// 评论
+		articleEditorRoute.POST("/comment/admin/list", handler.CommentHandler.ListCommentAdmin)
+		articleEditorRoute.POST("/comment/approve", handler.CommentHandler.ApproveCommentList)
+		articleEditorRoute.POST("/comment/hide", handler.CommentHandler.HideCommentList)
+		articleEditorRoute.POST("/comment/delete", handler.CommentHandler.DeleteCommentList)
+	}
+
+	// 审核队列
+	moderationAuthRoute := g.Group("/moderation", middleware.JWTAuth(), middleware.RequireRole(rbac.ROLE_ADMIN))
+	{
+		moderationAuthRoute.POST("/list", handler.ModerationHandler.ListModeration)
+		moderationAuthRoute.POST("/approve", handler.ModerationHandler.ApproveModerationList)
+		moderationAuthRoute.POST("/reject", handler.ModerationHandler.RejectModerationList)
+	}
+
+	// 审计日志
+	auditAuthRoute := g.Group("/audit", middleware.JWTAuth(), middleware.RequireRole(rbac.ROLE_ADMIN))
+	{
+		auditAuthRoute.POST("/list", handler.AuditLogHandler.ListAuditLog)
 	}
 
-	// 通用
-	commonAuthRoute := g.Group("/common", middleware.JWTAuth())
+	// 通用，允许使用image:upload权限的个人访问token
+	commonAuthRoute := g.Group("/common", middleware.JWTAuth(rbac.SCOPE_IMAGE_UPLOAD))
 	commonAuthRoute.POST("/upload/image", handler.CommonHandler.UploadImage)
 
 }
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"errors"
 
 	"github.com/gin-gonic/gin"
@@ -34,10 +35,19 @@ func (d *articleContentDao) UpdateContentByArticleID(c *gin.Context, content *mo
 	return res.RowsAffected, res.Error
 }
 
-func (d *articleContentDao) DeleteContentByArticleIDs(c *gin.Context, articleIDs []int64) error {
+func (d *articleContentDao) DeleteContentByArticleIDs(c context.Context, articleIDs []int64) error {
 	if len(articleIDs) <= 0 {
 		return errors.New("article id is invalid")
 	}
-	db := mysql.GetDBFromContext(c)
+	db := mysql.GetDBFromContext2(c)
 	return db.Table(model.TableNameArticleContent).Where("article_id in ?", articleIDs).Delete(&model.ArticleContent{}).Error
 }
+
+func (d *articleContentDao) ListContentByArticleIDs(c *gin.Context, articleIDs []int64) ([]model.ArticleContent, error) {
+	if len(articleIDs) <= 0 {
+		return nil, errors.New("article id is invalid")
+	}
+	var contentList []model.ArticleContent
+	res := mysql.GetDBFromContext(c).Where("article_id in ?", articleIDs).Find(&contentList)
+	return contentList, res.Error
+}
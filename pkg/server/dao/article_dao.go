@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
@@ -12,6 +13,7 @@ import (
 	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	"github.com/narcissus1949/narcissus-blog/pkg/dto"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var ArticleDao = &articlerDao{}
@@ -25,14 +27,18 @@ func (d *articlerDao) InsertArticle(c *gin.Context, article *model.Article) erro
 	return res.Error
 }
 
-func (d *articlerDao) UpdateArticle(c *gin.Context, article *model.Article) (int64, error) {
+// UpdateArticle 更新文章元数据，仅当数据库中的版本号等于version时更新成功，并将版本号加1
+func (d *articlerDao) UpdateArticle(c *gin.Context, article *model.Article, version int) (int64, error) {
 	if article.ID == 0 {
 		return 0, errors.New("article id is required")
 	}
+	article.Version = version + 1
 	tx := mysql.GetDBFromContext(c)
 	res := tx.Model(article).
+		Where("version = ?", version).
 		Select(
 			"title",
+			"slug",
 			"summary",
 			"category_id",
 			"author",
@@ -42,6 +48,8 @@ func (d *articlerDao) UpdateArticle(c *gin.Context, article *model.Article) (int
 			"is_original",
 			"original_article_link",
 			"status",
+			"publish_at",
+			"version",
 			"updated_time").
 		Updates(article)
 	return res.RowsAffected, res.Error
@@ -55,62 +63,10 @@ where (a.type in (0,1) and a.is_original = 1) and ((a.type = 0 and c.name = "gol
 group by a.id
 */
 func (d *articlerDao) ListArticle(ctx *gin.Context, articleListRequest dto.ArticleListDto) ([]model.ArticleDetail, error) {
-	db := mysql.GetDBFromContext(ctx)
 	var articleList []model.ArticleDetail
-	db = db.Table(model.TableNameArticle + " a").
+	db := d.listArticleCond(ctx, articleListRequest).
 		Select("a.*, c.name as category_name, GROUP_CONCAT(t.name) as tag_name_list").
-		Joins(fmt.Sprintf("left join %s c on a.category_id = c.id", model.TableNameArticleCategory)).
-		Joins(fmt.Sprintf("left join %s r on r.article_id = a.id", model.TableNameArticleTagRelation)).
-		Joins(fmt.Sprintf("left join %s t on t.id = r.tag_id", model.TableNameArticleTag))
-
-	// 条件查询
-	// 不同文章类型(type)公用字段
-	{
-		baseCond := db.Session(&gorm.Session{NewDB: true})
-		baseCond = baseCond.Where("a.type in ?", articleListRequest.Type)
-		if len(strings.TrimSpace(articleListRequest.Title)) > 0 {
-			baseCond = baseCond.Where("a.title like ?", "%"+articleListRequest.Title+"%")
-		}
-		if len(strings.TrimSpace(articleListRequest.Author)) > 0 {
-			baseCond = baseCond.Where("a.author like ?", "%"+articleListRequest.Author+"%")
-		}
-		if articleListRequest.IsOriginal != nil {
-			baseCond = baseCond.Where("a.is_original = ?", *articleListRequest.IsOriginal)
-		}
-		if articleListRequest.Status != nil {
-			baseCond = baseCond.Where("a.status = ?", *articleListRequest.Status)
-		}
-		if articleListRequest.StartTime > 0 {
-			baseCond = baseCond.Where("a.created_time >= ?", articleListRequest.StartTime)
-		}
-		if articleListRequest.EndTime > 0 {
-			baseCond = baseCond.Where("a.updated_time <= ?", articleListRequest.EndTime)
-		}
-		db = db.Where(baseCond)
-	}
-	// 博文支持分类、标签;随笔不支持分类、标签
-	{
-		baseCond := db.Session(&gorm.Session{NewDB: true})
-		if utils.ArrayExistInt(articleListRequest.Type, utils.ARTICLE_TYPE_POST) {
-			postCond := baseCond.Session(&gorm.Session{NewDB: true})
-			postCond = postCond.Where("a.type = ?", utils.ARTICLE_TYPE_POST)
-			if len(strings.TrimSpace(articleListRequest.Category)) > 0 {
-				postCond = postCond.Where("c.name = ?", articleListRequest.Category)
-			}
-			if len(articleListRequest.Tags) > 0 {
-				postCond = postCond.Where("t.name in ?", articleListRequest.Tags)
-			}
-			baseCond = baseCond.Or(postCond)
-		}
-		if utils.ArrayExistInt(articleListRequest.Type, utils.ARTICLE_TYPE_ESSAY) {
-			essayCond := baseCond.Session(&gorm.Session{NewDB: true})
-			essayCond = essayCond.Where("a.type = ?", utils.ARTICLE_TYPE_ESSAY)
-			baseCond = baseCond.Or(essayCond)
-		}
-		db = db.Where(baseCond)
-	}
-
-	db = db.Group("a.id")
+		Group("a.id")
 	// 排序
 	db = db.Order("a.created_time desc")
 	// 分页查询
@@ -119,12 +75,33 @@ func (d *articlerDao) ListArticle(ctx *gin.Context, articleListRequest dto.Artic
 	return articleList, res.Error
 }
 
+// ListArticleVersion 按ListArticle的条件和分页只查询文章ID和更新时间，用于判断列表是否变化
+func (d *articlerDao) ListArticleVersion(ctx *gin.Context, articleListRequest dto.ArticleListDto) ([]model.ArticleVersion, error) {
+	var versionList []model.ArticleVersion
+	res := d.listArticleCond(ctx, articleListRequest).
+		Select("a.id, a.updated_time").
+		Group("a.id").
+		Order("a.created_time desc").
+		Scopes(dto.Paginate(articleListRequest.Pageinate)).
+		Scan(&versionList)
+	return versionList, res.Error
+}
+
 // todo 目前只支持分类计数、标签计数和全量计数
 func (d *articlerDao) CountArticle(ctx *gin.Context, articleListRequest dto.ArticleListDto) (int64, error) {
-	db := mysql.GetDBFromContext(ctx)
 	var totalArticle int64
-	db = db.Table(model.TableNameArticle + " a").
+	res := d.listArticleCond(ctx, articleListRequest).
 		Select("a.*, c.name as category_name, GROUP_CONCAT(t.name) as tag_name_list").
+		Group("a.id").
+		Count(&totalArticle)
+
+	return totalArticle, res.Error
+}
+
+// listArticleCond 文章列表的关联和查询条件
+func (d *articlerDao) listArticleCond(ctx *gin.Context, articleListRequest dto.ArticleListDto) *gorm.DB {
+	db := mysql.GetDBFromContext(ctx).
+		Table(model.TableNameArticle + " a").
 		Joins(fmt.Sprintf("left join %s c on a.category_id = c.id", model.TableNameArticleCategory)).
 		Joins(fmt.Sprintf("left join %s r on r.article_id = a.id", model.TableNameArticleTagRelation)).
 		Joins(fmt.Sprintf("left join %s t on t.id = r.tag_id", model.TableNameArticleTag))
@@ -134,6 +111,8 @@ func (d *articlerDao) CountArticle(ctx *gin.Context, articleListRequest dto.Arti
 	{
 		baseCond := db.Session(&gorm.Session{NewDB: true})
 		baseCond = baseCond.Where("a.type in ?", articleListRequest.Type)
+		// 回收站中的文章不参与查询
+		baseCond = baseCond.Where("a.deleted_time is null")
 		if len(strings.TrimSpace(articleListRequest.Title)) > 0 {
 			baseCond = baseCond.Where("a.title like ?", "%"+articleListRequest.Title+"%")
 		}
@@ -146,6 +125,12 @@ func (d *articlerDao) CountArticle(ctx *gin.Context, articleListRequest dto.Arti
 		if articleListRequest.Status != nil {
 			baseCond = baseCond.Where("a.status = ?", *articleListRequest.Status)
 		}
+		if articleListRequest.OnlyPublished {
+			baseCond = baseCond.Where("(a.publish_at is null or a.publish_at <= ?)", time.Now())
+		}
+		if articleListRequest.OwnerID > 0 {
+			baseCond = baseCond.Where("a.owner_id = ?", articleListRequest.OwnerID)
+		}
 		if articleListRequest.StartTime > 0 {
 			baseCond = baseCond.Where("a.created_time >= ?", articleListRequest.StartTime)
 		}
@@ -176,10 +161,42 @@ func (d *articlerDao) CountArticle(ctx *gin.Context, articleListRequest dto.Arti
 		db = db.Where(baseCond)
 	}
 
-	db = db.Group("a.id")
-	res := db.Count(&totalArticle)
+	return db
+}
 
-	return totalArticle, res.Error
+// QueryArticleByID 查询文章基本信息，不存在时返回nil
+func (d *articlerDao) QueryArticleByID(c *gin.Context, id int64) (*model.Article, error) {
+	if id <= 0 {
+		return nil, errors.New("id is invalide")
+	}
+	var article model.Article
+	res := mysql.GetDBFromContext(c).Where("id = ? and deleted_time is null", id).Find(&article)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &article, nil
+}
+
+// ListArticleByIDs 批量查询文章基本信息，不包含回收站中的文章
+func (d *articlerDao) ListArticleByIDs(c *gin.Context, ids []int64) ([]model.Article, error) {
+	var articles []model.Article
+	err := mysql.GetDBFromContext(c).
+		Where("id in ? and deleted_time is null", ids).
+		Find(&articles).Error
+	return articles, err
+}
+
+// QueryArticleIDBySlug 根据别名查询文章ID，不存在时返回0
+func (d *articlerDao) QueryArticleIDBySlug(c *gin.Context, slug string) (int64, error) {
+	var article model.Article
+	res := mysql.GetDBFromContext(c).Select("id").Where("slug = ?", slug).Find(&article)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return article.ID, nil
 }
 
 /*
@@ -191,10 +208,22 @@ left join article_tags as t on r.tag_id = t.id
 where a.id = 1;
 */
 func (d *articlerDao) QueryArticleDetail(c *gin.Context, id int64) (*model.ArticleDetail, error) {
+	return d.queryArticleDetail(c, id, false)
+}
+
+// QueryPublishedArticleDetail 查询文章详情，未到定时发布时间的文章视为不存在，供前台查询使用
+func (d *articlerDao) QueryPublishedArticleDetail(c *gin.Context, id int64) (*model.ArticleDetail, error) {
+	return d.queryArticleDetail(c, id, true)
+}
+
+func (d *articlerDao) queryArticleDetail(c *gin.Context, id int64, onlyPublished bool) (*model.ArticleDetail, error) {
 	if id <= 0 {
 		return nil, errors.New("in invalide")
 	}
 	db := mysql.GetDBFromContext(c)
+	if onlyPublished {
+		db = db.Where("(a.publish_at is null or a.publish_at <= ?)", time.Now())
+	}
 	var detail model.ArticleDetail
 	// 查询文章基本信息和分类名称
 	res := db.Table(model.TableNameArticle+" as a").
@@ -203,21 +232,149 @@ func (d *articlerDao) QueryArticleDetail(c *gin.Context, id int64) (*model.Artic
 		Joins(fmt.Sprintf("left join %s as ctx on a.id = ctx.article_id", model.TableNameArticleContent)).
 		Joins(fmt.Sprintf("left join %s r on a.id = r.article_id", model.TableNameArticleTagRelation)).
 		Joins(fmt.Sprintf("left join %s t on r.tag_id = t.id", model.TableNameArticleTag)).
-		Where("a.id = ?", id).
+		Where("a.id = ? and a.deleted_time is null", id).
 		Group("a.id").
 		First(&detail)
 
 	return &detail, res.Error
 }
 
-func (d *articlerDao) DeleteArticleByIDs(c *gin.Context, ids []int64) error {
+// ListPublishedArticleDetail 查询已上线且已到发布时间的文章详情(含内容)，ids为空时查询全部
+func (d *articlerDao) ListPublishedArticleDetail(c context.Context, ids []int64) ([]model.ArticleDetail, error) {
+	db := mysql.GetDBFromContext2(c).Table(model.TableNameArticle+" as a").
+		Select("a.*, c.name as category_name, ctx.content as content, GROUP_CONCAT(t.name) as tag_name_list").
+		Joins(fmt.Sprintf("left join %s c on a.category_id = c.id", model.TableNameArticleCategory)).
+		Joins(fmt.Sprintf("left join %s as ctx on a.id = ctx.article_id", model.TableNameArticleContent)).
+		Joins(fmt.Sprintf("left join %s r on a.id = r.article_id", model.TableNameArticleTagRelation)).
+		Joins(fmt.Sprintf("left join %s t on r.tag_id = t.id", model.TableNameArticleTag)).
+		Where("a.status = ? and a.deleted_time is null", utils.ARTICLE_STATUS_ONLINE).
+		Where("(a.publish_at is null or a.publish_at <= ?)", time.Now())
+	if len(ids) > 0 {
+		db = db.Where("a.id in ?", ids)
+	}
+	var detailList []model.ArticleDetail
+	res := db.Group("a.id").Find(&detailList)
+	return detailList, res.Error
+}
+
+// ListPublishedArticleLastmod 查询全部已发布文章的ID和更新时间
+func (d *articlerDao) ListPublishedArticleLastmod(c *gin.Context) ([]model.Article, error) {
+	var articleList []model.Article
+	res := mysql.GetDBFromContext(c).Table(model.TableNameArticle).
+		Select("id, slug, updated_time").
+		Where("status = ? and deleted_time is null", utils.ARTICLE_STATUS_ONLINE).
+		Where("(publish_at is null or publish_at <= ?)", time.Now()).
+		Order("id").
+		Find(&articleList)
+	return articleList, res.Error
+}
+
+// ListPublishedCategoryLastmod 查询包含已发布文章的分类及其下文章的最后更新时间
+func (d *articlerDao) ListPublishedCategoryLastmod(c *gin.Context) ([]model.NameLastmod, error) {
+	var lastmodList []model.NameLastmod
+	res := mysql.GetDBFromContext(c).Table(model.TableNameArticle+" as a").
+		Select("c.name as name, max(a.updated_time) as updated_time").
+		Joins(fmt.Sprintf("join %s c on a.category_id = c.id", model.TableNameArticleCategory)).
+		Where("a.status = ? and a.deleted_time is null", utils.ARTICLE_STATUS_ONLINE).
+		Where("(a.publish_at is null or a.publish_at <= ?)", time.Now()).
+		Group("c.name").
+		Order("c.name").
+		Find(&lastmodList)
+	return lastmodList, res.Error
+}
+
+// ListPublishedTagLastmod 查询包含已发布文章的标签及其下文章的最后更新时间
+func (d *articlerDao) ListPublishedTagLastmod(c *gin.Context) ([]model.NameLastmod, error) {
+	var lastmodList []model.NameLastmod
+	res := mysql.GetDBFromContext(c).Table(model.TableNameArticle+" as a").
+		Select("t.name as name, max(a.updated_time) as updated_time").
+		Joins(fmt.Sprintf("join %s r on a.id = r.article_id", model.TableNameArticleTagRelation)).
+		Joins(fmt.Sprintf("join %s t on r.tag_id = t.id", model.TableNameArticleTag)).
+		Where("a.status = ? and a.deleted_time is null", utils.ARTICLE_STATUS_ONLINE).
+		Where("(a.publish_at is null or a.publish_at <= ?)", time.Now()).
+		Group("t.name").
+		Order("t.name").
+		Find(&lastmodList)
+	return lastmodList, res.Error
+}
+
+// SoftDeleteArticleByIDs 将文章移入回收站
+func (d *articlerDao) SoftDeleteArticleByIDs(c *gin.Context, ids []int64, deletedTime time.Time) error {
+	if len(ids) == 0 {
+		return errors.New("ids is empty")
+	}
+	db := mysql.GetDBFromContext(c)
+	return db.Table(model.TableNameArticle).
+		Where("id in ? and deleted_time is null", ids).
+		Update("deleted_time", deletedTime).Error
+}
+
+// RestoreArticleByIDs 从回收站恢复文章
+func (d *articlerDao) RestoreArticleByIDs(c *gin.Context, ids []int64) error {
 	if len(ids) == 0 {
 		return errors.New("ids is empty")
 	}
 	db := mysql.GetDBFromContext(c)
+	return db.Table(model.TableNameArticle).
+		Where("id in ? and deleted_time is not null", ids).
+		Update("deleted_time", nil).Error
+}
+
+// DeleteArticleByIDs 永久删除文章
+func (d *articlerDao) DeleteArticleByIDs(c context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return errors.New("ids is empty")
+	}
+	db := mysql.GetDBFromContext2(c)
 	return db.Table(model.TableNameArticle).Where("id in ?", ids).Delete(&model.Article{}).Error
 }
 
+// ListDeletedArticle 分页查询回收站中的文章，按移入时间倒序
+func (d *articlerDao) ListDeletedArticle(c *gin.Context, req dto.ArticleTrashListDto) ([]model.Article, error) {
+	var articleList []model.Article
+	res := d.deletedCond(c, req).
+		Order("deleted_time desc").
+		Scopes(dto.Paginate(req.Pageinate)).
+		Find(&articleList)
+	return articleList, res.Error
+}
+
+func (d *articlerDao) CountDeletedArticle(c *gin.Context, req dto.ArticleTrashListDto) (int64, error) {
+	var count int64
+	res := d.deletedCond(c, req).Count(&count)
+	return count, res.Error
+}
+
+func (d *articlerDao) deletedCond(c *gin.Context, req dto.ArticleTrashListDto) *gorm.DB {
+	db := mysql.GetDBFromContext(c).Model(&model.Article{}).Where("deleted_time is not null")
+	if len(strings.TrimSpace(req.Title)) > 0 {
+		db = db.Where("title like ?", "%"+req.Title+"%")
+	}
+	if req.OwnerID > 0 {
+		db = db.Where("owner_id = ?", req.OwnerID)
+	}
+	return db
+}
+
+// ListDeletedArticleByIDs 查询回收站中的文章，不在回收站中的ID忽略
+func (d *articlerDao) ListDeletedArticleByIDs(c *gin.Context, ids []int64) ([]model.Article, error) {
+	var articles []model.Article
+	err := mysql.GetDBFromContext(c).
+		Where("id in ? and deleted_time is not null", ids).
+		Find(&articles).Error
+	return articles, err
+}
+
+// ListExpiredDeletedArticle 查询并锁定移入回收站的时间早于before的文章，需在事务中执行
+func (d *articlerDao) ListExpiredDeletedArticle(c context.Context, before time.Time) ([]model.Article, error) {
+	var articles []model.Article
+	res := mysql.GetDBFromContext2(c).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_time is not null and deleted_time < ?", before).
+		Find(&articles)
+	return articles, res.Error
+}
+
 func (d *articlerDao) DeleteArticleByID(c *gin.Context, id int64) error {
 	if id <= 0 {
 		return errors.New("id is invalide")
@@ -235,3 +392,31 @@ func (d *articlerDao) IncreaseArticleViews(c context.Context, id int64, incremen
 	result := db.Table(model.TableNameArticle).Where("id = ?", id).Update("views", gorm.Expr("views + ?", increment))
 	return result.RowsAffected, result.Error
 }
+
+// ListScheduledArticleIDs 查询并锁定已到定时发布时间的文章ID，需在事务中执行
+func (d *articlerDao) ListScheduledArticleIDs(c context.Context, now time.Time) ([]int64, error) {
+	var ids []int64
+	res := mysql.GetDBFromContext2(c).Table(model.TableNameArticle).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("publish_at is not null and publish_at <= ? and deleted_time is null", now).
+		Pluck("id", &ids)
+	return ids, res.Error
+}
+
+// PublishScheduledArticles 将指定的定时发布文章上线，并清空定时发布时间。
+// 版本号加1，使基于发布前版本的编辑无法覆盖发布状态
+func (d *articlerDao) PublishScheduledArticles(c context.Context, ids []int64, now time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("ids is empty")
+	}
+	db := mysql.GetDBFromContext2(c)
+	result := db.Table(model.TableNameArticle).
+		Where("id in ?", ids).
+		Updates(map[string]any{
+			"status":       utils.ARTICLE_STATUS_ONLINE,
+			"publish_at":   nil,
+			"updated_time": now,
+			"version":      gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+var ArticleDraftDao = &articleDraftDao{}
+
+type articleDraftDao struct {
+}
+
+// SaveDraft 保存草稿，同一用户同一文章只保留一份草稿
+func (d *articleDraftDao) SaveDraft(c *gin.Context, draft *model.ArticleDraft) error {
+	if draft.UserID <= 0 {
+		return errors.New("user id is invalid")
+	}
+	tx := mysql.GetDBFromContext(c)
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "article_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"title",
+			"summary",
+			"content",
+			"category",
+			"tags",
+			"base_version",
+			"updated_time",
+		}),
+	}).Create(draft).Error
+}
+
+// QueryDraft 查询用户的文章草稿，不存在时返回nil
+func (d *articleDraftDao) QueryDraft(c *gin.Context, articleID int64, userID int) (*model.ArticleDraft, error) {
+	var draft model.ArticleDraft
+	res := mysql.GetDBFromContext(c).
+		Where("article_id = ? and user_id = ?", articleID, userID).
+		Find(&draft)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &draft, nil
+}
+
+func (d *articleDraftDao) DeleteDraft(c *gin.Context, articleID int64, userID int) error {
+	return mysql.GetDBFromContext(c).
+		Where("article_id = ? and user_id = ?", articleID, userID).
+		Delete(&model.ArticleDraft{}).Error
+}
+
+func (d *articleDraftDao) DeleteDraftByArticleIDs(c context.Context, articleIDs []int64) error {
+	if len(articleIDs) == 0 {
+		return errors.New("article ids is empty")
+	}
+	return mysql.GetDBFromContext2(c).Where("article_id in ?", articleIDs).Delete(&model.ArticleDraft{}).Error
+}
@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+)
+
+var ArticleRevisionDao = &articleRevisionDao{}
+
+type articleRevisionDao struct {
+}
+
+func (d *articleRevisionDao) InsertRevision(c *gin.Context, revision *model.ArticleRevision) error {
+	if revision.ArticleID <= 0 {
+		return errors.New("article id is invalid")
+	}
+	tx := mysql.GetDBFromContext(c)
+	return tx.Create(revision).Error
+}
+
+// QueryMaxRevision 查询文章最新版本号，没有版本时返回0
+func (d *articleRevisionDao) QueryMaxRevision(c *gin.Context, articleID int64) (int, error) {
+	var maxRevision int
+	res := mysql.GetDBFromContext(c).
+		Model(&model.ArticleRevision{}).
+		Select("coalesce(max(revision), 0)").
+		Where("article_id = ?", articleID).
+		Scan(&maxRevision)
+	return maxRevision, res.Error
+}
+
+// QueryRevision 查询文章指定版本，不存在时返回nil
+func (d *articleRevisionDao) QueryRevision(c *gin.Context, articleID int64, revision int) (*model.ArticleRevision, error) {
+	var revisionModel model.ArticleRevision
+	res := mysql.GetDBFromContext(c).
+		Where("article_id = ? and revision = ?", articleID, revision).
+		Find(&revisionModel)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &revisionModel, nil
+}
+
+// ListRevision 分页查询文章版本，不包含文章内容
+func (d *articleRevisionDao) ListRevision(c *gin.Context, articleID int64, p dto.Pageinate) ([]model.ArticleRevision, error) {
+	var revisionList []model.ArticleRevision
+	res := mysql.GetDBFromContext(c).
+		Omit("content").
+		Where("article_id = ?", articleID).
+		Order("revision desc").
+		Scopes(dto.Paginate(p)).
+		Find(&revisionList)
+	return revisionList, res.Error
+}
+
+func (d *articleRevisionDao) CountRevision(c *gin.Context, articleID int64) (int64, error) {
+	var count int64
+	res := mysql.GetDBFromContext(c).
+		Model(&model.ArticleRevision{}).
+		Where("article_id = ?", articleID).
+		Count(&count)
+	return count, res.Error
+}
+
+func (d *articleRevisionDao) DeleteRevisionByArticleIDs(c context.Context, articleIDs []int64) error {
+	if len(articleIDs) == 0 {
+		return errors.New("article ids is empty")
+	}
+	return mysql.GetDBFromContext2(c).Where("article_id in ?", articleIDs).Delete(&model.ArticleRevision{}).Error
+}
@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+)
+
+var ArticleSeriesDao = &articleSeriesDao{}
+
+type articleSeriesDao struct {
+}
+
+// ListSeries 分页查询系列及其下已发布的文章数
+func (d *articleSeriesDao) ListSeries(c *gin.Context, req dto.ArticleSeriesListDto) ([]model.ArticleSeriesCount, error) {
+	var seriesList []model.ArticleSeriesCount
+	db := mysql.GetDBFromContext(c).Table(model.TableNameArticleSeries+" s").
+		Select("s.*, count(a.id) as article_count").
+		Joins(fmt.Sprintf("left join %s r on r.series_id = s.id", model.TableNameArticleSeriesRelation)).
+		Joins(fmt.Sprintf("left join %s a on a.id = r.article_id and a.status = ? and a.deleted_time is null and (a.publish_at is null or a.publish_at <= ?)", model.TableNameArticle),
+			utils.ARTICLE_STATUS_ONLINE, time.Now())
+	if len(strings.TrimSpace(req.Name)) > 0 {
+		db = db.Where("s.name like ?", "%"+req.Name+"%")
+	}
+	res := db.Group("s.id").
+		Order("s.id desc").
+		Scopes(dto.Paginate(req.Pageinate)).
+		Find(&seriesList)
+	return seriesList, res.Error
+}
+
+func (d *articleSeriesDao) CountSeries(c *gin.Context, req dto.ArticleSeriesListDto) (int64, error) {
+	var count int64
+	db := mysql.GetDBFromContext(c).Model(&model.ArticleSeries{})
+	if len(strings.TrimSpace(req.Name)) > 0 {
+		db = db.Where("name like ?", "%"+req.Name+"%")
+	}
+	res := db.Count(&count)
+	return count, res.Error
+}
+
+// QuerySeriesByID 不存在时返回nil
+func (d *articleSeriesDao) QuerySeriesByID(c *gin.Context, id int64) (*model.ArticleSeries, error) {
+	var series model.ArticleSeries
+	res := mysql.GetDBFromContext(c).Where("id = ?", id).Find(&series)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &series, nil
+}
+
+// QuerySeriesByArticleID 查询文章所属的系列，不属于任何系列时返回nil
+func (d *articleSeriesDao) QuerySeriesByArticleID(c *gin.Context, articleID int64) (*model.ArticleSeries, error) {
+	var series model.ArticleSeries
+	res := mysql.GetDBFromContext(c).Table(model.TableNameArticleSeries+" s").
+		Select("s.*").
+		Joins(fmt.Sprintf("join %s r on r.series_id = s.id", model.TableNameArticleSeriesRelation)).
+		Where("r.article_id = ?", articleID).
+		Find(&series)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &series, nil
+}
+
+func (d *articleSeriesDao) ListSeriesByIDs(c *gin.Context, ids []int64) ([]model.ArticleSeries, error) {
+	var seriesList []model.ArticleSeries
+	res := mysql.GetDBFromContext(c).Where("id in ?", ids).Find(&seriesList)
+	return seriesList, res.Error
+}
+
+func (d *articleSeriesDao) InsertSeries(c *gin.Context, series *model.ArticleSeries) error {
+	return mysql.GetDBFromContext(c).Create(series).Error
+}
+
+func (d *articleSeriesDao) UpdateSeries(c *gin.Context, series *model.ArticleSeries) error {
+	if series.ID <= 0 {
+		return errors.New("series id is invalid")
+	}
+	return mysql.GetDBFromContext(c).Model(series).
+		Select("name", "description", "updated_time").
+		Updates(series).Error
+}
+
+func (d *articleSeriesDao) DeleteSeriesByIDs(c *gin.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return errors.New("ids is empty")
+	}
+	return mysql.GetDBFromContext(c).Where("id in ?", ids).Delete(&model.ArticleSeries{}).Error
+}
+
+// ListSeriesArticles 按顺序查询系列中的文章，不包含回收站中的文章
+func (d *articleSeriesDao) ListSeriesArticles(c *gin.Context, seriesID int64, onlyPublished bool) ([]model.ArticleSeriesArticle, error) {
+	var articleList []model.ArticleSeriesArticle
+	db := mysql.GetDBFromContext(c).Table(model.TableNameArticleSeriesRelation+" r").
+		Select("r.series_id, r.article_id, r.sort_order, a.title, a.slug").
+		Joins(fmt.Sprintf("join %s a on a.id = r.article_id", model.TableNameArticle)).
+		Where("r.series_id = ? and a.deleted_time is null", seriesID)
+	if onlyPublished {
+		db = db.Where("a.status = ?", utils.ARTICLE_STATUS_ONLINE).
+			Where("(a.publish_at is null or a.publish_at <= ?)", time.Now())
+	}
+	res := db.Order("r.sort_order, r.id").Find(&articleList)
+	return articleList, res.Error
+}
+
+// ListRelationsByArticleIDs 查询文章所属系列的关系
+func (d *articleSeriesDao) ListRelationsByArticleIDs(c *gin.Context, articleIDs []int64) ([]model.ArticleSeriesRelation, error) {
+	var relations []model.ArticleSeriesRelation
+	res := mysql.GetDBFromContext(c).Where("article_id in ?", articleIDs).Find(&relations)
+	return relations, res.Error
+}
+
+// ListRelationsBySeriesID 按顺序查询系列的全部文章关系
+func (d *articleSeriesDao) ListRelationsBySeriesID(c *gin.Context, seriesID int64) ([]model.ArticleSeriesRelation, error) {
+	var relations []model.ArticleSeriesRelation
+	res := mysql.GetDBFromContext(c).Where("series_id = ?", seriesID).Order("sort_order, id").Find(&relations)
+	return relations, res.Error
+}
+
+func (d *articleSeriesDao) InsertRelations(c *gin.Context, relations []*model.ArticleSeriesRelation) error {
+	if len(relations) == 0 {
+		return nil
+	}
+	return mysql.GetDBFromContext(c).Create(relations).Error
+}
+
+func (d *articleSeriesDao) DeleteRelationsBySeriesIDs(c *gin.Context, seriesIDs []int64) error {
+	if len(seriesIDs) == 0 {
+		return errors.New("series ids is empty")
+	}
+	return mysql.GetDBFromContext(c).Where("series_id in ?", seriesIDs).Delete(&model.ArticleSeriesRelation{}).Error
+}
+
+func (d *articleSeriesDao) DeleteRelationsByArticleIDs(c context.Context, articleIDs []int64) error {
+	if len(articleIDs) == 0 {
+		return errors.New("article ids is empty")
+	}
+	return mysql.GetDBFromContext2(c).Where("article_id in ?", articleIDs).Delete(&model.ArticleSeriesRelation{}).Error
+}
@@ -0,0 +1,43 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+)
+
+var ArticleSlugHistoryDao = &articleSlugHistoryDao{}
+
+type articleSlugHistoryDao struct {
+}
+
+func (d *articleSlugHistoryDao) InsertHistory(c *gin.Context, history *model.ArticleSlugHistory) error {
+	if history.ArticleID <= 0 {
+		return errors.New("article id is invalid")
+	}
+	return mysql.GetDBFromContext(c).Create(history).Error
+}
+
+// QueryArticleIDBySlug 查询曾经使用该别名的文章ID，不存在时返回0
+func (d *articleSlugHistoryDao) QueryArticleIDBySlug(c *gin.Context, slug string) (int64, error) {
+	var history model.ArticleSlugHistory
+	res := mysql.GetDBFromContext(c).Where("slug = ?", slug).Find(&history)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return history.ArticleID, nil
+}
+
+func (d *articleSlugHistoryDao) DeleteHistoryBySlug(c *gin.Context, slug string) error {
+	return mysql.GetDBFromContext(c).Where("slug = ?", slug).Delete(&model.ArticleSlugHistory{}).Error
+}
+
+func (d *articleSlugHistoryDao) DeleteHistoryByArticleIDs(c context.Context, articleIDs []int64) error {
+	if len(articleIDs) == 0 {
+		return errors.New("article ids is empty")
+	}
+	return mysql.GetDBFromContext2(c).Where("article_id in ?", articleIDs).Delete(&model.ArticleSlugHistory{}).Error
+}
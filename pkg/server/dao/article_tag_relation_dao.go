@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -24,11 +25,11 @@ func (d *articleTagRelationDao) InsertArticleTagRelations(c *gin.Context, relati
 }
 
 // DeleteArticleTagRelationsBatch 批量删除文章标签关系
-func (d *articleTagRelationDao) DeleteArticleTagRelationsByArticleIDs(c *gin.Context, articleIDs []int64) error {
+func (d *articleTagRelationDao) DeleteArticleTagRelationsByArticleIDs(c context.Context, articleIDs []int64) error {
 	if len(articleIDs) == 0 {
 		return errors.New("article id is empty")
 	}
-	tx := mysql.GetDBFromContext(c)
+	tx := mysql.GetDBFromContext2(c)
 	return tx.Where("article_id in ?", articleIDs).Delete(&model.ArticleTagRelation{}).Error
 }
 
@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"gorm.io/gorm"
+)
+
+var AuditLogDao = &auditLogDao{}
+
+type auditLogDao struct {
+}
+
+func (d *auditLogDao) InsertAuditLog(ctx context.Context, auditLog *model.AuditLog) error {
+	return mysql.GetDBFromContext2(ctx).Create(auditLog).Error
+}
+
+func (d *auditLogDao) ListAuditLog(c *gin.Context, req dto.AuditLogListDto) ([]model.AuditLog, error) {
+	var auditLogList []model.AuditLog
+	res := d.listCond(c, req).
+		Order("id desc").
+		Scopes(dto.Paginate(req.Pageinate)).
+		Find(&auditLogList)
+	return auditLogList, res.Error
+}
+
+func (d *auditLogDao) CountAuditLog(c *gin.Context, req dto.AuditLogListDto) (int64, error) {
+	var count int64
+	res := d.listCond(c, req).Count(&count)
+	return count, res.Error
+}
+
+func (d *auditLogDao) listCond(c *gin.Context, req dto.AuditLogListDto) *gorm.DB {
+	db := mysql.GetDBFromContext(c).Model(&model.AuditLog{})
+	if req.ActorID > 0 {
+		db = db.Where("actor_id = ?", req.ActorID)
+	}
+	if len(req.Action) > 0 {
+		db = db.Where("action in ?", req.Action)
+	}
+	if len(req.EntityType) > 0 {
+		db = db.Where("entity_type = ?", req.EntityType)
+	}
+	if req.EntityID > 0 {
+		db = db.Where("entity_id = ?", req.EntityID)
+	}
+	if len(req.RequestID) > 0 {
+		db = db.Where("request_id = ?", req.RequestID)
+	}
+	if req.StartTime > 0 {
+		db = db.Where("created_time >= ?", time.UnixMilli(req.StartTime))
+	}
+	if req.EndTime > 0 {
+		db = db.Where("created_time <= ?", time.UnixMilli(req.EndTime))
+	}
+	return db
+}
@@ -44,6 +44,13 @@ func (d *categoryrDao) CountCategory(ctx *gin.Context, req dto.CategoryListDto)
 	return count, res.Error
 }
 
+// ListCategoryByNameList 根据名称批量查询分类，不存在的名称忽略
+func (d *categoryrDao) ListCategoryByNameList(ctx *gin.Context, categoryNameList []string) ([]model.ArticleCategory, error) {
+	var categoryList []model.ArticleCategory
+	res := mysql.GetDBFromContext(ctx).Table(model.TableNameArticleCategory).Where("name in ?", categoryNameList).Find(&categoryList)
+	return categoryList, res.Error
+}
+
 func (d *categoryrDao) QueryCategoryByName(ctx *gin.Context, categoryName string) (*model.ArticleCategory, error) {
 	var category model.ArticleCategory
 	res := mysql.GetDBFromContext(ctx).Table(model.TableNameArticleCategory).Where("name = ?", categoryName).First(&category)
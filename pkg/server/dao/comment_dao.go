@@ -0,0 +1,175 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"gorm.io/gorm"
+)
+
+var CommentDao = &commentDao{}
+
+type commentDao struct {
+}
+
+func (d *commentDao) InsertComment(c *gin.Context, comment *model.Comment) error {
+	if comment.ArticleID <= 0 {
+		return errors.New("article id is invalid")
+	}
+	tx := mysql.GetDBFromContext(c)
+	return tx.Create(comment).Error
+}
+
+func (d *commentDao) QueryCommentByID(c *gin.Context, id int64) (*model.Comment, error) {
+	var comment model.Comment
+	res := mysql.GetDBFromContext(c).Where("id = ?", id).Find(&comment)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &comment, nil
+}
+
+// ListRootComment 分页查询文章下指定状态的顶层评论
+func (d *commentDao) ListRootComment(c *gin.Context, articleID int64, status uint8, p dto.Pageinate) ([]model.Comment, error) {
+	var commentList []model.Comment
+	res := mysql.GetDBFromContext(c).
+		Where("article_id = ? and root_id = 0 and status = ?", articleID, status).
+		Order("created_time desc").
+		Scopes(dto.Paginate(p)).
+		Find(&commentList)
+	return commentList, res.Error
+}
+
+func (d *commentDao) CountRootComment(c *gin.Context, articleID int64, status uint8) (int64, error) {
+	var count int64
+	res := mysql.GetDBFromContext(c).
+		Model(&model.Comment{}).
+		Where("article_id = ? and root_id = 0 and status = ?", articleID, status).
+		Count(&count)
+	return count, res.Error
+}
+
+// ListReplyByRootIDs 查询顶层评论下指定状态的全部回复
+func (d *commentDao) ListReplyByRootIDs(c *gin.Context, rootIDs []int64, status uint8) ([]model.Comment, error) {
+	if len(rootIDs) == 0 {
+		return nil, errors.New("root ids is empty")
+	}
+	var commentList []model.Comment
+	res := mysql.GetDBFromContext(c).
+		Where("root_id in ? and status = ?", rootIDs, status).
+		Order("created_time asc").
+		Find(&commentList)
+	return commentList, res.Error
+}
+
+func (d *commentDao) ListCommentAdmin(c *gin.Context, req dto.CommentAdminListDto) ([]model.CommentDetail, error) {
+	var commentList []model.CommentDetail
+	res := d.adminListCond(c, req).
+		Select("cm.*, a.title as article_title").
+		Order("cm.created_time desc").
+		Scopes(dto.Paginate(req.Pageinate)).
+		Find(&commentList)
+	return commentList, res.Error
+}
+
+func (d *commentDao) CountCommentAdmin(c *gin.Context, req dto.CommentAdminListDto) (int64, error) {
+	var count int64
+	res := d.adminListCond(c, req).Count(&count)
+	return count, res.Error
+}
+
+func (d *commentDao) adminListCond(c *gin.Context, req dto.CommentAdminListDto) *gorm.DB {
+	db := mysql.GetDBFromContext(c).
+		Table(model.TableNameComment + " cm").
+		Joins(fmt.Sprintf("left join %s a on cm.article_id = a.id", model.TableNameArticle))
+	if req.ArticleID != nil {
+		db = db.Where("cm.article_id = ?", *req.ArticleID)
+	}
+	if len(req.Status) > 0 {
+		db = db.Where("cm.status in ?", req.Status)
+	}
+	if len(strings.TrimSpace(req.Keyword)) > 0 {
+		db = db.Where("(cm.content like ? or cm.nickname like ?)", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+	return db
+}
+
+// ListCommentByIDs 批量查询评论
+func (d *commentDao) ListCommentByIDs(c *gin.Context, ids []int64) ([]model.Comment, error) {
+	var commentList []model.Comment
+	res := mysql.GetDBFromContext(c).Where("id in ?", ids).Find(&commentList)
+	return commentList, res.Error
+}
+
+// ListCommentIDByParentIDs 查询直接回复指定评论的评论ID
+func (d *commentDao) ListCommentIDByParentIDs(c *gin.Context, parentIDs []int64) ([]int64, error) {
+	if len(parentIDs) == 0 {
+		return nil, errors.New("parent ids is empty")
+	}
+	var idList []int64
+	res := mysql.GetDBFromContext(c).
+		Model(&model.Comment{}).
+		Where("parent_id in ?", parentIDs).
+		Pluck("id", &idList)
+	return idList, res.Error
+}
+
+func (d *commentDao) UpdateCommentStatusByIDs(c *gin.Context, ids []int64, status uint8) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("ids is empty")
+	}
+	res := mysql.GetDBFromContext(c).
+		Model(&model.Comment{}).
+		Where("id in ?", ids).
+		Update("status", status)
+	return res.RowsAffected, res.Error
+}
+
+func (d *commentDao) DeleteCommentByIDs(c *gin.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return errors.New("ids is empty")
+	}
+	return mysql.GetDBFromContext(c).Where("id in ?", ids).Delete(&model.Comment{}).Error
+}
+
+func (d *commentDao) DeleteCommentByArticleIDs(c context.Context, articleIDs []int64) error {
+	if len(articleIDs) == 0 {
+		return errors.New("article ids is empty")
+	}
+	return mysql.GetDBFromContext2(c).Where("article_id in ?", articleIDs).Delete(&model.Comment{}).Error
+}
+
+// CountApprovedCommentByArticleIDs 统计文章已展示的评论数，key为文章ID
+// 回复只有在所属顶层评论也已通过审核时才会展示，因此关联顶层评论判断
+func (d *commentDao) CountApprovedCommentByArticleIDs(c *gin.Context, articleIDs []int64) (map[int64]int, error) {
+	result := make(map[int64]int, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return result, nil
+	}
+	var countList []model.ArticleCommentCount
+	res := mysql.GetDBFromContext(c).
+		Table(model.TableNameComment+" cm").
+		Joins(fmt.Sprintf("left join %s r on cm.root_id = r.id", model.TableNameComment)).
+		Select("cm.article_id, count(*) as count").
+		Where("cm.article_id in ? and cm.status = ?", articleIDs, utils.COMMENT_STATUS_APPROVED).
+		Where("(cm.root_id = 0 or r.status = ?)", utils.COMMENT_STATUS_APPROVED).
+		Group("cm.article_id").
+		Scan(&countList)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	for i := range countList {
+		result[countList[i].ArticleID] = countList[i].Count
+	}
+	return result, nil
+}
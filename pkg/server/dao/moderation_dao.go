@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ModerationDao = &moderationDao{}
+
+type moderationDao struct {
+}
+
+func (d *moderationDao) InsertModeration(c *gin.Context, moderation *model.Moderation) error {
+	if moderation.SubjectID <= 0 {
+		return errors.New("subject id is invalid")
+	}
+	tx := mysql.GetDBFromContext(c)
+	return tx.Create(moderation).Error
+}
+
+func (d *moderationDao) ListModeration(c *gin.Context, req dto.ModerationListDto) ([]model.Moderation, error) {
+	var moderationList []model.Moderation
+	res := d.listCond(c, req).
+		Order("score desc, created_time asc").
+		Scopes(dto.Paginate(req.Pageinate)).
+		Find(&moderationList)
+	return moderationList, res.Error
+}
+
+func (d *moderationDao) CountModeration(c *gin.Context, req dto.ModerationListDto) (int64, error) {
+	var count int64
+	res := d.listCond(c, req).Count(&count)
+	return count, res.Error
+}
+
+func (d *moderationDao) listCond(c *gin.Context, req dto.ModerationListDto) *gorm.DB {
+	db := mysql.GetDBFromContext(c).Model(&model.Moderation{})
+	if len(req.SubjectType) > 0 {
+		db = db.Where("subject_type = ?", req.SubjectType)
+	}
+	if len(req.Status) > 0 {
+		db = db.Where("status in ?", req.Status)
+	}
+	if req.MinScore > 0 {
+		db = db.Where("score >= ?", req.MinScore)
+	}
+	return db
+}
+
+// ListModerationByIDsAndStatus 查询指定状态的审核记录，加行锁防止重复审核
+func (d *moderationDao) ListModerationByIDsAndStatus(c *gin.Context, ids []int64, status uint8) ([]model.Moderation, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("ids is empty")
+	}
+	var moderationList []model.Moderation
+	res := mysql.GetDBFromContext(c).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id in ? and status = ?", ids, status).
+		Find(&moderationList)
+	return moderationList, res.Error
+}
+
+func (d *moderationDao) UpdateModerationStatusByIDs(c *gin.Context, ids []int64, status uint8, reviewerID int) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("ids is empty")
+	}
+	now := time.Now()
+	res := mysql.GetDBFromContext(c).
+		Model(&model.Moderation{}).
+		Where("id in ?", ids).
+		Updates(map[string]any{
+			"status":        status,
+			"reviewer_id":   reviewerID,
+			"reviewed_time": now,
+			"updated_time":  now,
+		})
+	return res.RowsAffected, res.Error
+}
@@ -58,6 +58,13 @@ func (d *tagDao) CountTag(c *gin.Context, tagListDto dto.TagListDto) (int64, err
 	return count, res.Error
 }
 
+// ListTagByNameList 根据名称批量查询标签，不存在的名称忽略
+func (d *tagDao) ListTagByNameList(ctx *gin.Context, nameList []string) ([]model.ArticleTag, error) {
+	var tagList []model.ArticleTag
+	res := mysql.GetDBFromContext(ctx).Table(model.TableNameArticleTag).Where("name in ?", nameList).Find(&tagList)
+	return tagList, res.Error
+}
+
 func (d *tagDao) ListTagIdByNameArr(ctx *gin.Context, nameList []string) ([]int64, error) {
 	if len(nameList) == 0 {
 		return nil, errors.New("nameList is empty")
@@ -89,11 +96,11 @@ func (d *tagDao) UpdateTagByID(ctx *gin.Context, tag model.ArticleTag) error {
 }
 
 // DeleteTag 根据标签
-func (d *tagDao) DeleteTagByNameList(nameList []string) error {
+func (d *tagDao) DeleteTagByNameList(ctx *gin.Context, nameList []string) error {
 	if len(nameList) == 0 {
 		return errors.New("nameList is empty")
 	}
-	res := mysql.Client.Table(model.TableNameArticleTag).
+	res := mysql.GetDBFromContext(ctx).Table(model.TableNameArticleTag).
 		Where("name in ?", nameList).
 		Delete(&model.ArticleTag{})
 	return res.Error
@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+)
+
+var UserAccessTokenDao = &userAccessTokenDao{}
+
+type userAccessTokenDao struct {
+}
+
+// QueryByTokenHash 根据token的sha256查询，不存在时返回nil
+func (d *userAccessTokenDao) QueryByTokenHash(c *gin.Context, tokenHash string) (*model.UserAccessToken, error) {
+	var token model.UserAccessToken
+	res := mysql.GetDBFromContext(c).Where("token_hash = ?", tokenHash).Find(&token)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func (d *userAccessTokenDao) ListByUserID(c *gin.Context, userID int) ([]model.UserAccessToken, error) {
+	var tokens []model.UserAccessToken
+	err := mysql.GetDBFromContext(c).Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+func (d *userAccessTokenDao) CountByUserID(c *gin.Context, userID int) (int64, error) {
+	var count int64
+	err := mysql.GetDBFromContext(c).Model(&model.UserAccessToken{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (d *userAccessTokenDao) InsertToken(c *gin.Context, token *model.UserAccessToken) error {
+	return mysql.GetDBFromContext(c).Create(token).Error
+}
+
+// DeleteToken 删除用户的token，返回删除行数
+func (d *userAccessTokenDao) DeleteToken(c *gin.Context, userID int, id int) (int64, error) {
+	res := mysql.GetDBFromContext(c).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&model.UserAccessToken{})
+	return res.RowsAffected, res.Error
+}
+
+// DeleteByUserIDs 删除用户的全部token
+func (d *userAccessTokenDao) DeleteByUserIDs(c *gin.Context, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return errors.New("user ids is empty")
+	}
+	return mysql.GetDBFromContext(c).
+		Where("user_id in ?", userIDs).
+		Delete(&model.UserAccessToken{}).Error
+}
+
+func (d *userAccessTokenDao) UpdateLastUsed(c *gin.Context, id int, lastUsedTime time.Time, ip string) error {
+	return mysql.GetDBFromContext(c).
+		Model(&model.UserAccessToken{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"last_used_time": lastUsedTime, "last_used_ip": ip}).Error
+}
@@ -1,8 +1,15 @@
 package dao
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
 	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
 	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"gorm.io/gorm"
 )
 
 var UserDaoInstance = &userDao{}
@@ -22,7 +29,144 @@ func (d *userDao) QueryByUsername(username string) (*model.User, error) {
 	return &user, res.Error
 }
 
-func (d *userDao) InsertUser(user *model.User) error {
-	res := mysql.Client.Create(user)
+// QueryByID 根据用户ID查询用户，不存在时返回nil
+func (d *userDao) QueryByID(ctx context.Context, id int) (*model.User, error) {
+	var user model.User
+	res := mysql.GetDBFromContext2(ctx).Where("id = ?", id).Find(&user)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (d *userDao) InsertUser(c *gin.Context, user *model.User) error {
+	res := mysql.GetDBFromContext(c).Create(user)
+	return res.Error
+}
+
+func (d *userDao) ListUserByIDs(c *gin.Context, ids []int64) ([]model.User, error) {
+	var userList []model.User
+	res := mysql.GetDBFromContext(c).Where("id in ?", ids).Find(&userList)
+	return userList, res.Error
+}
+
+func (d *userDao) UpdateUserStatusByIDs(c *gin.Context, ids []int64, status uint8) error {
+	if len(ids) == 0 {
+		return errors.New("ids is empty")
+	}
+	res := mysql.GetDBFromContext(c).
+		Model(&model.User{}).
+		Where("id in ?", ids).
+		Update("status", status)
+	return res.Error
+}
+
+// UpdateUserStatusByIDsAndStatus 只更新处于from状态的用户，返回更新的行数
+func (d *userDao) UpdateUserStatusByIDsAndStatus(ctx context.Context, ids []int64, from uint8, to uint8) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("ids is empty")
+	}
+	res := mysql.GetDBFromContext2(ctx).
+		Model(&model.User{}).
+		Where("id in ? and status = ?", ids, from).
+		Update("status", to)
+	return res.RowsAffected, res.Error
+}
+
+func (d *userDao) UpdatePasswordResetRequiredByIDs(c *gin.Context, ids []int64, required bool) error {
+	if len(ids) == 0 {
+		return errors.New("ids is empty")
+	}
+	res := mysql.GetDBFromContext(c).
+		Model(&model.User{}).
+		Where("id in ?", ids).
+		Update("password_reset_required", required)
+	return res.Error
+}
+
+// UpdateUserProfile 更新昵称、邮箱、手机号、头像
+func (d *userDao) UpdateUserProfile(c *gin.Context, user *model.User) error {
+	res := mysql.GetDBFromContext(c).
+		Model(user).
+		Select("nickname", "email", "phone_number", "avatar_path", "updated_at").
+		Updates(user)
 	return res.Error
 }
+
+// UpdateUserPassword 更新密码并清除强制重置密码标记
+func (d *userDao) UpdateUserPassword(c *gin.Context, id int, hashedPassword string) error {
+	res := mysql.GetDBFromContext(c).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"password":                hashedPassword,
+			"password_reset_required": false,
+			"updated_at":              time.Now(),
+		})
+	return res.Error
+}
+
+// ListByUsernameOrEmail 根据用户名或邮箱查询用户，邮箱不唯一时返回多个用户
+func (d *userDao) ListByUsernameOrEmail(c *gin.Context, account string) ([]model.User, error) {
+	var userList []model.User
+	res := mysql.GetDBFromContext(c).
+		Where("username = ? or email = ?", account, account).
+		Find(&userList)
+	return userList, res.Error
+}
+
+// QueryByNickname 根据昵称查询用户，不存在时返回nil
+func (d *userDao) QueryByNickname(c *gin.Context, nickname string) (*model.User, error) {
+	var user model.User
+	res := mysql.GetDBFromContext(c).Where("nickname = ?", nickname).Find(&user)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (d *userDao) ListUser(c *gin.Context, req dto.UserListDto) ([]model.User, error) {
+	var userList []model.User
+	res := d.listCond(c, req).
+		Order("id desc").
+		Scopes(dto.Paginate(req.Pageinate)).
+		Find(&userList)
+	return userList, res.Error
+}
+
+func (d *userDao) CountUser(c *gin.Context, req dto.UserListDto) (int64, error) {
+	var count int64
+	res := d.listCond(c, req).Count(&count)
+	return count, res.Error
+}
+
+func (d *userDao) listCond(c *gin.Context, req dto.UserListDto) *gorm.DB {
+	db := mysql.GetDBFromContext(c).Model(&model.User{})
+	if len(req.Username) > 0 {
+		db = db.Where("username like ?", "%"+req.Username+"%")
+	}
+	if len(req.Nickname) > 0 {
+		db = db.Where("nickname like ?", "%"+req.Nickname+"%")
+	}
+	if len(req.Email) > 0 {
+		db = db.Where("email like ?", "%"+req.Email+"%")
+	}
+	if len(req.Status) > 0 {
+		db = db.Where("status in ?", req.Status)
+	}
+	if req.Role != nil {
+		roleUsers := mysql.GetDBFromContext(c).
+			Table(model.TableNameUserRole+" ur").
+			Joins("join "+model.TableNameRole+" r on r.id = ur.role_id").
+			Where("r.role = ?", *req.Role).
+			Select("ur.user_id")
+		db = db.Where("id in (?)", roleUsers)
+	}
+	return db
+}
@@ -0,0 +1,52 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+)
+
+var UserIdentityDao = &userIdentityDao{}
+
+type userIdentityDao struct {
+}
+
+// QueryIdentity 按提供方和sub查询关联的外部身份，不存在时返回nil
+func (d *userIdentityDao) QueryIdentity(c *gin.Context, provider string, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	res := mysql.GetDBFromContext(c).Where("provider = ? AND subject = ?", provider, subject).Find(&identity)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &identity, nil
+}
+
+func (d *userIdentityDao) ListIdentityByUserID(c *gin.Context, userID int) ([]model.UserIdentity, error) {
+	var identities []model.UserIdentity
+	err := mysql.GetDBFromContext(c).Where("user_id = ?", userID).Order("id").Find(&identities).Error
+	return identities, err
+}
+
+func (d *userIdentityDao) InsertIdentity(c *gin.Context, identity *model.UserIdentity) error {
+	return mysql.GetDBFromContext(c).Create(identity).Error
+}
+
+func (d *userIdentityDao) UpdateLastLoginTime(c *gin.Context, id int, lastLoginTime time.Time) error {
+	return mysql.GetDBFromContext(c).
+		Model(&model.UserIdentity{}).
+		Where("id = ?", id).
+		Update("last_login_time", lastLoginTime).Error
+}
+
+// DeleteIdentity 删除用户关联的外部身份，返回删除行数
+func (d *userIdentityDao) DeleteIdentity(c *gin.Context, userID int, provider string) (int64, error) {
+	res := mysql.GetDBFromContext(c).
+		Where("user_id = ? AND provider = ?", userID, provider).
+		Delete(&model.UserIdentity{})
+	return res.RowsAffected, res.Error
+}
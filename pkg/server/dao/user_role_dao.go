@@ -0,0 +1,90 @@
+package dao
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+var UserRoleDao = &userRoleDao{}
+
+type userRoleDao struct {
+}
+
+// QueryRoleByRole 根据角色值查询角色，不存在时返回nil
+func (d *userRoleDao) QueryRoleByRole(ctx context.Context, role int32) (*model.Role, error) {
+	var r model.Role
+	res := mysql.GetDBFromContext2(ctx).Where("role = ?", role).Find(&r)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+// ListRolesByUserID 查询用户拥有的角色值
+func (d *userRoleDao) ListRolesByUserID(c *gin.Context, userID int) ([]int32, error) {
+	roles := []int32{}
+	err := mysql.GetDBFromContext(c).
+		Table(model.TableNameUserRole+" ur").
+		Joins("join "+model.TableNameRole+" r on r.id = ur.role_id").
+		Where("ur.user_id = ?", userID).
+		Order("r.role").
+		Pluck("r.role", &roles).Error
+	return roles, err
+}
+
+// ListRolesByUserIDs 批量查询用户拥有的角色值，key为用户ID
+func (d *userRoleDao) ListRolesByUserIDs(c *gin.Context, userIDs []int) (map[int][]int32, error) {
+	var rows []struct {
+		UserID int
+		Role   int32
+	}
+	roleMap := make(map[int][]int32, len(userIDs))
+	if len(userIDs) == 0 {
+		return roleMap, nil
+	}
+	err := mysql.GetDBFromContext(c).
+		Table(model.TableNameUserRole+" ur").
+		Joins("join "+model.TableNameRole+" r on r.id = ur.role_id").
+		Where("ur.user_id in ?", userIDs).
+		Order("r.role").
+		Select("ur.user_id as user_id, r.role as role").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		roleMap[row.UserID] = append(roleMap[row.UserID], row.Role)
+	}
+	return roleMap, nil
+}
+
+// CountUserByRole 统计拥有指定角色的用户数
+func (d *userRoleDao) CountUserByRole(ctx context.Context, role int32) (int64, error) {
+	var count int64
+	err := mysql.GetDBFromContext2(ctx).
+		Table(model.TableNameUserRole+" ur").
+		Joins("join "+model.TableNameRole+" r on r.id = ur.role_id").
+		Where("r.role = ?", role).
+		Count(&count).Error
+	return count, err
+}
+
+// InsertUserRole 授予用户角色，已拥有时忽略
+func (d *userRoleDao) InsertUserRole(ctx context.Context, userRole *model.UserRole) error {
+	return mysql.GetDBFromContext2(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(userRole).Error
+}
+
+// DeleteUserRole 撤销用户角色
+func (d *userRoleDao) DeleteUserRole(c *gin.Context, userID int, roleID int) error {
+	return mysql.GetDBFromContext(c).
+		Where("user_id = ? and role_id = ?", userID, roleID).
+		Delete(&model.UserRole{}).Error
+}
@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+var UserTotpDao = &userTotpDao{}
+
+type userTotpDao struct {
+}
+
+// QueryTotp 查询用户TOTP配置，不存在时返回nil
+func (d *userTotpDao) QueryTotp(c *gin.Context, userID int) (*model.UserTotp, error) {
+	var userTotp model.UserTotp
+	res := mysql.GetDBFromContext(c).Where("user_id = ?", userID).Find(&userTotp)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &userTotp, nil
+}
+
+// SaveTotp 保存待验证的密钥，重新绑定时覆盖原密钥
+func (d *userTotpDao) SaveTotp(c *gin.Context, userTotp *model.UserTotp) error {
+	return mysql.GetDBFromContext(c).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled", "updated_time"}),
+	}).Create(userTotp).Error
+}
+
+func (d *userTotpDao) EnableTotp(c *gin.Context, userID int) error {
+	return mysql.GetDBFromContext(c).
+		Model(&model.UserTotp{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{"enabled": true, "updated_time": time.Now()}).Error
+}
+
+func (d *userTotpDao) DeleteTotp(c *gin.Context, userID int) error {
+	return mysql.GetDBFromContext(c).
+		Where("user_id = ?", userID).
+		Delete(&model.UserTotp{}).Error
+}
+
+func (d *userTotpDao) InsertRecoveryCodes(c *gin.Context, codes []*model.UserRecoveryCode) error {
+	return mysql.GetDBFromContext(c).Create(codes).Error
+}
+
+func (d *userTotpDao) DeleteRecoveryCodes(c *gin.Context, userID int) error {
+	return mysql.GetDBFromContext(c).
+		Where("user_id = ?", userID).
+		Delete(&model.UserRecoveryCode{}).Error
+}
+
+// UseRecoveryCode 将未使用的恢复码标记为已使用，返回更新的行数，为0表示恢复码无效
+func (d *userTotpDao) UseRecoveryCode(c *gin.Context, userID int, codeHash string) (int64, error) {
+	res := mysql.GetDBFromContext(c).
+		Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? and code_hash = ? and used_time is null", userID, codeHash).
+		Update("used_time", time.Now())
+	return res.RowsAffected, res.Error
+}
+
+// CountUnusedRecoveryCodes 统计用户剩余可用的恢复码数量
+func (d *userTotpDao) CountUnusedRecoveryCodes(c *gin.Context, userID int) (int64, error) {
+	var count int64
+	res := mysql.GetDBFromContext(c).
+		Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? and used_time is null", userID).
+		Count(&count)
+	return count, res.Error
+}
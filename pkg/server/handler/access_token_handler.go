@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var AccessTokenHandler = new(accessTokenHandler)
+
+type accessTokenHandler struct {
+}
+
+func (c *accessTokenHandler) ListTokens(ctx *gin.Context) {
+	tokens, err := service.PersonalTokenService.ListTokens(ctx)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, tokens)
+}
+
+func (c *accessTokenHandler) CreateToken(ctx *gin.Context) {
+	var createDto dto.AccessTokenCreateDto
+	if err := ctx.ShouldBindJSON(&createDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind create access token JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := createDto.Validate(); err != nil {
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	token, err := service.PersonalTokenService.CreateToken(ctx, createDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, token)
+}
+
+func (c *accessTokenHandler) RevokeToken(ctx *gin.Context) {
+	var revokeDto dto.AccessTokenRevokeDto
+	if err := ctx.ShouldBindJSON(&revokeDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind revoke access token JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.PersonalTokenService.RevokeToken(ctx, revokeDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
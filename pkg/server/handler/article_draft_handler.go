@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var ArticleDraftHandler = new(articleDraftHandler)
+
+type articleDraftHandler struct {
+}
+
+func (c *articleDraftHandler) SaveDraft(ctx *gin.Context) {
+	var draftDto dto.ArticleDraftDto
+	if err := ctx.ShouldBindJSON(&draftDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind save article draft JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	draft, err := service.ArticleDraftService.SaveDraft(ctx, draftDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, draft)
+}
+
+func (c *articleDraftHandler) GetDraft(ctx *gin.Context) {
+	var queryDto dto.ArticleDraftQueryDto
+	if err := ctx.ShouldBindQuery(&queryDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind get article draft query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	draft, err := service.ArticleDraftService.GetDraft(ctx, queryDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, draft)
+}
+
+func (c *articleDraftHandler) DeleteDraft(ctx *gin.Context) {
+	var queryDto dto.ArticleDraftQueryDto
+	if err := ctx.ShouldBindJSON(&queryDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind delete article draft JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.ArticleDraftService.DeleteDraft(ctx, queryDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"io"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -124,10 +126,11 @@ func (c *articleHandler) ListArticle(ctx *gin.Context) {
 		return
 	}
 
-	// 只允许查已上线的文章
+	// 只允许查已上线且已到发布时间的文章
 	status := true
 	articleListRequest.Status = &status
-	articleList, err := service.ArticleService.ListArticleAdmin(ctx, articleListRequest)
+	articleListRequest.OnlyPublished = true
+	articleList, err := service.ArticleService.ListArticle(ctx, articleListRequest)
 	if err != nil {
 		resp.Fail(ctx, err)
 		return
@@ -136,6 +139,15 @@ func (c *articleHandler) ListArticle(ctx *gin.Context) {
 }
 
 func (c *articleHandler) GetArticleeDetail(ctx *gin.Context) {
+	c.getArticleDetail(ctx, true)
+}
+
+// GetArticleDetailAdmin 管理端查询文章详情，包含未到定时发布时间的文章
+func (c *articleHandler) GetArticleDetailAdmin(ctx *gin.Context) {
+	c.getArticleDetail(ctx, false)
+}
+
+func (c *articleHandler) getArticleDetail(ctx *gin.Context, onlyPublished bool) {
 	idStr := ctx.Query("id")
 	if len(strings.TrimSpace(idStr)) == 0 {
 		logger.FromContext(ctx.Request.Context()).Error("Failed to get article detail, id is 0")
@@ -149,11 +161,20 @@ func (c *articleHandler) GetArticleeDetail(ctx *gin.Context) {
 		return
 	}
 
-	articleDetail, getDetailErr := service.ArticleService.GetArticleDetail(ctx, int64(id))
+	getDetail := service.ArticleService.GetArticleDetail
+	if onlyPublished {
+		getDetail = service.ArticleService.GetPublishedArticleDetail
+	}
+	articleDetail, getDetailErr := getDetail(ctx, int64(id))
 	if getDetailErr != nil {
 		resp.Fail(ctx, getDetailErr)
 		return
 	}
+	// 前台详情同时返回渲染后的内容，管理端编辑只需要原始内容
+	if onlyPublished {
+		service.ArticleRenderService.RenderDetail(ctx, articleDetail)
+	}
+	service.ArticleSeriesService.FillSeriesNav(ctx, articleDetail, onlyPublished)
 	resp.OK(ctx, articleDetail)
 }
 
@@ -195,3 +216,74 @@ func (c *articleHandler) IncreasePageView(ctx *gin.Context) {
 
 	resp.OK(ctx, nil)
 }
+
+func (c *articleHandler) SearchArticle(ctx *gin.Context) {
+	var searchDto dto.ArticleSearchDto
+	// 先设置默认，防止binding校验失败
+	if err := searchDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate article search request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindQuery(&searchDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind article search query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := searchDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate article search request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	searchResult, err := service.ArticleSearchService.Search(ctx, searchDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, searchResult)
+}
+
+// GetArticleBySlug 根据别名查询已发布文章，历史别名301重定向到当前别名
+func (c *articleHandler) GetArticleBySlug(ctx *gin.Context) {
+	articleSlug := strings.ToLower(strings.TrimSpace(ctx.Param("slug")))
+	if articleSlug == "" {
+		resp.ParamFail(ctx, "slug invalide")
+		return
+	}
+
+	articleDetail, currentSlug, err := service.ArticleSlugService.GetArticleBySlug(ctx, articleSlug)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	if currentSlug != "" {
+		// 使用相对路径，经过反向代理添加路径前缀后依然有效
+		ctx.Redirect(http.StatusMovedPermanently, url.PathEscape(currentSlug))
+		return
+	}
+	service.ArticleRenderService.RenderDetail(ctx, articleDetail)
+	service.ArticleSeriesService.FillSeriesNav(ctx, articleDetail, true)
+	resp.OK(ctx, articleDetail)
+}
+
+func (c *articleHandler) ListRelatedArticle(ctx *gin.Context) {
+	var relatedDto dto.ArticleRelatedDto
+	if err := ctx.ShouldBindQuery(&relatedDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind related article query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := relatedDto.ValidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate related article request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	relatedResult, err := service.ArticleRelatedService.ListRelated(ctx, relatedDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, relatedResult)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var ArticleRevisionHandler = new(articleRevisionHandler)
+
+type articleRevisionHandler struct {
+}
+
+func (c *articleRevisionHandler) ListRevision(ctx *gin.Context) {
+	var listDto dto.ArticleRevisionListDto
+	// 先设置默认，防止binding校验失败
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate article revision list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindQuery(&listDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind article revision list query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	revisionList, err := service.ArticleRevisionService.ListRevision(ctx, listDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, revisionList)
+}
+
+func (c *articleRevisionHandler) GetRevision(ctx *gin.Context) {
+	var revisionDto dto.ArticleRevisionDto
+	if err := ctx.ShouldBindQuery(&revisionDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind get article revision query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	revision, err := service.ArticleRevisionService.GetRevision(ctx, revisionDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, revision)
+}
+
+func (c *articleRevisionHandler) DiffRevision(ctx *gin.Context) {
+	var diffDto dto.ArticleRevisionDiffDto
+	if err := ctx.ShouldBindQuery(&diffDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind article revision diff query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := diffDto.VlidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate article revision diff request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	diffResult, err := service.ArticleRevisionService.DiffRevision(ctx, diffDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, diffResult)
+}
+
+func (c *articleRevisionHandler) RestoreRevision(ctx *gin.Context) {
+	var revisionDto dto.ArticleRevisionDto
+	if err := ctx.ShouldBindJSON(&revisionDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind restore article revision JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.ArticleRevisionService.RestoreRevision(ctx, revisionDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var ArticleSeriesHandler = new(articleSeriesHandler)
+
+type articleSeriesHandler struct {
+}
+
+func (c *articleSeriesHandler) ListSeries(ctx *gin.Context) {
+	var listDto dto.ArticleSeriesListDto
+	// 先设置默认，防止binding校验失败
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate series list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindQuery(&listDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind series list query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	seriesList, err := service.ArticleSeriesService.ListSeries(ctx, listDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, seriesList)
+}
+
+func (c *articleSeriesHandler) GetSeries(ctx *gin.Context) {
+	var queryDto dto.ArticleSeriesQueryDto
+	if err := ctx.ShouldBindQuery(&queryDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind get series query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	series, err := service.ArticleSeriesService.GetSeries(ctx, queryDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, series)
+}
+
+func (c *articleSeriesHandler) CreateSeries(ctx *gin.Context) {
+	var seriesDto dto.ArticleSeriesDto
+	if err := ctx.ShouldBindJSON(&seriesDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind create series JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := seriesDto.ValidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate create series request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := service.ArticleSeriesService.CreateSeries(ctx, seriesDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *articleSeriesHandler) UpdateSeries(ctx *gin.Context) {
+	var updateDto dto.ArticleSeriesUpdateDto
+	if err := ctx.ShouldBindJSON(&updateDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind update series JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := updateDto.ValidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate update series request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := service.ArticleSeriesService.UpdateSeries(ctx, updateDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *articleSeriesHandler) DeleteSeriesList(ctx *gin.Context) {
+	var deleteDto dto.ArticleSeriesDeleteDto
+	if err := ctx.ShouldBindJSON(&deleteDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind delete series JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := deleteDto.ValidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate delete series request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := service.ArticleSeriesService.DeleteSeriesList(ctx, deleteDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
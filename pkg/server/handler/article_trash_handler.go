@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var ArticleTrashHandler = new(articleTrashHandler)
+
+type articleTrashHandler struct {
+}
+
+func (c *articleTrashHandler) ListTrash(ctx *gin.Context) {
+	var listDto dto.ArticleTrashListDto
+	// 先设置默认，防止binding校验失败
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate article trash list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindJSON(&listDto); err != nil && !errors.Is(err, io.EOF) {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind article trash list JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	// 第二次设置默认，防止传入零值
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate article trash list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	articleList, err := service.ArticleTrashService.ListTrash(ctx, listDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, articleList)
+}
+
+func (c *articleTrashHandler) RestoreArticleList(ctx *gin.Context) {
+	trashDto, ok := bindArticleTrash(ctx)
+	if !ok {
+		return
+	}
+	if err := service.ArticleTrashService.RestoreArticleList(ctx, trashDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *articleTrashHandler) PurgeArticleList(ctx *gin.Context) {
+	trashDto, ok := bindArticleTrash(ctx)
+	if !ok {
+		return
+	}
+	if err := service.ArticleTrashService.PurgeArticleList(ctx, trashDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func bindArticleTrash(ctx *gin.Context) (dto.ArticleTrashDto, bool) {
+	var trashDto dto.ArticleTrashDto
+	if err := ctx.ShouldBindJSON(&trashDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind article trash JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return trashDto, false
+	}
+	if err := trashDto.VlidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate article trash request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return trashDto, false
+	}
+	return trashDto, true
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var AuditLogHandler = new(auditLogHandler)
+
+type auditLogHandler struct {
+}
+
+func (c *auditLogHandler) ListAuditLog(ctx *gin.Context) {
+	var listDto dto.AuditLogListDto
+	// 先设置默认，防止binding校验失败
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate audit log list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindJSON(&listDto); err != nil && !errors.Is(err, io.EOF) {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind audit log list JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	// 第二次设置默认，防止传入零值
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate audit log list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	auditLogList, err := service.AuditLogService.ListAuditLog(ctx, listDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, auditLogList)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var CommentHandler = new(commentHandler)
+
+type commentHandler struct {
+}
+
+func (c *commentHandler) ListComment(ctx *gin.Context) {
+	var listDto dto.CommentListDto
+	// 先设置默认，防止binding校验失败
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate comment list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindQuery(&listDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind comment list query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	commentList, err := service.CommentService.ListComment(ctx, listDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, commentList)
+}
+
+func (c *commentHandler) CreateComment(ctx *gin.Context) {
+	var createDto dto.CommentCreateDto
+	if err := ctx.ShouldBindJSON(&createDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind create comment JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := createDto.VlidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate create comment request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.CommentService.CreateComment(ctx, createDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *commentHandler) ListCommentAdmin(ctx *gin.Context) {
+	var listDto dto.CommentAdminListDto
+	// 先设置默认，防止binding校验失败
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate comment admin list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindJSON(&listDto); err != nil && !errors.Is(err, io.EOF) {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind comment admin list JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	// 第二次设置默认，防止传入零值
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate comment admin list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	commentList, err := service.CommentService.ListCommentAdmin(ctx, listDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, commentList)
+}
+
+func (c *commentHandler) ApproveCommentList(ctx *gin.Context) {
+	idsDto, ok := bindCommentIDs(ctx)
+	if !ok {
+		return
+	}
+	if err := service.CommentService.ApproveCommentList(ctx, idsDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *commentHandler) HideCommentList(ctx *gin.Context) {
+	idsDto, ok := bindCommentIDs(ctx)
+	if !ok {
+		return
+	}
+	if err := service.CommentService.HideCommentList(ctx, idsDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *commentHandler) DeleteCommentList(ctx *gin.Context) {
+	idsDto, ok := bindCommentIDs(ctx)
+	if !ok {
+		return
+	}
+	if err := service.CommentService.DeleteCommentList(ctx, idsDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func bindCommentIDs(ctx *gin.Context) (dto.CommentIDsDto, bool) {
+	var idsDto dto.CommentIDsDto
+	if err := ctx.ShouldBindJSON(&idsDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind comment ids JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return idsDto, false
+	}
+	if err := idsDto.VlidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate comment ids request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return idsDto, false
+	}
+	return idsDto, true
+}
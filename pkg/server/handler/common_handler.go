@@ -59,3 +59,23 @@ func (c *commonHandler) PublicKeyEncrypt(ctx *gin.Context) {
 	}
 	resp.OK(ctx, result)
 }
+
+func (c *commonHandler) GetCaptcha(ctx *gin.Context) {
+	result, err := service.LoginGuardService.NewCaptcha(ctx)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, result)
+}
+
+// GetJWKS 按RFC 7517格式返回公钥集合，未配置非对称密钥时返回404
+func (c *commonHandler) GetJWKS(ctx *gin.Context) {
+	jwks := service.CommonServiceInstance.GetJWKS()
+	if len(jwks.Keys) == 0 {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	ctx.Header("Cache-Control", "public, max-age=3600")
+	ctx.JSON(http.StatusOK, jwks)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/feed"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var FeedHandler = new(feedHandler)
+
+type feedHandler struct {
+}
+
+func (c *feedHandler) RSS(ctx *gin.Context) {
+	c.serveFeed(ctx, "application/rss+xml; charset=utf-8", (*feed.Feed).ToRSS)
+}
+
+func (c *feedHandler) Atom(ctx *gin.Context) {
+	c.serveFeed(ctx, "application/atom+xml; charset=utf-8", (*feed.Feed).ToAtom)
+}
+
+func (c *feedHandler) JSONFeed(ctx *gin.Context) {
+	c.serveFeed(ctx, "application/feed+json; charset=utf-8", (*feed.Feed).ToJSON)
+}
+
+func (c *feedHandler) serveFeed(ctx *gin.Context, contentType string, encode func(*feed.Feed) ([]byte, error)) {
+	l := logger.FromContext(ctx.Request.Context())
+	var feedDto dto.FeedDto
+	if err := ctx.ShouldBindQuery(&feedDto); err != nil {
+		l.Error("Failed to bind feed query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := feedDto.VlidateAndDefault(); err != nil {
+		l.Error("Failed to validate feed request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	// 根据文章ID和更新时间判断订阅源是否变化，未变化时无需生成订阅源
+	versions, err := service.FeedService.ListFeedVersion(ctx, feedDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	etag := feed.ETag(ctx.Request.URL.RequestURI(), versions)
+	lastModified := feed.LastModified(versions)
+	ctx.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if feed.NotModified(ctx.GetHeader("If-None-Match"), ctx.GetHeader("If-Modified-Since"), etag, lastModified) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	result, err := service.FeedService.BuildFeed(ctx, feedDto, ctx.Request.URL.Path)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	data, err := encode(result)
+	if err != nil {
+		l.Error("Failed to encode feed", zap.Error(err))
+		resp.Fail(ctx, err)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, data)
+}
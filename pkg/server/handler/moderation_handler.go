@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var ModerationHandler = new(moderationHandler)
+
+type moderationHandler struct {
+}
+
+func (c *moderationHandler) ListModeration(ctx *gin.Context) {
+	var listDto dto.ModerationListDto
+	// 先设置默认，防止binding校验失败
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate moderation list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindJSON(&listDto); err != nil && !errors.Is(err, io.EOF) {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind moderation list JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	// 第二次设置默认，防止传入零值
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate moderation list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	moderationList, err := service.ModerationService.ListModeration(ctx, listDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, moderationList)
+}
+
+func (c *moderationHandler) ApproveModerationList(ctx *gin.Context) {
+	reviewDto, ok := bindModerationReview(ctx)
+	if !ok {
+		return
+	}
+	if err := service.ModerationService.ApproveModerationList(ctx, reviewDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *moderationHandler) RejectModerationList(ctx *gin.Context) {
+	reviewDto, ok := bindModerationReview(ctx)
+	if !ok {
+		return
+	}
+	if err := service.ModerationService.RejectModerationList(ctx, reviewDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func bindModerationReview(ctx *gin.Context) (dto.ModerationReviewDto, bool) {
+	var reviewDto dto.ModerationReviewDto
+	if err := ctx.ShouldBindJSON(&reviewDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind moderation review JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return reviewDto, false
+	}
+	if err := reviewDto.VlidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate moderation review request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return reviewDto, false
+	}
+	return reviewDto, true
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var OidcHandler = new(oidcHandler)
+
+type oidcHandler struct {
+}
+
+func (c *oidcHandler) ListProviders(ctx *gin.Context) {
+	resp.OK(ctx, service.OidcService.ListProviders(ctx))
+}
+
+func (c *oidcHandler) Authorize(ctx *gin.Context) {
+	var authorizeDto dto.OidcAuthorizeDto
+	if err := ctx.ShouldBindQuery(&authorizeDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind oidc authorize query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	result, err := service.OidcService.Authorize(ctx, authorizeDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, result)
+}
+
+func (c *oidcHandler) Callback(ctx *gin.Context) {
+	var callbackDto dto.OidcCallbackDto
+	if err := ctx.ShouldBindJSON(&callbackDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind oidc callback JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	token, err := service.OidcService.Callback(ctx, callbackDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, token)
+}
+
+func (c *oidcHandler) AuthorizeLink(ctx *gin.Context) {
+	var authorizeDto dto.OidcAuthorizeDto
+	if err := ctx.ShouldBindQuery(&authorizeDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind oidc link authorize query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	result, err := service.OidcService.AuthorizeLink(ctx, authorizeDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, result)
+}
+
+func (c *oidcHandler) LinkCallback(ctx *gin.Context) {
+	var callbackDto dto.OidcCallbackDto
+	if err := ctx.ShouldBindJSON(&callbackDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind oidc link callback JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.OidcService.LinkCallback(ctx, callbackDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *oidcHandler) ListIdentities(ctx *gin.Context) {
+	identities, err := service.OidcService.ListIdentities(ctx)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, identities)
+}
+
+func (c *oidcHandler) Unlink(ctx *gin.Context) {
+	var unlinkDto dto.OidcUnlinkDto
+	if err := ctx.ShouldBindJSON(&unlinkDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind oidc unlink JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.OidcService.Unlink(ctx, unlinkDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
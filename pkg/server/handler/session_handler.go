@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var SessionHandler = new(sessionHandler)
+
+type sessionHandler struct {
+}
+
+func (c *sessionHandler) ListSessions(ctx *gin.Context) {
+	sessions, err := service.SessionService.ListSessions(ctx)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, sessions)
+}
+
+func (c *sessionHandler) RevokeSession(ctx *gin.Context) {
+	var revokeDto dto.SessionRevokeDto
+	if err := ctx.ShouldBindJSON(&revokeDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind revoke session JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.SessionService.RevokeSession(ctx, revokeDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *sessionHandler) RevokeOtherSessions(ctx *gin.Context) {
+	if err := service.SessionService.RevokeOtherSessions(ctx); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
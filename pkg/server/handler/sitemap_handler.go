@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+)
+
+var SitemapHandler = new(sitemapHandler)
+
+type sitemapHandler struct {
+}
+
+func (c *sitemapHandler) Sitemap(ctx *gin.Context) {
+	c.serveSitemap(ctx, 0)
+}
+
+// SitemapPage 拆分后的子sitemap，路径形如 /sitemap/1.xml
+func (c *sitemapHandler) SitemapPage(ctx *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(ctx.Param("page"), ".xml"))
+	if err != nil || page <= 0 {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	c.serveSitemap(ctx, page)
+}
+
+func (c *sitemapHandler) serveSitemap(ctx *gin.Context, page int) {
+	data, err := service.SitemapService.GetSitemap(ctx, page)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	if data == nil {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+}
+
+func (c *sitemapHandler) Robots(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/plain; charset=utf-8", service.SitemapService.GetRobots())
+}
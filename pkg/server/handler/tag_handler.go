@@ -110,7 +110,7 @@ func (c *tagHandler) DeleteTagList(ctx *gin.Context) {
 		resp.ParamFail(ctx, err.Error())
 		return
 	}
-	if err := service.TagService.DeleteTagList(deleteRequest); err != nil {
+	if err := service.TagService.DeleteTagList(ctx, deleteRequest); err != nil {
 		resp.Fail(ctx, err)
 		return
 	}
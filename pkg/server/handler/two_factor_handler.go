@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var TwoFactorHandler = new(twoFactorHandler)
+
+type twoFactorHandler struct {
+}
+
+func (c *twoFactorHandler) GetStatus(ctx *gin.Context) {
+	status, err := service.TwoFactorService.GetStatus(ctx)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, status)
+}
+
+func (c *twoFactorHandler) Enroll(ctx *gin.Context) {
+	enroll, err := service.TwoFactorService.Enroll(ctx)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, enroll)
+}
+
+func (c *twoFactorHandler) Activate(ctx *gin.Context) {
+	var codeDto dto.TotpCodeDto
+	if err := ctx.ShouldBindJSON(&codeDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind activate totp JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	codes, err := service.TwoFactorService.Activate(ctx, codeDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, codes)
+}
+
+func (c *twoFactorHandler) Disable(ctx *gin.Context) {
+	var disableDto dto.TotpDisableDto
+	if err := ctx.ShouldBindJSON(&disableDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind disable totp JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.TwoFactorService.Disable(ctx, disableDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *twoFactorHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var codeDto dto.TotpCodeDto
+	if err := ctx.ShouldBindJSON(&codeDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind regenerate recovery codes JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	codes, err := service.TwoFactorService.RegenerateRecoveryCodes(ctx, codeDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, codes)
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var UserAdminHandler = new(userAdminHandler)
+
+type userAdminHandler struct {
+}
+
+func (c *userAdminHandler) ListUser(ctx *gin.Context) {
+	var listDto dto.UserListDto
+	// 先设置默认，防止binding校验失败
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate user list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := ctx.ShouldBindJSON(&listDto); err != nil && !errors.Is(err, io.EOF) {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind user list JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	// 第二次设置默认，防止传入零值
+	if err := listDto.VlidateAndSetDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate user list request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	userList, err := service.UserAdminService.ListUser(ctx, listDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, userList)
+}
+
+func (c *userAdminHandler) UpdateUser(ctx *gin.Context) {
+	var updateDto dto.UserUpdateDto
+	if err := ctx.ShouldBindJSON(&updateDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind update user JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := updateDto.VlidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate update user request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.UserAdminService.UpdateUser(ctx, updateDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *userAdminHandler) DisableUserList(ctx *gin.Context) {
+	idsDto, ok := bindUserIDs(ctx)
+	if !ok {
+		return
+	}
+	if err := service.UserAdminService.DisableUserList(ctx, idsDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *userAdminHandler) EnableUserList(ctx *gin.Context) {
+	idsDto, ok := bindUserIDs(ctx)
+	if !ok {
+		return
+	}
+	if err := service.UserAdminService.EnableUserList(ctx, idsDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *userAdminHandler) ForcePasswordReset(ctx *gin.Context) {
+	idsDto, ok := bindUserIDs(ctx)
+	if !ok {
+		return
+	}
+	if err := service.UserAdminService.ForcePasswordReset(ctx, idsDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func bindUserIDs(ctx *gin.Context) (dto.UserIDsDto, bool) {
+	var idsDto dto.UserIDsDto
+	if err := ctx.ShouldBindJSON(&idsDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind user ids JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return idsDto, false
+	}
+	if err := idsDto.VlidateAndDefault(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate user ids request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return idsDto, false
+	}
+	return idsDto, true
+}
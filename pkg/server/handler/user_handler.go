@@ -58,6 +58,22 @@ func (c *userHandler) Login(ctx *gin.Context) {
 	resp.OK(ctx, token)
 }
 
+func (c *userHandler) LoginTwoFactor(ctx *gin.Context) {
+	var loginDto dto.LoginTwoFactorDto
+	if err := ctx.ShouldBindJSON(&loginDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind two-factor login JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	token, err := service.TwoFactorService.CompleteLogin(ctx, loginDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, token)
+}
+
 func (c *userHandler) Logout(ctx *gin.Context) {
 	var logoutDto dto.LogoutDto
 	if err := ctx.ShouldBindJSON(&logoutDto); err != nil {
@@ -100,3 +116,89 @@ func (c *userHandler) RefreshToken(ctx *gin.Context) {
 	}
 	resp.OK(ctx, result)
 }
+
+func (c *userHandler) GetProfile(ctx *gin.Context) {
+	profile, err := service.UserServiceInstance.GetProfile(ctx)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, profile)
+}
+
+func (c *userHandler) UpdateProfile(ctx *gin.Context) {
+	var profileDto dto.UserProfileDto
+	if err := ctx.ShouldBindJSON(&profileDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind update profile JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := profileDto.Validate(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate update profile request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	profile, err := service.UserServiceInstance.UpdateProfile(ctx, profileDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, profile)
+}
+
+func (c *userHandler) ChangePassword(ctx *gin.Context) {
+	var passwordDto dto.ChangePasswordDto
+	if err := ctx.ShouldBindJSON(&passwordDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind change password JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := passwordDto.Validate(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate change password request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	token, err := service.UserServiceInstance.ChangePassword(ctx, passwordDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, token)
+}
+
+func (c *userHandler) ForgotPassword(ctx *gin.Context) {
+	var forgotDto dto.ForgotPasswordDto
+	if err := ctx.ShouldBindJSON(&forgotDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind forgot password JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.PasswordResetService.RequestReset(ctx, forgotDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *userHandler) ResetPassword(ctx *gin.Context) {
+	var resetDto dto.ResetPasswordDto
+	if err := ctx.ShouldBindJSON(&resetDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind reset password JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := resetDto.Validate(); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to validate reset password request", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.PasswordResetService.ConfirmReset(ctx, resetDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
+	resp "github.com/narcissus1949/narcissus-blog/pkg/vo/response"
+	"go.uber.org/zap"
+)
+
+var UserRoleHandler = new(userRoleHandler)
+
+type userRoleHandler struct {
+}
+
+func (c *userRoleHandler) ListUserRoles(ctx *gin.Context) {
+	var queryDto dto.UserRoleQueryDto
+	if err := ctx.ShouldBindQuery(&queryDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind list user roles query", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	roles, err := service.UserRoleService.ListUserRoles(ctx, queryDto)
+	if err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, roles)
+}
+
+func (c *userRoleHandler) GrantRole(ctx *gin.Context) {
+	var roleDto dto.UserRoleDto
+	if err := ctx.ShouldBindJSON(&roleDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind grant role JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := roleDto.VlidateAndDefault(); err != nil {
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.UserRoleService.GrantRole(ctx, roleDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
+
+func (c *userRoleHandler) RevokeRole(ctx *gin.Context) {
+	var roleDto dto.UserRoleDto
+	if err := ctx.ShouldBindJSON(&roleDto); err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to bind revoke role JSON", zap.Error(err))
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+	if err := roleDto.VlidateAndDefault(); err != nil {
+		resp.ParamFail(ctx, err.Error())
+		return
+	}
+
+	if err := service.UserRoleService.RevokeRole(ctx, roleDto); err != nil {
+		resp.Fail(ctx, err)
+		return
+	}
+	resp.OK(ctx, nil)
+}
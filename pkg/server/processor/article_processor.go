@@ -6,9 +6,13 @@ import (
 	"time"
 
 	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
 	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/related"
+	"github.com/narcissus1949/narcissus-blog/internal/trash"
 	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/service"
 	"go.uber.org/zap"
 )
 
@@ -90,3 +94,129 @@ func UpdateArticleViewCount(ctx context.Context) []error {
 	}
 	return errList
 }
+
+// 定时发布检查间隔
+const scheduledPublishInterval = time.Minute
+
+func RunScheduledPublishProcessor(ctx context.Context) {
+	go func(ctx context.Context) {
+		ticker := time.NewTicker(scheduledPublishInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				PublishScheduledArticles(ctx)
+			case <-ctx.Done():
+				logger.FromContext(ctx).Info("Article scheduled publish processor stopped")
+				return
+			}
+		}
+	}(ctx)
+}
+
+// PublishScheduledArticles 将已到定时发布时间的文章上线，并加入搜索索引、清除sitemap缓存
+func PublishScheduledArticles(ctx context.Context) {
+	now := time.Now()
+	var ids []int64
+	var rowsAffected int64
+	// 在同一事务中锁定并更新查询到的文章，保证更新的文章与之后建立索引的文章一致
+	if err := mysql.RunDBTransaction2(ctx, func(ctx context.Context) error {
+		var err error
+		ids, err = dao.ArticleDao.ListScheduledArticleIDs(ctx, now)
+		if err != nil {
+			return fmt.Errorf("list scheduled articles: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		rowsAffected, err = dao.ArticleDao.PublishScheduledArticles(ctx, ids, now)
+		if err != nil {
+			return fmt.Errorf("publish scheduled articles: %w", err)
+		}
+		return nil
+	}); err != nil {
+		logger.FromContext(ctx).Error("Failed to publish scheduled articles", zap.Error(err))
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+	logger.FromContext(ctx).Info("Publish scheduled articles done", zap.Int64("total", rowsAffected))
+	if err := service.ArticleSearchService.IndexArticles(ctx, ids); err != nil {
+		logger.FromContext(ctx).Error("Failed to update article search index", zap.Error(err), zap.Int64s("ids", ids))
+	}
+	if err := service.SitemapService.InvalidateCache(ctx); err != nil {
+		logger.FromContext(ctx).Error("Failed to invalidate sitemap cache", zap.Error(err))
+	}
+}
+
+// 搜索索引全量重建间隔，用于修正增量更新失败导致的偏差
+const searchIndexRebuildInterval = 6 * time.Hour
+
+func RunSearchIndexProcessor(ctx context.Context) {
+	go func(ctx context.Context) {
+		ticker := time.NewTicker(searchIndexRebuildInterval)
+		defer ticker.Stop()
+		for {
+			if err := utils.Retry(3, 1000, func() error {
+				return service.ArticleSearchService.RebuildIndex(ctx)
+			}); err != nil {
+				logger.FromContext(ctx).Error("Search index processor failed after 3 times retry")
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				logger.FromContext(ctx).Info("Article search index processor stopped")
+				return
+			}
+		}
+	}(ctx)
+}
+
+func RunRelatedArticleProcessor(ctx context.Context) {
+	go func(ctx context.Context) {
+		ticker := time.NewTicker(time.Duration(related.Config.RebuildInterval) * time.Minute)
+		defer ticker.Stop()
+		for {
+			if err := utils.Retry(3, 1000, func() error {
+				return service.ArticleRelatedService.Rebuild(ctx)
+			}); err != nil {
+				logger.FromContext(ctx).Error("Related article processor failed after 3 times retry")
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				logger.FromContext(ctx).Info("Related article processor stopped")
+				return
+			}
+		}
+	}(ctx)
+}
+
+func RunTrashPurgeProcessor(ctx context.Context) {
+	go func(ctx context.Context) {
+		ticker := time.NewTicker(time.Duration(trash.Config.PurgeInterval) * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				PurgeExpiredArticles(ctx)
+			case <-ctx.Done():
+				logger.FromContext(ctx).Info("Article trash purge processor stopped")
+				return
+			}
+		}
+	}(ctx)
+}
+
+// PurgeExpiredArticles 永久删除回收站中超过保留时间的文章
+func PurgeExpiredArticles(ctx context.Context) {
+	total, err := service.ArticleTrashService.PurgeExpiredArticles(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to purge expired articles", zap.Error(err))
+		return
+	}
+	if total > 0 {
+		logger.FromContext(ctx).Info("Purge expired articles done", zap.Int("total", total), zap.Int("retention", trash.Config.Retention))
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var ArticleDraftService = new(articleDraftService)
+
+type articleDraftService struct {
+}
+
+// SaveDraft 自动保存当前用户的文章草稿，草稿与已发布的文章内容分开存储
+func (s *articleDraftService) SaveDraft(c *gin.Context, draftDto dto.ArticleDraftDto) (*vo.ArticleDraftVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	currentVersion, err := s.getCurrentVersion(c, draftDto.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	draft := &model.ArticleDraft{
+		ArticleID:   draftDto.ArticleID,
+		UserID:      c.GetInt(utils.CONTEXT_USER_ID),
+		Title:       draftDto.Title,
+		Summary:     draftDto.Summary,
+		Content:     draftDto.Content,
+		Category:    draftDto.Category,
+		Tags:        strings.Join(draftDto.Tags, ","),
+		BaseVersion: draftDto.BaseVersion,
+		UpdatedTime: time.Now(),
+	}
+	if err := dao.ArticleDraftDao.SaveDraft(c, draft); err != nil {
+		l.Error("Failed to save article draft", zap.Error(err), zap.Int64("article id", draftDto.ArticleID))
+		return nil, err
+	}
+	return buildArticleDraftVo(draft, currentVersion), nil
+}
+
+// GetDraft 查询当前用户的文章草稿，并标记草稿是否已落后于文章当前版本
+func (s *articleDraftService) GetDraft(c *gin.Context, queryDto dto.ArticleDraftQueryDto) (*vo.ArticleDraftVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	currentVersion, err := s.getCurrentVersion(c, queryDto.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	draft, err := dao.ArticleDraftDao.QueryDraft(c, queryDto.ArticleID, c.GetInt(utils.CONTEXT_USER_ID))
+	if err != nil {
+		l.Error("Failed to query article draft", zap.Error(err), zap.Int64("article id", queryDto.ArticleID))
+		return nil, err
+	}
+	if draft == nil {
+		return nil, cerr.New(cerr.ERROR_ARTICLE_DRAFT_NOT_EXIST)
+	}
+	return buildArticleDraftVo(draft, currentVersion), nil
+}
+
+// DeleteDraft 丢弃当前用户的文章草稿
+func (s *articleDraftService) DeleteDraft(c *gin.Context, queryDto dto.ArticleDraftQueryDto) error {
+	l := logger.FromContext(c.Request.Context())
+	if err := dao.ArticleDraftDao.DeleteDraft(c, queryDto.ArticleID, c.GetInt(utils.CONTEXT_USER_ID)); err != nil {
+		l.Error("Failed to delete article draft", zap.Error(err), zap.Int64("article id", queryDto.ArticleID))
+		return err
+	}
+	return nil
+}
+
+// getCurrentVersion 查询文章当前版本号，新建文章返回0
+func (s *articleDraftService) getCurrentVersion(c *gin.Context, articleID int64) (int, error) {
+	if articleID == 0 {
+		return 0, nil
+	}
+	l := logger.FromContext(c.Request.Context())
+	article, err := dao.ArticleDao.QueryArticleByID(c, articleID)
+	if err != nil {
+		l.Error("Failed to query article", zap.Error(err), zap.Int64("article id", articleID))
+		return 0, err
+	}
+	if article == nil {
+		return 0, cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
+	}
+	return article.Version, nil
+}
+
+func buildArticleDraftVo(draft *model.ArticleDraft, currentVersion int) *vo.ArticleDraftVo {
+	tagList := []string{}
+	if len(draft.Tags) > 0 {
+		tagList = strings.Split(draft.Tags, ",")
+	}
+	return &vo.ArticleDraftVo{
+		ArticleID:      draft.ArticleID,
+		Title:          draft.Title,
+		Summary:        draft.Summary,
+		Content:        draft.Content,
+		Category:       draft.Category,
+		TagNameList:    tagList,
+		BaseVersion:    draft.BaseVersion,
+		CurrentVersion: currentVersion,
+		Stale:          draft.ArticleID > 0 && draft.BaseVersion != currentVersion,
+		UpdatedTime:    draft.UpdatedTime.UnixMilli(),
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/related"
+	"github.com/narcissus1949/narcissus-blog/internal/search"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var ArticleRelatedService = new(articleRelatedService)
+
+type articleRelatedService struct {
+}
+
+// Rebuild 重新计算全部已发布文章的相关文章并写入缓存。
+// 缓存有效期为两个计算周期，已下线文章的缓存不再更新，到期后自动删除
+func (s *articleRelatedService) Rebuild(ctx context.Context) error {
+	detailList, err := dao.ArticleDao.ListPublishedArticleDetail(ctx, nil)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to list published article detail", zap.Error(err))
+		return err
+	}
+	docs := make([]related.Document, 0, len(detailList))
+	for i := range detailList {
+		var tags []string
+		if len(detailList[i].TagNameList) > 0 {
+			tags = strings.Split(detailList[i].TagNameList, ",")
+		}
+		docs = append(docs, related.Document{
+			ID:         detailList[i].ID,
+			CategoryID: detailList[i].CategoryID,
+			Tags:       tags,
+			Text:       detailList[i].Title + " " + search.PlainText(detailList[i].Content),
+		})
+	}
+	result := related.Compute(docs, related.Config.MaxLimit)
+
+	expire := 2 * time.Duration(related.Config.RebuildInterval) * time.Minute
+	pipe := cache.Client.Pipeline()
+	for id, items := range result {
+		data, err := json.Marshal(items)
+		if err != nil {
+			logger.FromContext(ctx).Error("Failed to marshal related articles", zap.Error(err), zap.Int64("article id", id))
+			return err
+		}
+		pipe.Set(ctx, fmt.Sprintf(utils.ARTICLE_RELATED_KEY_TEMPLATE, id), data, expire)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.FromContext(ctx).Error("Failed to set related articles cache", zap.Error(err))
+		return err
+	}
+	logger.FromContext(ctx).Info("Rebuild related articles done", zap.Int("total", len(docs)))
+	return nil
+}
+
+// ListRelated 获取文章的相关文章，尚未计算的文章返回空列表
+func (s *articleRelatedService) ListRelated(c *gin.Context, relatedDto dto.ArticleRelatedDto) (*vo.ArticleRelatedVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	result := &vo.ArticleRelatedVo{ArticleList: []vo.ArticleRelatedItemVo{}}
+	cacheKey := fmt.Sprintf(utils.ARTICLE_RELATED_KEY_TEMPLATE, relatedDto.ID)
+	cached, err := cache.Client.Get(c, cacheKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return result, nil
+	}
+	if err != nil {
+		l.Error("Failed to get related articles from cache", zap.Error(err), zap.String("key", cacheKey))
+		return nil, err
+	}
+	var items []related.Item
+	if err := json.Unmarshal(cached, &items); err != nil {
+		l.Error("Failed to unmarshal related articles", zap.Error(err), zap.String("key", cacheKey))
+		return nil, err
+	}
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	detailList, err := dao.ArticleDao.ListPublishedArticleDetail(c, ids)
+	if err != nil {
+		l.Error("Failed to list published article detail", zap.Error(err), zap.Int64s("ids", ids))
+		return nil, err
+	}
+	detailMap := make(map[int64]*model.ArticleDetail, len(detailList))
+	for i := range detailList {
+		detailMap[detailList[i].ID] = &detailList[i]
+	}
+	for _, item := range items {
+		detail, ok := detailMap[item.ID]
+		if !ok {
+			// 缓存尚未更新，跳过已下线或已删除的文章
+			continue
+		}
+		tagList := []string{}
+		if len(detail.TagNameList) > 0 {
+			tagList = strings.Split(detail.TagNameList, ",")
+			sort.Strings(tagList)
+		}
+		result.ArticleList = append(result.ArticleList, vo.ArticleRelatedItemVo{
+			ID:           detail.ID,
+			Title:        detail.Title,
+			Slug:         detail.Slug,
+			Summary:      detail.Summary,
+			CategoryName: detail.CategoryName,
+			TagNameList:  tagList,
+			Score:        item.Score,
+			CreatedTime:  detail.CreatedTime.UnixMilli(),
+			UpdatedTime:  detail.UpdatedTime.UnixMilli(),
+		})
+		if len(result.ArticleList) >= relatedDto.Limit {
+			break
+		}
+	}
+	return result, nil
+}
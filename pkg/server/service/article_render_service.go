@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/markdown"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 渲染结果缓存时间，内容不变时缓存一直有效，过期只用于回收不再使用的缓存
+const articleRenderCacheExpire = 7 * 24 * time.Hour
+
+var ArticleRenderService = new(articleRenderService)
+
+type articleRenderService struct {
+}
+
+// Render 渲染Markdown，结果按内容hash缓存在redis中
+func (s *articleRenderService) Render(ctx context.Context, content string) (*markdown.Result, error) {
+	l := logger.FromContext(ctx)
+	sum := sha256.Sum256([]byte(content))
+	key := fmt.Sprintf(utils.ARTICLE_RENDER_KEY_TEMPLATE, markdown.Version, hex.EncodeToString(sum[:]))
+
+	cached, err := cache.Client.Get(ctx, key).Bytes()
+	if err == nil {
+		var result markdown.Result
+		unmarshalErr := json.Unmarshal(cached, &result)
+		if unmarshalErr == nil {
+			return &result, nil
+		}
+		l.Error("Failed to unmarshal article render cache", zap.Error(unmarshalErr), zap.String("key", key))
+	} else if !errors.Is(err, redis.Nil) {
+		l.Error("Failed to get article render cache", zap.Error(err), zap.String("key", key))
+	}
+
+	result, err := markdown.Render(content)
+	if err != nil {
+		l.Error("Failed to render markdown", zap.Error(err))
+		return nil, err
+	}
+	if data, err := json.Marshal(result); err != nil {
+		l.Error("Failed to marshal article render result", zap.Error(err))
+	} else if err := cache.Client.Set(ctx, key, data, articleRenderCacheExpire).Err(); err != nil {
+		l.Error("Failed to set article render cache", zap.Error(err), zap.String("key", key))
+	}
+	return result, nil
+}
+
+// RenderDetail 为文章详情填充渲染后的HTML、目录和阅读时间，渲染失败时只返回原始内容
+func (s *articleRenderService) RenderDetail(c *gin.Context, detail *vo.ArticleDetailVo) {
+	result, err := s.Render(c.Request.Context(), detail.Content)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to render article detail", zap.Error(err), zap.Int64("article id", detail.ID))
+		return
+	}
+	detail.ContentHTML = result.HTML
+	detail.TOC = result.TOC
+	detail.ReadingTime = result.ReadingTime
+}
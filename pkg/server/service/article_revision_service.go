@@ -0,0 +1,261 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/diff"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// unified diff上下文行数
+const revisionDiffContext = 3
+
+var ArticleRevisionService = new(articleRevisionService)
+
+type articleRevisionService struct {
+}
+
+// SaveRevision 以文章当前内容追加一个新版本，需在保存文章的事务中执行
+func (s *articleRevisionService) SaveRevision(c *gin.Context, articleID int64) error {
+	l := logger.FromContext(c.Request.Context())
+	articleDetail, err := dao.ArticleDao.QueryArticleDetail(c, articleID)
+	if err != nil {
+		l.Error("Failed to query article detail", zap.Error(err), zap.Int64("article id", articleID))
+		return err
+	}
+	maxRevision, err := dao.ArticleRevisionDao.QueryMaxRevision(c, articleID)
+	if err != nil {
+		l.Error("Failed to query max revision", zap.Error(err), zap.Int64("article id", articleID))
+		return err
+	}
+
+	tagList := []string{}
+	if len(articleDetail.TagNameList) > 0 {
+		tagList = strings.Split(articleDetail.TagNameList, ",")
+		sort.Strings(tagList)
+	}
+	revision := &model.ArticleRevision{
+		ArticleID:    articleID,
+		Revision:     maxRevision + 1,
+		Title:        articleDetail.Title,
+		Summary:      articleDetail.Summary,
+		Content:      articleDetail.Content,
+		CategoryID:   articleDetail.CategoryID,
+		CategoryName: articleDetail.CategoryName,
+		TagNameList:  strings.Join(tagList, ","),
+		EditorID:     c.GetInt(utils.CONTEXT_USER_ID),
+		CreatedTime:  time.Now(),
+	}
+	if err := dao.ArticleRevisionDao.InsertRevision(c, revision); err != nil {
+		l.Error("Failed to insert article revision", zap.Error(err), zap.Int64("article id", articleID))
+		return err
+	}
+	return nil
+}
+
+// ListRevision 分页查询文章版本
+func (s *articleRevisionService) ListRevision(c *gin.Context, listDto dto.ArticleRevisionListDto) (*vo.ArticleRevisionListVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	if err := s.checkPermission(c, "list revision", listDto.ArticleID); err != nil {
+		return nil, err
+	}
+	var revisionList []model.ArticleRevision
+	var total int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		var listErr error
+		revisionList, listErr = dao.ArticleRevisionDao.ListRevision(c, listDto.ArticleID, listDto.Pageinate)
+		if listErr != nil {
+			l.Error("Failed to list article revision", zap.Error(listErr), zap.Int64("article id", listDto.ArticleID))
+			return listErr
+		}
+		var countErr error
+		total, countErr = dao.ArticleRevisionDao.CountRevision(c, listDto.ArticleID)
+		if countErr != nil {
+			l.Error("Failed to count article revision", zap.Error(countErr), zap.Int64("article id", listDto.ArticleID))
+			return countErr
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to run db transaction", zap.Error(txErr))
+		return nil, txErr
+	}
+
+	list := make([]vo.ArticleRevisionVo, 0, len(revisionList))
+	for i := range revisionList {
+		list = append(list, toArticleRevisionVo(revisionList[i]))
+	}
+	return &vo.ArticleRevisionListVo{
+		RevisionList: list,
+		Pageinate:    buildPageinate(listDto.Pageinate, total),
+	}, nil
+}
+
+// GetRevision 查询文章指定版本详情
+func (s *articleRevisionService) GetRevision(c *gin.Context, revisionDto dto.ArticleRevisionDto) (*vo.ArticleRevisionVo, error) {
+	if err := s.checkPermission(c, "get revision", revisionDto.ArticleID); err != nil {
+		return nil, err
+	}
+	revision, err := s.getRevision(c, revisionDto.ArticleID, revisionDto.Revision)
+	if err != nil {
+		return nil, err
+	}
+	revisionVo := toArticleRevisionVo(*revision)
+	return &revisionVo, nil
+}
+
+// DiffRevision 比较文章两个版本，返回unified diff
+func (s *articleRevisionService) DiffRevision(c *gin.Context, diffDto dto.ArticleRevisionDiffDto) (*vo.ArticleRevisionDiffVo, error) {
+	if err := s.checkPermission(c, "diff revision", diffDto.ArticleID); err != nil {
+		return nil, err
+	}
+	from, err := s.getRevision(c, diffDto.ArticleID, diffDto.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getRevision(c, diffDto.ArticleID, diffDto.To)
+	if err != nil {
+		return nil, err
+	}
+	return &vo.ArticleRevisionDiffVo{
+		ArticleID: diffDto.ArticleID,
+		From:      diffDto.From,
+		To:        diffDto.To,
+		Diff: diff.Unified(
+			fmt.Sprintf("revision %d", from.Revision),
+			fmt.Sprintf("revision %d", to.Revision),
+			revisionText(from),
+			revisionText(to),
+			revisionDiffContext),
+	}, nil
+}
+
+// RestoreRevision 将文章恢复为指定版本，恢复结果作为一个新版本保存
+func (s *articleRevisionService) RestoreRevision(c *gin.Context, revisionDto dto.ArticleRevisionDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		revision, err := s.getRevision(c, revisionDto.ArticleID, revisionDto.Revision)
+		if err != nil {
+			return err
+		}
+		current, err := ArticleService.GetArticleDetail(c, revisionDto.ArticleID)
+		if err != nil {
+			l.Error("Failed to get article detail", zap.Error(err), zap.Int64("article id", revisionDto.ArticleID))
+			return err
+		}
+
+		// 版本只记录标题、摘要、内容、分类和标签，其余属性保持当前值
+		tags := []string{}
+		if len(revision.TagNameList) > 0 {
+			tags = strings.Split(revision.TagNameList, ",")
+		}
+		articleID := revisionDto.ArticleID
+		var publishAt *int64
+		if current.PublishAt > 0 {
+			publishAt = &current.PublishAt
+		}
+		articleDto := dto.ArticleDto{
+			ID:                  &articleID,
+			Title:               revision.Title,
+			Summary:             revision.Summary,
+			Content:             revision.Content,
+			Type:                current.Type,
+			Category:            revision.CategoryName,
+			Tags:                tags,
+			Author:              current.Author,
+			AllowComment:        current.AllowComment,
+			Weight:              current.Weight,
+			IsSticky:            current.IsSticky,
+			IsOriginal:          current.IsOriginal,
+			OriginalArticleLink: current.OriginalArticleLink,
+			Status:              current.Status,
+			PublishAt:           publishAt,
+			Version:             &current.Version,
+		}
+		if err := ArticleService.updateArticle(c, articleDto); err != nil {
+			l.Error("Failed to restore article revision", zap.Error(err), zap.Int64("article id", articleID), zap.Int("revision", revision.Revision))
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to restore article revision", zap.Error(txErr))
+		return txErr
+	}
+	ArticleService.afterArticleCommit(c, []int64{revisionDto.ArticleID})
+	return nil
+}
+
+// checkPermission 检查当前用户能否查看文章版本，作者只能查看自己文章的版本
+func (s *articleRevisionService) checkPermission(c *gin.Context, action string, articleID int64) error {
+	article, err := dao.ArticleDao.QueryArticleByID(c, articleID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to query article", zap.Error(err), zap.Int64("article id", articleID))
+		return err
+	}
+	if article == nil {
+		return cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
+	}
+	return ArticleService.checkArticlePermission(c, action, article.ID, article.OwnerID)
+}
+
+func (s *articleRevisionService) getRevision(c *gin.Context, articleID int64, revisionNum int) (*model.ArticleRevision, error) {
+	l := logger.FromContext(c.Request.Context())
+	revision, err := dao.ArticleRevisionDao.QueryRevision(c, articleID, revisionNum)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, cerr.New(cerr.ERROR_ARTICLE_REVISION_NOT_EXIST)
+		}
+		l.Error("Failed to query article revision", zap.Error(err), zap.Int64("article id", articleID), zap.Int("revision", revisionNum))
+		return nil, err
+	}
+	if revision == nil {
+		return nil, cerr.New(cerr.ERROR_ARTICLE_REVISION_NOT_EXIST)
+	}
+	return revision, nil
+}
+
+// revisionText 将版本转为用于比较的文本，元数据放在内容之前
+func revisionText(revision *model.ArticleRevision) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Title: %s\n", revision.Title)
+	fmt.Fprintf(&builder, "Summary: %s\n", revision.Summary)
+	fmt.Fprintf(&builder, "Category: %s\n", revision.CategoryName)
+	fmt.Fprintf(&builder, "Tags: %s\n", revision.TagNameList)
+	builder.WriteString("\n")
+	builder.WriteString(revision.Content)
+	return builder.String()
+}
+
+func toArticleRevisionVo(revision model.ArticleRevision) vo.ArticleRevisionVo {
+	tagList := []string{}
+	if len(revision.TagNameList) > 0 {
+		tagList = strings.Split(revision.TagNameList, ",")
+	}
+	return vo.ArticleRevisionVo{
+		ID:           revision.ID,
+		ArticleID:    revision.ArticleID,
+		Revision:     revision.Revision,
+		Title:        revision.Title,
+		Summary:      revision.Summary,
+		CategoryName: revision.CategoryName,
+		TagNameList:  tagList,
+		Content:      revision.Content,
+		EditorID:     revision.EditorID,
+		CreatedTime:  revision.CreatedTime.UnixMilli(),
+	}
+}
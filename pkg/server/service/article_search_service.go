@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/search"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+// 搜索结果正文片段长度
+const searchSnippetLen = 120
+
+var ArticleSearchService = new(articleSearchService)
+
+type articleSearchService struct {
+}
+
+// RebuildIndex 从数据库重建全部已发布文章的索引
+func (s *articleSearchService) RebuildIndex(ctx context.Context) error {
+	detailList, err := dao.ArticleDao.ListPublishedArticleDetail(ctx, nil)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to list published article detail", zap.Error(err))
+		return err
+	}
+	docs := make([]search.Document, 0, len(detailList))
+	for i := range detailList {
+		docs = append(docs, buildSearchDocument(&detailList[i]))
+	}
+	search.Default.Reset(docs)
+	logger.FromContext(ctx).Info("Rebuild article search index done", zap.Int("total", len(docs)))
+	return nil
+}
+
+// IndexArticles 增量更新文章索引，需在保存文章的事务提交后调用，未发布的文章会从索引中移除
+func (s *articleSearchService) IndexArticles(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	detailList, err := dao.ArticleDao.ListPublishedArticleDetail(ctx, ids)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to list published article detail", zap.Error(err), zap.Int64s("ids", ids))
+		return err
+	}
+	published := map[int64]bool{}
+	for i := range detailList {
+		search.Default.Upsert(buildSearchDocument(&detailList[i]))
+		published[detailList[i].ID] = true
+	}
+	for _, id := range ids {
+		if !published[id] {
+			search.Default.Remove(id)
+		}
+	}
+	return nil
+}
+
+// RemoveArticles 从索引中删除文章
+func (s *articleSearchService) RemoveArticles(ids []int64) {
+	search.Default.Remove(ids...)
+}
+
+// Search 全文搜索已发布文章，按相关度排序并返回高亮片段
+func (s *articleSearchService) Search(c *gin.Context, searchDto dto.ArticleSearchDto) (*vo.ArticleSearchVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	hits := search.Default.Search(searchDto.Keyword)
+	result := &vo.ArticleSearchVo{
+		ArticleList: []vo.ArticleSearchItemVo{},
+		Pageinate:   buildPageinate(searchDto.Pageinate, int64(len(hits))),
+	}
+	start := (searchDto.PageNum - 1) * searchDto.PageSize
+	if start >= len(hits) {
+		return result, nil
+	}
+	hits = hits[start:min(start+searchDto.PageSize, len(hits))]
+
+	ids := make([]int64, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	detailList, err := dao.ArticleDao.ListPublishedArticleDetail(c, ids)
+	if err != nil {
+		l.Error("Failed to list published article detail", zap.Error(err), zap.Int64s("ids", ids))
+		return nil, err
+	}
+	detailMap := make(map[int64]*model.ArticleDetail, len(detailList))
+	for i := range detailList {
+		detailMap[detailList[i].ID] = &detailList[i]
+	}
+
+	for _, hit := range hits {
+		detail, ok := detailMap[hit.ID]
+		if !ok {
+			// 索引尚未同步，跳过已下线或已删除的文章
+			continue
+		}
+		tagList := []string{}
+		if len(detail.TagNameList) > 0 {
+			tagList = strings.Split(detail.TagNameList, ",")
+			sort.Strings(tagList)
+		}
+		doc := buildSearchDocument(detail)
+		result.ArticleList = append(result.ArticleList, vo.ArticleSearchItemVo{
+			ID:             detail.ID,
+			Title:          detail.Title,
+			Summary:        detail.Summary,
+			CategoryName:   detail.CategoryName,
+			TagNameList:    tagList,
+			TitleHighlight: search.Highlight(detail.Title, searchDto.Keyword),
+			Snippet:        search.Snippet(doc.Content, searchDto.Keyword, searchSnippetLen),
+			Score:          hit.Score,
+			CreatedTime:    detail.CreatedTime.UnixMilli(),
+			UpdatedTime:    detail.UpdatedTime.UnixMilli(),
+		})
+	}
+	return result, nil
+}
+
+func buildSearchDocument(detail *model.ArticleDetail) search.Document {
+	return search.Document{
+		ID:      detail.ID,
+		Title:   detail.Title,
+		Summary: detail.Summary,
+		Content: search.PlainText(detail.Content),
+	}
+}
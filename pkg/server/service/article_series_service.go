@@ -0,0 +1,312 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var ArticleSeriesService = new(articleSeriesService)
+
+type articleSeriesService struct {
+}
+
+// 审计日志中记录的系列数据
+type seriesAuditData struct {
+	model.ArticleSeries
+	ArticleIDs []int64 `json:"article_ids"`
+}
+
+// ListSeries 分页查询系列列表
+func (s *articleSeriesService) ListSeries(c *gin.Context, listDto dto.ArticleSeriesListDto) (*vo.ArticleSeriesListVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	var seriesList []model.ArticleSeriesCount
+	var total int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		var listErr error
+		seriesList, listErr = dao.ArticleSeriesDao.ListSeries(c, listDto)
+		if listErr != nil {
+			l.Error("Failed to list series", zap.Error(listErr))
+			return listErr
+		}
+		var countErr error
+		total, countErr = dao.ArticleSeriesDao.CountSeries(c, listDto)
+		if countErr != nil {
+			l.Error("Failed to count series", zap.Error(countErr))
+			return countErr
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to run db transaction", zap.Error(txErr))
+		return nil, txErr
+	}
+
+	list := make([]vo.ArticleSeriesVo, 0, len(seriesList))
+	for i := range seriesList {
+		list = append(list, toArticleSeriesVo(seriesList[i].ArticleSeries, seriesList[i].ArticleCount))
+	}
+	return &vo.ArticleSeriesListVo{
+		SeriesList: list,
+		Pageinate:  buildPageinate(listDto.Pageinate, total),
+	}, nil
+}
+
+// GetSeries 获取系列详情及已发布文章的目录
+func (s *articleSeriesService) GetSeries(c *gin.Context, queryDto dto.ArticleSeriesQueryDto) (*vo.ArticleSeriesDetailVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	series, err := dao.ArticleSeriesDao.QuerySeriesByID(c, queryDto.ID)
+	if err != nil {
+		l.Error("Failed to query series by id", zap.Error(err), zap.Int64("id", queryDto.ID))
+		return nil, err
+	}
+	if series == nil {
+		return nil, cerr.New(cerr.ERROR_ARTICLE_SERIES_NOT_EXIST)
+	}
+	articles, err := dao.ArticleSeriesDao.ListSeriesArticles(c, series.ID, true)
+	if err != nil {
+		l.Error("Failed to list series articles", zap.Error(err), zap.Int64("id", series.ID))
+		return nil, err
+	}
+	return &vo.ArticleSeriesDetailVo{
+		ArticleSeriesVo: toArticleSeriesVo(*series, len(articles)),
+		Articles:        toArticleSeriesItemVoList(articles),
+	}, nil
+}
+
+// CreateSeries 创建系列
+func (s *articleSeriesService) CreateSeries(c *gin.Context, seriesDto dto.ArticleSeriesDto) error {
+	l := logger.FromContext(c.Request.Context())
+	series := model.ArticleSeries{
+		Name:        seriesDto.Name,
+		Description: seriesDto.Description,
+	}
+	txErr := mysql.RunDBTransaction(c, func() error {
+		if err := dao.ArticleSeriesDao.InsertSeries(c, &series); err != nil {
+			if strings.Contains(err.Error(), "Duplicate entry") {
+				return cerr.New(cerr.ERROR_ARTICLE_SERIES_EXIST)
+			}
+			l.Error("Failed to insert series", zap.Error(err))
+			return err
+		}
+		if err := s.replaceSeriesArticles(c, series.ID, seriesDto.ArticleIDs); err != nil {
+			return err
+		}
+		return AuditLogService.Record(c, utils.AUDIT_ACTION_CREATE, utils.AUDIT_ENTITY_SERIES, series.ID, nil,
+			seriesAuditData{ArticleSeries: series, ArticleIDs: seriesDto.ArticleIDs})
+	})
+	if txErr != nil {
+		l.Error("Failed to create series", zap.Error(txErr))
+		return txErr
+	}
+	return nil
+}
+
+// UpdateSeries 更新系列信息，文章列表按传入的顺序整体替换
+func (s *articleSeriesService) UpdateSeries(c *gin.Context, updateDto dto.ArticleSeriesUpdateDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		series, err := dao.ArticleSeriesDao.QuerySeriesByID(c, updateDto.ID)
+		if err != nil {
+			l.Error("Failed to query series by id", zap.Error(err), zap.Int64("id", updateDto.ID))
+			return err
+		}
+		if series == nil {
+			return cerr.New(cerr.ERROR_ARTICLE_SERIES_NOT_EXIST)
+		}
+		before, err := s.seriesAuditData(c, *series)
+		if err != nil {
+			return err
+		}
+
+		after := *series
+		after.Name = updateDto.Name
+		after.Description = updateDto.Description
+		if err := dao.ArticleSeriesDao.UpdateSeries(c, &after); err != nil {
+			if strings.Contains(err.Error(), "Duplicate entry") {
+				return cerr.New(cerr.ERROR_ARTICLE_SERIES_EXIST)
+			}
+			l.Error("Failed to update series", zap.Error(err), zap.Int64("id", updateDto.ID))
+			return err
+		}
+		if err := dao.ArticleSeriesDao.DeleteRelationsBySeriesIDs(c, []int64{series.ID}); err != nil {
+			l.Error("Failed to delete series relations", zap.Error(err), zap.Int64("id", series.ID))
+			return err
+		}
+		if err := s.replaceSeriesArticles(c, series.ID, updateDto.ArticleIDs); err != nil {
+			return err
+		}
+		return AuditLogService.Record(c, utils.AUDIT_ACTION_UPDATE, utils.AUDIT_ENTITY_SERIES, series.ID, before,
+			seriesAuditData{ArticleSeries: after, ArticleIDs: updateDto.ArticleIDs})
+	})
+	if txErr != nil {
+		l.Error("Failed to update series", zap.Error(txErr))
+		return txErr
+	}
+	return nil
+}
+
+// DeleteSeriesList 删除系列 - 批量，系列中的文章不会被删除
+func (s *articleSeriesService) DeleteSeriesList(c *gin.Context, deleteDto dto.ArticleSeriesDeleteDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		seriesList, err := dao.ArticleSeriesDao.ListSeriesByIDs(c, deleteDto.IDs)
+		if err != nil {
+			l.Error("Failed to list series by ids", zap.Error(err), zap.Int64s("ids", deleteDto.IDs))
+			return err
+		}
+		if len(seriesList) == 0 {
+			return cerr.New(cerr.ERROR_ARTICLE_SERIES_NOT_EXIST)
+		}
+		befores := make([]seriesAuditData, 0, len(seriesList))
+		ids := make([]int64, 0, len(seriesList))
+		for i := range seriesList {
+			before, err := s.seriesAuditData(c, seriesList[i])
+			if err != nil {
+				return err
+			}
+			befores = append(befores, before)
+			ids = append(ids, seriesList[i].ID)
+		}
+		if err := dao.ArticleSeriesDao.DeleteRelationsBySeriesIDs(c, ids); err != nil {
+			l.Error("Failed to delete series relations", zap.Error(err), zap.Int64s("ids", ids))
+			return err
+		}
+		if err := dao.ArticleSeriesDao.DeleteSeriesByIDs(c, ids); err != nil {
+			l.Error("Failed to delete series", zap.Error(err), zap.Int64s("ids", ids))
+			return err
+		}
+		for i := range befores {
+			if err := AuditLogService.Record(c, utils.AUDIT_ACTION_DELETE, utils.AUDIT_ENTITY_SERIES, befores[i].ID, befores[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to delete series list", zap.Error(txErr))
+		return txErr
+	}
+	return nil
+}
+
+// FillSeriesNav 填充文章所属系列的目录及上一篇、下一篇，失败时只记录日志，不影响文章详情返回
+func (s *articleSeriesService) FillSeriesNav(c *gin.Context, detail *vo.ArticleDetailVo, onlyPublished bool) {
+	l := logger.FromContext(c.Request.Context())
+	series, err := dao.ArticleSeriesDao.QuerySeriesByArticleID(c, detail.ID)
+	if err != nil {
+		l.Error("Failed to query series by article id", zap.Error(err), zap.Int64("article id", detail.ID))
+		return
+	}
+	if series == nil {
+		return
+	}
+	articles, err := dao.ArticleSeriesDao.ListSeriesArticles(c, series.ID, onlyPublished)
+	if err != nil {
+		l.Error("Failed to list series articles", zap.Error(err), zap.Int64("id", series.ID))
+		return
+	}
+	items := toArticleSeriesItemVoList(articles)
+	nav := &vo.ArticleSeriesNavVo{
+		ID:       series.ID,
+		Name:     series.Name,
+		Articles: items,
+	}
+	for i := range items {
+		if items[i].ID != detail.ID {
+			continue
+		}
+		if i > 0 {
+			nav.Prev = &items[i-1]
+		}
+		if i < len(items)-1 {
+			nav.Next = &items[i+1]
+		}
+		break
+	}
+	detail.Series = nav
+}
+
+// replaceSeriesArticles 校验文章并按顺序写入系列关系，需在事务中执行且系列原有关系已删除
+func (s *articleSeriesService) replaceSeriesArticles(c *gin.Context, seriesID int64, articleIDs []int64) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	l := logger.FromContext(c.Request.Context())
+	articles, err := dao.ArticleDao.ListArticleByIDs(c, articleIDs)
+	if err != nil {
+		l.Error("Failed to list article by ids", zap.Error(err), zap.Int64s("ids", articleIDs))
+		return err
+	}
+	if len(articles) != len(articleIDs) {
+		return cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
+	}
+	relations, err := dao.ArticleSeriesDao.ListRelationsByArticleIDs(c, articleIDs)
+	if err != nil {
+		l.Error("Failed to list series relations by article ids", zap.Error(err), zap.Int64s("ids", articleIDs))
+		return err
+	}
+	if len(relations) > 0 {
+		return cerr.New(cerr.ERROR_ARTICLE_SERIES_ARTICLE_CONFLICT)
+	}
+
+	newRelations := make([]*model.ArticleSeriesRelation, 0, len(articleIDs))
+	for i, id := range articleIDs {
+		newRelations = append(newRelations, &model.ArticleSeriesRelation{
+			SeriesID:  seriesID,
+			ArticleID: id,
+			SortOrder: i,
+		})
+	}
+	if err := dao.ArticleSeriesDao.InsertRelations(c, newRelations); err != nil {
+		l.Error("Failed to insert series relations", zap.Error(err), zap.Int64("series id", seriesID))
+		return err
+	}
+	return nil
+}
+
+func (s *articleSeriesService) seriesAuditData(c *gin.Context, series model.ArticleSeries) (seriesAuditData, error) {
+	relations, err := dao.ArticleSeriesDao.ListRelationsBySeriesID(c, series.ID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to list series relations", zap.Error(err), zap.Int64("id", series.ID))
+		return seriesAuditData{}, err
+	}
+	ids := make([]int64, 0, len(relations))
+	for i := range relations {
+		ids = append(ids, relations[i].ArticleID)
+	}
+	return seriesAuditData{ArticleSeries: series, ArticleIDs: ids}, nil
+}
+
+func toArticleSeriesVo(series model.ArticleSeries, articleCount int) vo.ArticleSeriesVo {
+	return vo.ArticleSeriesVo{
+		ID:           series.ID,
+		Name:         series.Name,
+		Description:  series.Description,
+		ArticleCount: articleCount,
+		CreatedTime:  series.CreatedTime.UnixMilli(),
+		UpdatedTime:  series.UpdatedTime.UnixMilli(),
+	}
+}
+
+func toArticleSeriesItemVoList(articles []model.ArticleSeriesArticle) []vo.ArticleSeriesItemVo {
+	items := make([]vo.ArticleSeriesItemVo, 0, len(articles))
+	for i := range articles {
+		items = append(items, vo.ArticleSeriesItemVo{
+			ID:    articles[i].ArticleID,
+			Title: articles[i].Title,
+			Slug:  articles[i].Slug,
+			Order: i + 1,
+		})
+	}
+	return items
+}
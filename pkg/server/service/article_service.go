@@ -14,6 +14,7 @@ import (
 	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
 	"github.com/narcissus1949/narcissus-blog/internal/logger"
 	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
 	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	"github.com/narcissus1949/narcissus-blog/pkg/dto"
 	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
@@ -27,7 +28,16 @@ var ArticleService = new(articleService)
 type articleService struct {
 }
 
+// ListArticleAdmin 管理端分页查询文章，作者只能查看自己的文章
 func (s *articleService) ListArticleAdmin(ctx *gin.Context, articleListRequest dto.ArticleListDto) (*vo.ArticleListVo, error) {
+	if !rbac.HasAnyRole(rbac.RolesFromContext(ctx), rbac.ROLE_ADMIN, rbac.ROLE_EDITOR) {
+		articleListRequest.OwnerID = ctx.GetInt(utils.CONTEXT_USER_ID)
+	}
+	return s.ListArticle(ctx, articleListRequest)
+}
+
+// ListArticle 分页查询文章
+func (s *articleService) ListArticle(ctx *gin.Context, articleListRequest dto.ArticleListDto) (*vo.ArticleListVo, error) {
 	l := logger.FromContext(ctx.Request.Context())
 	var articleListResponse vo.ArticleListVo
 	var articleList []model.ArticleDetail
@@ -55,6 +65,13 @@ func (s *articleService) ListArticleAdmin(ctx *gin.Context, articleListRequest d
 		return nil, txErr
 	}
 
+	// 获取文章评论数
+	articleIDs := make([]int64, 0, len(articleList))
+	for i := range articleList {
+		articleIDs = append(articleIDs, articleList[i].ID)
+	}
+	commentCountMap := CommentService.CountApprovedComment(ctx, articleIDs)
+
 	// 响应参数封装
 	list := make([]vo.ArticleDetailVo, 0, len(articleList))
 	for i := range articleList {
@@ -74,17 +91,22 @@ func (s *articleService) ListArticleAdmin(ctx *gin.Context, articleListRequest d
 			ArticleMeta: vo.ArticleMeta{
 				ID:                  articleList[i].ID,
 				Title:               articleList[i].Title,
+				Slug:                articleList[i].Slug,
 				Summary:             articleList[i].Summary,
 				CategoryID:          articleList[i].CategoryID,
 				Type:                articleList[i].Type,
 				Author:              articleList[i].Author,
+				OwnerID:             articleList[i].OwnerID,
 				AllowComment:        articleList[i].AllowComment,
 				Views:               articleList[i].Views + viewsCache,
+				CommentCount:        commentCountMap[articleList[i].ID],
 				Weight:              articleList[i].Weight,
 				IsSticky:            articleList[i].IsSticky,
 				IsOriginal:          articleList[i].IsOriginal,
 				OriginalArticleLink: articleList[i].OriginalArticleLink,
 				Status:              articleList[i].Status,
+				PublishAt:           timeToUnixMilli(articleList[i].PublishAt),
+				Version:             articleList[i].Version,
 				CreatedTime:         articleList[i].CreatedTime.UnixMilli(),
 				UpdatedTime:         articleList[i].UpdatedTime.UnixMilli(),
 			},
@@ -112,19 +134,29 @@ func (s *articleService) ListArticleAdmin(ctx *gin.Context, articleListRequest d
 func (s *articleService) CreateArticle(c *gin.Context, articleDto dto.ArticleDto) error {
 	l := logger.FromContext(c.Request.Context())
 
+	articleSlug, err := ArticleSlugService.ResolveSlug(c, 0, articleDto.Slug, articleDto.Title)
+	if err != nil {
+		l.Error("Failed to resolve article slug", zap.Error(err), zap.String("slug", articleDto.Slug))
+		return err
+	}
+
 	now := time.Now()
+	status, publishAt := resolvePublishStatus(articleDto, now)
 	articleModel := &model.Article{
 		Title:               articleDto.Title,
+		Slug:                articleSlug,
 		Type:                articleDto.Type,
 		Summary:             articleDto.Summary,
 		Author:              articleDto.Author,
+		OwnerID:             c.GetInt(utils.CONTEXT_USER_ID),
 		AllowComment:        articleDto.AllowComment,
 		Views:               0,
 		Weight:              articleDto.Weight,
 		IsSticky:            articleDto.IsSticky,
 		IsOriginal:          articleDto.IsOriginal,
 		OriginalArticleLink: articleDto.OriginalArticleLink,
-		Status:              articleDto.Status,
+		Status:              status,
+		PublishAt:           publishAt,
 		CreatedTime:         now,
 		UpdatedTime:         now,
 	}
@@ -190,13 +222,25 @@ func (s *articleService) CreateArticle(c *gin.Context, articleDto dto.ArticleDto
 				return err
 			}
 		}
-		return nil
+
+		// 记录文章版本
+		if err := ArticleRevisionService.SaveRevision(c, articleModel.ID); err != nil {
+			l.Error("Failed to save article revision", zap.Error(err), zap.Int64("articleID", articleModel.ID))
+			return err
+		}
+		// 文章已保存，删除当前用户的新建文章草稿
+		if err := dao.ArticleDraftDao.DeleteDraft(c, 0, c.GetInt(utils.CONTEXT_USER_ID)); err != nil {
+			l.Error("Failed to delete article draft", zap.Error(err))
+			return err
+		}
+		return AuditLogService.Record(c, utils.AUDIT_ACTION_CREATE, utils.AUDIT_ENTITY_ARTICLE, articleModel.ID, nil, articleModel)
 	})
 
 	if txErr != nil {
 		l.Error("Failed to execute transaction", zap.Error(txErr))
 		return txErr
 	}
+	s.afterArticleCommit(c, []int64{articleModel.ID})
 
 	return nil
 }
@@ -208,126 +252,206 @@ func (s *articleService) UpdateArticle(c *gin.Context, articleDto dto.ArticleDto
 	}
 
 	txErr := mysql.RunDBTransaction(c, func() error {
-		// 0.检查文章存在
-		articleDetail, err := s.GetArticleDetail(c, *articleDto.ID)
+		return s.updateArticle(c, articleDto)
+	})
+	if txErr != nil {
+		l.Error("Failed to update article", zap.Error(txErr))
+		return txErr
+	}
+	s.afterArticleCommit(c, []int64{*articleDto.ID})
+	return nil
+}
+
+// updateArticle 更新文章并记录新版本，需在事务中执行
+func (s *articleService) updateArticle(c *gin.Context, articleDto dto.ArticleDto) error {
+	l := logger.FromContext(c.Request.Context())
+	// 0.检查文章存在
+	articleDetail, err := s.GetArticleDetail(c, *articleDto.ID)
+	if err != nil {
+		l.Error("Failed to get article detail", zap.Error(err), zap.Int64("articleID", *articleDto.ID))
+		return err
+	}
+	if articleDetail == nil {
+		return cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
+	}
+	if err := s.checkArticlePermission(c, "update", articleDetail.ID, articleDetail.OwnerID); err != nil {
+		return err
+	}
+	if articleDetail.Type != articleDto.Type {
+		return cerr.NewParamError("不支持更改文章类型")
+	}
+	beforeArticle, err := dao.ArticleDao.QueryArticleByID(c, *articleDto.ID)
+	if err != nil {
+		l.Error("Failed to query article", zap.Error(err), zap.Int64("articleID", *articleDto.ID))
+		return err
+	}
+	// 编辑所基于的版本已过期，拒绝覆盖其他会话的修改
+	if articleDto.Version == nil || *articleDto.Version != articleDetail.Version {
+		l.Warn("Article version conflict", zap.Int64("articleID", *articleDto.ID), zap.Any("version", articleDto.Version), zap.Int("currentVersion", articleDetail.Version))
+		return cerr.New(cerr.ERROR_ARTICLE_VERSION_CONFLICT)
+	}
+	// 1.更新文章元数据
+	// 未指定别名时保持原别名不变，避免修改标题导致链接变化
+	articleSlug := articleDetail.Slug
+	if articleDto.Slug != "" || articleSlug == "" {
+		articleSlug, err = ArticleSlugService.ResolveSlug(c, *articleDto.ID, articleDto.Slug, articleDto.Title)
 		if err != nil {
-			l.Error("Failed to get article detail", zap.Error(err), zap.Int64("articleID", *articleDto.ID))
+			l.Error("Failed to resolve article slug", zap.Error(err), zap.String("slug", articleDto.Slug))
 			return err
 		}
-		if articleDetail == nil {
-			return cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
-		}
-		if articleDetail.Type != articleDto.Type {
-			return cerr.NewParamError("不支持更改文章类型")
-		}
-		// 1.更新文章元数据
-		now := time.Now()
-		articleModel := &model.Article{
-			ID:      *articleDto.ID,
-			Title:   articleDto.Title,
-			Summary: articleDto.Summary,
-			Type:    articleDetail.Type, // 不支持更改文章类型
-			// CategoryID:          articleDto.CategoryID,
-			Author:              articleDto.Author,
-			AllowComment:        articleDto.AllowComment,
-			Weight:              articleDto.Weight,
-			IsSticky:            articleDto.IsSticky,
-			IsOriginal:          articleDto.IsOriginal,
-			OriginalArticleLink: articleDto.OriginalArticleLink,
-			Status:              articleDto.Status,
-			CreatedTime:         time.Unix(articleDetail.CreatedTime, 0),
-			UpdatedTime:         now,
-		}
-		// 处理文章分类
-		if articleModel.Type != utils.ARTICLE_TYPE_ABOUT {
-			if articleDetail.CategoryName == articleDto.Category {
-				articleModel.CategoryID = articleDetail.CategoryID
-			} else if len(strings.TrimSpace(articleDto.Category)) > 0 {
-				categoryId, err := CategoryService.GetCategoryIDByName(c, articleDto.Category)
-				if err != nil {
-					l.Error("Failed to get category id", zap.Error(err), zap.String("category", articleDto.Category))
-					return err
-				}
-				articleModel.CategoryID = &categoryId
+	}
+	now := time.Now()
+	status, publishAt := resolvePublishStatus(articleDto, now)
+	articleModel := &model.Article{
+		ID:      *articleDto.ID,
+		Title:   articleDto.Title,
+		Slug:    articleSlug,
+		Summary: articleDto.Summary,
+		Type:    articleDetail.Type, // 不支持更改文章类型
+		// CategoryID:          articleDto.CategoryID,
+		Author:              articleDto.Author,
+		AllowComment:        articleDto.AllowComment,
+		Weight:              articleDto.Weight,
+		IsSticky:            articleDto.IsSticky,
+		IsOriginal:          articleDto.IsOriginal,
+		OriginalArticleLink: articleDto.OriginalArticleLink,
+		Status:              status,
+		PublishAt:           publishAt,
+		CreatedTime:         time.Unix(articleDetail.CreatedTime, 0),
+		UpdatedTime:         now,
+	}
+	// 处理文章分类
+	if articleModel.Type != utils.ARTICLE_TYPE_ABOUT {
+		if articleDetail.CategoryName == articleDto.Category {
+			articleModel.CategoryID = articleDetail.CategoryID
+		} else if len(strings.TrimSpace(articleDto.Category)) > 0 {
+			categoryId, err := CategoryService.GetCategoryIDByName(c, articleDto.Category)
+			if err != nil {
+				l.Error("Failed to get category id", zap.Error(err), zap.String("category", articleDto.Category))
+				return err
 			}
+			articleModel.CategoryID = &categoryId
 		}
+	}
 
-		// 更新文章
-		if _, updateArticleErr := dao.ArticleDao.UpdateArticle(c, articleModel); updateArticleErr != nil {
-			l.Error("Failed to update article", zap.Error(updateArticleErr), zap.Int64("articleID", articleModel.ID))
-			return updateArticleErr
+	// 更新文章
+	rowsAffected, updateArticleErr := dao.ArticleDao.UpdateArticle(c, articleModel, articleDetail.Version)
+	if updateArticleErr != nil {
+		l.Error("Failed to update article", zap.Error(updateArticleErr), zap.Int64("articleID", articleModel.ID))
+		return updateArticleErr
+	}
+	// 查询与更新之间文章已被其他会话修改
+	if rowsAffected == 0 {
+		l.Warn("Article version conflict", zap.Int64("articleID", articleModel.ID), zap.Int("version", articleDetail.Version))
+		return cerr.New(cerr.ERROR_ARTICLE_VERSION_CONFLICT)
+	}
+	if err := ArticleSlugService.ChangeSlug(c, articleModel.ID, articleDetail.Slug, articleSlug); err != nil {
+		return err
+	}
+	// 2.更新文章内容
+	if articleDetail.Content != articleDto.Content {
+		articleContentModel := &model.ArticleContent{
+			ArticleID: articleModel.ID,
+			Content:   articleDto.Content,
 		}
-		// 2.更新文章内容
-		if articleDetail.Content != articleDto.Content {
-			articleContentModel := &model.ArticleContent{
+		if _, updateContentErr := dao.ArticleContentDao.UpdateContentByArticleID(c, articleContentModel); updateContentErr != nil {
+			l.Error("Failed to update article content", zap.Error(updateContentErr), zap.Int64("articleID", articleModel.ID))
+			return updateContentErr
+		}
+	}
+	// 3.更新文章标签关联
+	addTags, deleteTags := getNewTagRelation(articleDto.Tags, articleDetail.TagNameList)
+	if len(deleteTags) > 0 {
+		// 获取新增标签id
+		tagIdList, listTagErr := dao.TagDao.ListTagIdByNameArr(c, deleteTags)
+		if listTagErr != nil {
+			l.Error("Failed to list tag id by name arr", zap.Error(listTagErr), zap.Strings("tags", deleteTags))
+			return listTagErr
+		}
+		var relations []*model.ArticleTagRelation
+		for _, tagID := range tagIdList {
+			relation := &model.ArticleTagRelation{
 				ArticleID: articleModel.ID,
-				Content:   articleDto.Content,
-			}
-			if _, updateContentErr := dao.ArticleContentDao.UpdateContentByArticleID(c, articleContentModel); updateContentErr != nil {
-				l.Error("Failed to update article content", zap.Error(updateContentErr), zap.Int64("articleID", articleModel.ID))
-				return updateContentErr
+				TagID:     tagID,
 			}
+			relations = append(relations, relation)
 		}
-		// 3.更新文章标签关联
-		addTags, deleteTags := getNewTagRelation(articleDto.Tags, articleDetail.TagNameList)
-		if len(deleteTags) > 0 {
-			// 获取新增标签id
-			tagIdList, listTagErr := dao.TagDao.ListTagIdByNameArr(c, deleteTags)
-			if listTagErr != nil {
-				l.Error("Failed to list tag id by name arr", zap.Error(listTagErr), zap.Strings("tags", deleteTags))
-				return listTagErr
-			}
-			var relations []*model.ArticleTagRelation
-			for _, tagID := range tagIdList {
-				relation := &model.ArticleTagRelation{
-					ArticleID: articleModel.ID,
-					TagID:     tagID,
-				}
-				relations = append(relations, relation)
-			}
-			// 删除旧的标签关联
-			if err := dao.ArticleTagRelationDao.DeleteArticleTagRelations(c, relations); err != nil {
-				l.Error("Failed to delete article tag relation", zap.Error(err), zap.Int64("articleID", articleModel.ID))
-				return err
-			}
+		// 删除旧的标签关联
+		if err := dao.ArticleTagRelationDao.DeleteArticleTagRelations(c, relations); err != nil {
+			l.Error("Failed to delete article tag relation", zap.Error(err), zap.Int64("articleID", articleModel.ID))
+			return err
 		}
-		if len(addTags) > 0 {
-			// 获取新增标签id
-			tagIdList, listTagErr := dao.TagDao.ListTagIdByNameArr(c, addTags)
-			if listTagErr != nil {
-				l.Error("Failed to list tag id by name arr", zap.Error(listTagErr), zap.Strings("tags", addTags))
-				return listTagErr
-			}
-			var relations []*model.ArticleTagRelation
-			for _, tagID := range tagIdList {
-				relation := &model.ArticleTagRelation{
-					ArticleID: articleModel.ID,
-					TagID:     tagID,
-				}
-				relations = append(relations, relation)
-			}
-			// 插入文章-标签关系
-			if err := dao.ArticleTagRelationDao.InsertArticleTagRelations(c, relations); err != nil {
-				l.Error("Failed to insert article tag relation", zap.Error(err), zap.Int64("articleID", articleModel.ID))
-				return err
+	}
+	if len(addTags) > 0 {
+		// 获取新增标签id
+		tagIdList, listTagErr := dao.TagDao.ListTagIdByNameArr(c, addTags)
+		if listTagErr != nil {
+			l.Error("Failed to list tag id by name arr", zap.Error(listTagErr), zap.Strings("tags", addTags))
+			return listTagErr
+		}
+		var relations []*model.ArticleTagRelation
+		for _, tagID := range tagIdList {
+			relation := &model.ArticleTagRelation{
+				ArticleID: articleModel.ID,
+				TagID:     tagID,
 			}
+			relations = append(relations, relation)
+		}
+		// 插入文章-标签关系
+		if err := dao.ArticleTagRelationDao.InsertArticleTagRelations(c, relations); err != nil {
+			l.Error("Failed to insert article tag relation", zap.Error(err), zap.Int64("articleID", articleModel.ID))
+			return err
 		}
-		return nil
-	})
-	if txErr != nil {
-		l.Error("Failed to update article", zap.Error(txErr))
-		return txErr
 	}
-	return nil
+	// 4.记录文章版本
+	if err := ArticleRevisionService.SaveRevision(c, articleModel.ID); err != nil {
+		l.Error("Failed to save article revision", zap.Error(err), zap.Int64("articleID", articleModel.ID))
+		return err
+	}
+	// 5.文章已保存，删除当前用户的草稿
+	if err := dao.ArticleDraftDao.DeleteDraft(c, articleModel.ID, c.GetInt(utils.CONTEXT_USER_ID)); err != nil {
+		l.Error("Failed to delete article draft", zap.Error(err), zap.Int64("articleID", articleModel.ID))
+		return err
+	}
+	// 6.记录审计日志
+	afterArticle, err := dao.ArticleDao.QueryArticleByID(c, articleModel.ID)
+	if err != nil {
+		l.Error("Failed to query article", zap.Error(err), zap.Int64("articleID", articleModel.ID))
+		return err
+	}
+	return AuditLogService.Record(c, utils.AUDIT_ACTION_UPDATE, utils.AUDIT_ENTITY_ARTICLE, articleModel.ID, beforeArticle, afterArticle)
 }
 
+// GetArticleDetail 查询文章详情，包含未到定时发布时间的文章，作者只能查看自己的文章
 func (s *articleService) GetArticleDetail(c *gin.Context, id int64) (*vo.ArticleDetailVo, error) {
+	detail, err := s.getArticleDetail(c, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkArticlePermission(c, "view", detail.ID, detail.OwnerID); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// GetPublishedArticleDetail 查询文章详情，未到定时发布时间的文章视为不存在
+func (s *articleService) GetPublishedArticleDetail(c *gin.Context, id int64) (*vo.ArticleDetailVo, error) {
+	return s.getArticleDetail(c, id, true)
+}
+
+func (s *articleService) getArticleDetail(c *gin.Context, id int64, onlyPublished bool) (*vo.ArticleDetailVo, error) {
 	l := logger.FromContext(c.Request.Context())
 	if id <= 0 {
 		return nil, cerr.NewParamError("id无效")
 	}
 	// 查询文章
-	articleDetail, err := dao.ArticleDao.QueryArticleDetail(c, id)
+	var articleDetail *model.ArticleDetail
+	var err error
+	if onlyPublished {
+		articleDetail, err = dao.ArticleDao.QueryPublishedArticleDetail(c, id)
+	} else {
+		articleDetail, err = dao.ArticleDao.QueryArticleDetail(c, id)
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			l.Error("Article not exist", zap.Int64("article id", id))
@@ -356,16 +480,21 @@ func (s *articleService) GetArticleDetail(c *gin.Context, id int64) (*vo.Article
 		ArticleMeta: vo.ArticleMeta{
 			ID:                  articleDetail.ID,
 			Title:               articleDetail.Title,
+			Slug:                articleDetail.Slug,
 			Summary:             articleDetail.Summary,
 			CategoryID:          articleDetail.CategoryID,
 			Author:              articleDetail.Author,
+			OwnerID:             articleDetail.OwnerID,
 			AllowComment:        articleDetail.AllowComment,
 			Views:               articleDetail.Views,
+			CommentCount:        CommentService.CountApprovedComment(c, []int64{id})[id],
 			Weight:              articleDetail.Weight,
 			IsSticky:            articleDetail.IsSticky,
 			IsOriginal:          articleDetail.IsOriginal,
 			OriginalArticleLink: articleDetail.OriginalArticleLink,
 			Status:              articleDetail.Status,
+			PublishAt:           timeToUnixMilli(articleDetail.PublishAt),
+			Version:             articleDetail.Version,
 			CreatedTime:         articleDetail.CreatedTime.UnixMilli(),
 			UpdatedTime:         articleDetail.UpdatedTime.UnixMilli(),
 		},
@@ -377,36 +506,81 @@ func (s *articleService) GetArticleDetail(c *gin.Context, id int64) (*vo.Article
 	return &resp, nil
 }
 
+// DeleteArticleList 将文章移入回收站，内容、标签、评论等关联数据保留，永久删除时一并清理
 func (s *articleService) DeleteArticleList(c *gin.Context, deleteDto dto.ArticleDeleteDto) error {
 	l := logger.FromContext(c.Request.Context())
 	txErr := mysql.RunDBTransaction(c, func() error {
-		// 删除文章
-		if err := dao.ArticleDao.DeleteArticleByIDs(c, deleteDto.IDs); err != nil {
-			l.Error("Failed to delete article by ids", zap.Error(err), zap.Int64s("ids", deleteDto.IDs))
+		// 检查操作权限
+		articles, err := dao.ArticleDao.ListArticleByIDs(c, deleteDto.IDs)
+		if err != nil {
+			l.Error("Failed to list article by ids", zap.Error(err), zap.Int64s("ids", deleteDto.IDs))
 			return err
 		}
-		// 删除文章内容
-		if err := dao.ArticleContentDao.DeleteContentByArticleIDs(c, deleteDto.IDs); err != nil {
-			l.Error("Failed to delete article content by ids", zap.Error(err), zap.Int64s("ids", deleteDto.IDs))
-			return err
+		for i := range articles {
+			if err := s.checkArticlePermission(c, "delete", articles[i].ID, articles[i].OwnerID); err != nil {
+				return err
+			}
 		}
-		// 删除文章标签关联
-		if err := dao.ArticleTagRelationDao.DeleteArticleTagRelationsByArticleIDs(c, deleteDto.IDs); err != nil {
-			l.Error("Failed to delete article tag relation by ids", zap.Error(err), zap.Int64s("ids", deleteDto.IDs))
+		if len(articles) == 0 {
+			return nil
+		}
+		// 移入回收站
+		now := time.Now()
+		if err := dao.ArticleDao.SoftDeleteArticleByIDs(c, deleteDto.IDs, now); err != nil {
+			l.Error("Failed to soft delete article by ids", zap.Error(err), zap.Int64s("ids", deleteDto.IDs))
 			return err
 		}
+		for i := range articles {
+			after := articles[i]
+			after.DeletedTime = &now
+			if err := AuditLogService.Record(c, utils.AUDIT_ACTION_DELETE, utils.AUDIT_ENTITY_ARTICLE, articles[i].ID, articles[i], after); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 	if txErr != nil {
 		l.Error("Failed to delete article list", zap.Error(txErr))
 		return txErr
 	}
+	s.afterArticleCommit(c, deleteDto.IDs)
 	return nil
 }
 
+// checkArticlePermission 管理员和编辑可操作所有文章，作者只能操作自己所属的文章
+func (s *articleService) checkArticlePermission(c *gin.Context, action string, articleID int64, ownerID int) error {
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	roles := rbac.RolesFromContext(c)
+	if rbac.HasAnyRole(roles, rbac.ROLE_ADMIN, rbac.ROLE_EDITOR) {
+		return nil
+	}
+	if ownerID > 0 && ownerID == userID && rbac.HasAnyRole(roles, rbac.ROLE_AUTHOR) {
+		return nil
+	}
+	// 拒绝记录用于审计
+	logger.FromContext(c.Request.Context()).Warn("Article permission denied",
+		zap.String("action", action),
+		zap.Int64("article id", articleID),
+		zap.Int("owner id", ownerID),
+		zap.Int("user id", userID),
+		zap.Int32s("roles", roles))
+	return cerr.New(cerr.ERROR_ARTICLE_PERMISSION_DENIED)
+}
+
+// afterArticleCommit 文章保存或删除的事务提交后同步更新搜索索引、清除sitemap缓存，失败只记录日志，不影响已提交的修改
+func (s *articleService) afterArticleCommit(c *gin.Context, ids []int64) {
+	l := logger.FromContext(c.Request.Context())
+	if err := ArticleSearchService.IndexArticles(c, ids); err != nil {
+		l.Error("Failed to update article search index", zap.Error(err), zap.Int64s("ids", ids))
+	}
+	if err := SitemapService.InvalidateCache(c); err != nil {
+		l.Error("Failed to invalidate sitemap cache", zap.Error(err))
+	}
+}
+
 func (s *articleService) AddPageView(c *gin.Context, pageViewDto dto.ArticlePageViewDto) error {
 	l := logger.FromContext(c.Request.Context())
-	_, err := s.GetArticleDetail(c, pageViewDto.ArticleID)
+	_, err := s.GetPublishedArticleDetail(c, pageViewDto.ArticleID)
 	if err != nil {
 		l.Error("Failed to get article detail", zap.Error(err), zap.Int64("article id", pageViewDto.ArticleID))
 		return err
@@ -450,6 +624,22 @@ func (s *articleService) AddPageView(c *gin.Context, pageViewDto dto.ArticlePage
 	return nil
 }
 
+// resolvePublishStatus 根据定时发布时间计算文章状态，发布时间晚于当前时间时先保存为offline
+func resolvePublishStatus(articleDto dto.ArticleDto, now time.Time) (uint8, *time.Time) {
+	if articleDto.PublishAt == nil || *articleDto.PublishAt <= now.UnixMilli() {
+		return articleDto.Status, nil
+	}
+	publishAt := time.UnixMilli(*articleDto.PublishAt)
+	return utils.ARTICLE_STATUS_OFFLINE, &publishAt
+}
+
+func timeToUnixMilli(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
 // 根据新标签列表和旧标签列表，找出需要删除的标签列表和需要新增的标签列表
 func getNewTagRelation(newTagList, oldTagList []string) ([]string, []string) {
 	// 1. 存入map，方便查找
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/slug"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var ArticleSlugService = new(articleSlugService)
+
+type articleSlugService struct {
+}
+
+// ResolveSlug 确定文章使用的别名。指定了别名时校验是否被其他文章占用(包括其他文章的历史别名)，
+// 否则由标题生成，重复时追加序号。新建文章的articleID为0
+func (s *articleSlugService) ResolveSlug(c *gin.Context, articleID int64, requested, title string) (string, error) {
+	if requested != "" {
+		ownerID, err := s.getSlugOwner(c, requested)
+		if err != nil {
+			return "", err
+		}
+		if ownerID != 0 && ownerID != articleID {
+			return "", cerr.New(cerr.ERROR_ARTICLE_SLUG_EXIST)
+		}
+		return requested, nil
+	}
+
+	base := slug.Generate(title)
+	candidate := base
+	for n := 2; ; n++ {
+		ownerID, err := s.getSlugOwner(c, candidate)
+		if err != nil {
+			return "", err
+		}
+		if ownerID == 0 || ownerID == articleID {
+			return candidate, nil
+		}
+		candidate = slug.WithSuffix(base, n)
+	}
+}
+
+// ChangeSlug 文章别名变化时记录旧别名，需在保存文章的事务中执行
+func (s *articleSlugService) ChangeSlug(c *gin.Context, articleID int64, oldSlug, newSlug string) error {
+	l := logger.FromContext(c.Request.Context())
+	if oldSlug == newSlug {
+		return nil
+	}
+	// 重新使用自己的历史别名时删除对应的历史记录
+	if err := dao.ArticleSlugHistoryDao.DeleteHistoryBySlug(c, newSlug); err != nil {
+		l.Error("Failed to delete article slug history", zap.Error(err), zap.String("slug", newSlug))
+		return err
+	}
+	if oldSlug == "" {
+		return nil
+	}
+	if err := dao.ArticleSlugHistoryDao.InsertHistory(c, &model.ArticleSlugHistory{
+		ArticleID: articleID,
+		Slug:      oldSlug,
+	}); err != nil {
+		l.Error("Failed to insert article slug history", zap.Error(err), zap.Int64("article id", articleID), zap.String("slug", oldSlug))
+		return err
+	}
+	return nil
+}
+
+// GetArticleBySlug 根据别名查询已发布文章详情。别名为历史别名且文章已发布时返回nil和文章当前别名，由调用方重定向
+func (s *articleSlugService) GetArticleBySlug(c *gin.Context, articleSlug string) (*vo.ArticleDetailVo, string, error) {
+	l := logger.FromContext(c.Request.Context())
+	articleID, err := dao.ArticleDao.QueryArticleIDBySlug(c, articleSlug)
+	if err != nil {
+		l.Error("Failed to query article id by slug", zap.Error(err), zap.String("slug", articleSlug))
+		return nil, "", err
+	}
+	if articleID != 0 {
+		detail, err := ArticleService.GetPublishedArticleDetail(c, articleID)
+		return detail, "", err
+	}
+
+	historyArticleID, err := dao.ArticleSlugHistoryDao.QueryArticleIDBySlug(c, articleSlug)
+	if err != nil {
+		l.Error("Failed to query article slug history", zap.Error(err), zap.String("slug", articleSlug))
+		return nil, "", err
+	}
+	if historyArticleID == 0 {
+		return nil, "", cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
+	}
+	article, err := dao.ArticleDao.QueryArticleByID(c, historyArticleID)
+	if err != nil {
+		l.Error("Failed to query article", zap.Error(err), zap.Int64("article id", historyArticleID))
+		return nil, "", err
+	}
+	// 未发布的文章不重定向，避免通过历史别名泄露当前别名
+	if article == nil || article.Slug == "" || article.Status != utils.ARTICLE_STATUS_ONLINE ||
+		(article.PublishAt != nil && article.PublishAt.After(time.Now())) {
+		return nil, "", cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
+	}
+	return nil, article.Slug, nil
+}
+
+// getSlugOwner 查询当前或曾经使用该别名的文章ID，未被使用时返回0
+func (s *articleSlugService) getSlugOwner(c *gin.Context, articleSlug string) (int64, error) {
+	l := logger.FromContext(c.Request.Context())
+	articleID, err := dao.ArticleDao.QueryArticleIDBySlug(c, articleSlug)
+	if err != nil {
+		l.Error("Failed to query article id by slug", zap.Error(err), zap.String("slug", articleSlug))
+		return 0, err
+	}
+	if articleID != 0 {
+		return articleID, nil
+	}
+	historyArticleID, err := dao.ArticleSlugHistoryDao.QueryArticleIDBySlug(c, articleSlug)
+	if err != nil {
+		l.Error("Failed to query article slug history", zap.Error(err), zap.String("slug", articleSlug))
+		return 0, err
+	}
+	return historyArticleID, nil
+}
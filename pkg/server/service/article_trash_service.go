@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
+	"github.com/narcissus1949/narcissus-blog/internal/trash"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var ArticleTrashService = new(articleTrashService)
+
+type articleTrashService struct {
+}
+
+// ListTrash 分页查询回收站中的文章，作者只能查看自己的文章
+func (s *articleTrashService) ListTrash(c *gin.Context, listDto dto.ArticleTrashListDto) (*vo.ArticleTrashListVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	if !rbac.HasAnyRole(rbac.RolesFromContext(c), rbac.ROLE_ADMIN, rbac.ROLE_EDITOR) {
+		listDto.OwnerID = c.GetInt(utils.CONTEXT_USER_ID)
+	}
+	var articleList []model.Article
+	var total int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		var listErr error
+		articleList, listErr = dao.ArticleDao.ListDeletedArticle(c, listDto)
+		if listErr != nil {
+			l.Error("Failed to list deleted article", zap.Error(listErr))
+			return listErr
+		}
+		var countErr error
+		total, countErr = dao.ArticleDao.CountDeletedArticle(c, listDto)
+		if countErr != nil {
+			l.Error("Failed to count deleted article", zap.Error(countErr))
+			return countErr
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to run db transaction", zap.Error(txErr))
+		return nil, txErr
+	}
+
+	list := make([]vo.ArticleTrashVo, 0, len(articleList))
+	for i := range articleList {
+		var deletedTime, expireTime int64
+		if articleList[i].DeletedTime != nil {
+			deletedTime = articleList[i].DeletedTime.UnixMilli()
+			expireTime = articleList[i].DeletedTime.AddDate(0, 0, trash.Config.Retention).UnixMilli()
+		}
+		list = append(list, vo.ArticleTrashVo{
+			ID:          articleList[i].ID,
+			Title:       articleList[i].Title,
+			Slug:        articleList[i].Slug,
+			Type:        articleList[i].Type,
+			Author:      articleList[i].Author,
+			OwnerID:     articleList[i].OwnerID,
+			CreatedTime: articleList[i].CreatedTime.UnixMilli(),
+			DeletedTime: deletedTime,
+			ExpireTime:  expireTime,
+		})
+	}
+	return &vo.ArticleTrashListVo{
+		ArticleList: list,
+		Pageinate:   buildPageinate(listDto.Pageinate, total),
+	}, nil
+}
+
+// RestoreArticleList 从回收站恢复文章
+func (s *articleTrashService) RestoreArticleList(c *gin.Context, trashDto dto.ArticleTrashDto) error {
+	l := logger.FromContext(c.Request.Context())
+	var restoredIDs []int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		articles, err := s.listDeletedWithPermission(c, "restore", trashDto.IDs)
+		if err != nil {
+			return err
+		}
+		restoredIDs = getArticleIDs(articles)
+		if err := dao.ArticleDao.RestoreArticleByIDs(c, restoredIDs); err != nil {
+			l.Error("Failed to restore article by ids", zap.Error(err), zap.Int64s("ids", restoredIDs))
+			return err
+		}
+		for i := range articles {
+			after := articles[i]
+			after.DeletedTime = nil
+			if err := AuditLogService.Record(c, utils.AUDIT_ACTION_RESTORE, utils.AUDIT_ENTITY_ARTICLE, articles[i].ID, articles[i], after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to restore article list", zap.Error(txErr))
+		return txErr
+	}
+	ArticleService.afterArticleCommit(c, restoredIDs)
+	return nil
+}
+
+// PurgeArticleList 永久删除回收站中的文章及其内容、标签、版本、评论等关联数据
+func (s *articleTrashService) PurgeArticleList(c *gin.Context, trashDto dto.ArticleTrashDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		articles, err := s.listDeletedWithPermission(c, "purge", trashDto.IDs)
+		if err != nil {
+			return err
+		}
+		purgeIDs := getArticleIDs(articles)
+		if err := purgeArticles(c, purgeIDs); err != nil {
+			l.Error("Failed to purge article", zap.Error(err), zap.Int64s("ids", purgeIDs))
+			return err
+		}
+		for i := range articles {
+			if err := AuditLogService.Record(c, utils.AUDIT_ACTION_PURGE, utils.AUDIT_ENTITY_ARTICLE, articles[i].ID, articles[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to purge article list", zap.Error(txErr))
+		return txErr
+	}
+	return nil
+}
+
+// PurgeExpiredArticles 永久删除超过保留时间的回收站文章并以系统身份记录审计日志，返回删除的文章数
+func (s *articleTrashService) PurgeExpiredArticles(ctx context.Context) (int, error) {
+	var purgeIDs []int64
+	if err := mysql.RunDBTransaction2(ctx, func(ctx context.Context) error {
+		articles, err := dao.ArticleDao.ListExpiredDeletedArticle(ctx, trash.ExpireBefore(time.Now()))
+		if err != nil {
+			logger.FromContext(ctx).Error("Failed to list expired deleted article", zap.Error(err))
+			return err
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+		purgeIDs = getArticleIDs(articles)
+		if err := purgeArticles(ctx, purgeIDs); err != nil {
+			return err
+		}
+		for i := range articles {
+			if err := AuditLogService.RecordSystem(ctx, utils.AUDIT_ACTION_PURGE, utils.AUDIT_ENTITY_ARTICLE, articles[i].ID, articles[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.FromContext(ctx).Error("Failed to purge expired article", zap.Error(err), zap.Int64s("ids", purgeIDs))
+		return 0, err
+	}
+	return len(purgeIDs), nil
+}
+
+// listDeletedWithPermission 查询回收站中的文章并检查操作权限，需在事务中执行
+func (s *articleTrashService) listDeletedWithPermission(c *gin.Context, action string, ids []int64) ([]model.Article, error) {
+	articles, err := dao.ArticleDao.ListDeletedArticleByIDs(c, ids)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to list deleted article by ids", zap.Error(err), zap.Int64s("ids", ids))
+		return nil, err
+	}
+	if len(articles) == 0 {
+		return nil, cerr.New(cerr.ERROR_ARTICLE_NOT_EXIST)
+	}
+	for i := range articles {
+		if err := ArticleService.checkArticlePermission(c, action, articles[i].ID, articles[i].OwnerID); err != nil {
+			return nil, err
+		}
+	}
+	return articles, nil
+}
+
+// purgeArticles 永久删除文章及其关联数据，需在事务中执行
+func purgeArticles(ctx context.Context, ids []int64) error {
+	if err := dao.ArticleDao.DeleteArticleByIDs(ctx, ids); err != nil {
+		return fmt.Errorf("delete article: %w", err)
+	}
+	if err := dao.ArticleContentDao.DeleteContentByArticleIDs(ctx, ids); err != nil {
+		return fmt.Errorf("delete article content: %w", err)
+	}
+	if err := dao.ArticleTagRelationDao.DeleteArticleTagRelationsByArticleIDs(ctx, ids); err != nil {
+		return fmt.Errorf("delete article tag relation: %w", err)
+	}
+	if err := dao.ArticleRevisionDao.DeleteRevisionByArticleIDs(ctx, ids); err != nil {
+		return fmt.Errorf("delete article revision: %w", err)
+	}
+	if err := dao.CommentDao.DeleteCommentByArticleIDs(ctx, ids); err != nil {
+		return fmt.Errorf("delete article comment: %w", err)
+	}
+	if err := dao.ArticleDraftDao.DeleteDraftByArticleIDs(ctx, ids); err != nil {
+		return fmt.Errorf("delete article draft: %w", err)
+	}
+	if err := dao.ArticleSlugHistoryDao.DeleteHistoryByArticleIDs(ctx, ids); err != nil {
+		return fmt.Errorf("delete article slug history: %w", err)
+	}
+	if err := dao.ArticleSeriesDao.DeleteRelationsByArticleIDs(ctx, ids); err != nil {
+		return fmt.Errorf("delete article series relation: %w", err)
+	}
+	return nil
+}
+
+func getArticleIDs(articles []model.Article) []int64 {
+	ids := make([]int64, 0, len(articles))
+	for i := range articles {
+		ids = append(ids, articles[i].ID)
+	}
+	return ids
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var AuditLogService = new(auditLogService)
+
+type auditLogService struct {
+}
+
+// Record 记录一条审计日志，需在修改数据的事务中执行，写入失败时修改一并回滚
+// before为nil表示新建，after为nil表示删除
+func (s *auditLogService) Record(c *gin.Context, action string, entityType string, entityID int64, before any, after any) error {
+	l := logger.FromContext(c.Request.Context())
+	beforeData, err := marshalAuditData(before)
+	if err != nil {
+		l.Error("Failed to marshal audit data", zap.Error(err), zap.String("entity type", entityType), zap.Int64("entity id", entityID))
+		return err
+	}
+	afterData, err := marshalAuditData(after)
+	if err != nil {
+		l.Error("Failed to marshal audit data", zap.Error(err), zap.String("entity type", entityType), zap.Int64("entity id", entityID))
+		return err
+	}
+	auditLog := &model.AuditLog{
+		ActorID:     c.GetInt(utils.CONTEXT_USER_ID),
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		BeforeData:  beforeData,
+		AfterData:   afterData,
+		RequestID:   c.GetString(utils.X_REQUEST_ID),
+		IP:          c.ClientIP(),
+		CreatedTime: time.Now(),
+	}
+	if err := dao.AuditLogDao.InsertAuditLog(c, auditLog); err != nil {
+		l.Error("Failed to insert audit log", zap.Error(err), zap.String("action", action), zap.String("entity type", entityType), zap.Int64("entity id", entityID))
+		return err
+	}
+	return nil
+}
+
+// RecordSystem 记录系统任务(启动初始化、定时任务等)的审计日志，操作人为AUDIT_SYSTEM_ACTOR_ID，需在修改数据的事务中执行
+func (s *auditLogService) RecordSystem(ctx context.Context, action string, entityType string, entityID int64, before any, after any) error {
+	l := logger.FromContext(ctx)
+	beforeData, err := marshalAuditData(before)
+	if err != nil {
+		l.Error("Failed to marshal audit data", zap.Error(err), zap.String("entity type", entityType), zap.Int64("entity id", entityID))
+		return err
+	}
+	afterData, err := marshalAuditData(after)
+	if err != nil {
+		l.Error("Failed to marshal audit data", zap.Error(err), zap.String("entity type", entityType), zap.Int64("entity id", entityID))
+		return err
+	}
+	auditLog := &model.AuditLog{
+		ActorID:     utils.AUDIT_SYSTEM_ACTOR_ID,
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		BeforeData:  beforeData,
+		AfterData:   afterData,
+		CreatedTime: time.Now(),
+	}
+	if err := dao.AuditLogDao.InsertAuditLog(ctx, auditLog); err != nil {
+		l.Error("Failed to insert audit log", zap.Error(err), zap.String("action", action), zap.String("entity type", entityType), zap.Int64("entity id", entityID))
+		return err
+	}
+	return nil
+}
+
+// ListAuditLog 管理员分页查询审计日志，按操作时间倒序
+func (s *auditLogService) ListAuditLog(c *gin.Context, listDto dto.AuditLogListDto) (*vo.AuditLogListVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	var auditLogList []model.AuditLog
+	var total int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		var listErr error
+		auditLogList, listErr = dao.AuditLogDao.ListAuditLog(c, listDto)
+		if listErr != nil {
+			l.Error("Failed to list audit log", zap.Error(listErr))
+			return listErr
+		}
+		var countErr error
+		total, countErr = dao.AuditLogDao.CountAuditLog(c, listDto)
+		if countErr != nil {
+			l.Error("Failed to count audit log", zap.Error(countErr))
+			return countErr
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to run db transaction", zap.Error(txErr))
+		return nil, txErr
+	}
+
+	list := make([]vo.AuditLogVo, 0, len(auditLogList))
+	for i := range auditLogList {
+		list = append(list, vo.AuditLogVo{
+			ID:          auditLogList[i].ID,
+			ActorID:     auditLogList[i].ActorID,
+			Action:      auditLogList[i].Action,
+			EntityType:  auditLogList[i].EntityType,
+			EntityID:    auditLogList[i].EntityID,
+			Before:      toAuditRawMessage(auditLogList[i].BeforeData),
+			After:       toAuditRawMessage(auditLogList[i].AfterData),
+			RequestID:   auditLogList[i].RequestID,
+			IP:          auditLogList[i].IP,
+			CreatedTime: auditLogList[i].CreatedTime.UnixMilli(),
+		})
+	}
+	return &vo.AuditLogListVo{
+		AuditLogList: list,
+		Pageinate:    buildPageinate(listDto.Pageinate, total),
+	}, nil
+}
+
+func marshalAuditData(data any) (string, error) {
+	if data == nil {
+		return "", nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func toAuditRawMessage(data string) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.RawMessage(data)
+}
+
+// userAuditData 用户审计快照，不包含密码
+func userAuditData(user model.User) vo.UserVo {
+	userVo := toUserVo(user, nil)
+	userVo.Roles = nil
+	return userVo
+}
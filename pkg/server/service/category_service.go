@@ -9,6 +9,7 @@ import (
 	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
 	"github.com/narcissus1949/narcissus-blog/internal/logger"
 	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	"github.com/narcissus1949/narcissus-blog/pkg/dto"
 	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
 	"github.com/narcissus1949/narcissus-blog/pkg/vo"
@@ -158,12 +159,23 @@ func (s *categoryService) CreateCategoryList(ctx *gin.Context, categoryDto dto.C
 		})
 	}
 
-	if err := dao.CategoryDao.InsertCategoryBatch(ctx, categoryModels); err != nil {
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			return cerr.New(cerr.ERROR_ARTICLE_CATEGORY_EXIST)
+	txErr := mysql.RunDBTransaction(ctx, func() error {
+		if err := dao.CategoryDao.InsertCategoryBatch(ctx, categoryModels); err != nil {
+			if strings.Contains(err.Error(), "Duplicate entry") {
+				return cerr.New(cerr.ERROR_ARTICLE_CATEGORY_EXIST)
+			}
+			l.Error("Failed to insert category", zap.Error(err))
+			return err
+		}
+		for i := range categoryModels {
+			if err := AuditLogService.Record(ctx, utils.AUDIT_ACTION_CREATE, utils.AUDIT_ENTITY_CATEGORY, categoryModels[i].ID, nil, categoryModels[i]); err != nil {
+				return err
+			}
 		}
-		l.Error("Failed to insert category", zap.Error(err))
-		return err
+		return nil
+	})
+	if txErr != nil {
+		return txErr
 	}
 	return nil
 }
@@ -192,22 +204,47 @@ func (s *categoryService) UpdateCategory(ctx *gin.Context, updateDto dto.Categor
 			l.Error("Failed to update category", zap.Error(err), zap.Int64("category id", updateDto.ID))
 			return err
 		}
-		return nil
+		afterVo := *categoryVo
+		afterVo.Name = categoryModel.Name
+		afterVo.UpdatedTime = categoryModel.UpdatedTime.Format("2006-01-02 15:04:05")
+		return AuditLogService.Record(ctx, utils.AUDIT_ACTION_UPDATE, utils.AUDIT_ENTITY_CATEGORY, updateDto.ID, categoryVo, afterVo)
 	})
 	if txErr != nil {
 		l.Error("Failed to update category", zap.Error(txErr))
 		return txErr
 	}
-
+	// 分类页面地址包含分类名，改名后需要重新生成sitemap
+	if err := SitemapService.InvalidateCache(ctx); err != nil {
+		l.Error("Failed to invalidate sitemap cache", zap.Error(err))
+	}
 	return nil
 }
 
 // DeleteCategory 删除分类 - 批量
 func (s *categoryService) DeleteCategoryList(ctx *gin.Context, deleteRequest dto.CategoryDto) error {
 	l := logger.FromContext(ctx.Request.Context())
-	if err := dao.CategoryDao.DeleteCategoryByNameList(ctx, deleteRequest.NameList); err != nil {
-		l.Error("Failed to delete category", zap.Error(err), zap.Strings("category", deleteRequest.NameList))
-		return err
+	txErr := mysql.RunDBTransaction(ctx, func() error {
+		categoryList, err := dao.CategoryDao.ListCategoryByNameList(ctx, deleteRequest.NameList)
+		if err != nil {
+			l.Error("Failed to list category by name list", zap.Error(err), zap.Strings("category", deleteRequest.NameList))
+			return err
+		}
+		if err := dao.CategoryDao.DeleteCategoryByNameList(ctx, deleteRequest.NameList); err != nil {
+			l.Error("Failed to delete category", zap.Error(err), zap.Strings("category", deleteRequest.NameList))
+			return err
+		}
+		for i := range categoryList {
+			if err := AuditLogService.Record(ctx, utils.AUDIT_ACTION_DELETE, utils.AUDIT_ENTITY_CATEGORY, categoryList[i].ID, categoryList[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		return txErr
+	}
+	if err := SitemapService.InvalidateCache(ctx); err != nil {
+		l.Error("Failed to invalidate sitemap cache", zap.Error(err))
 	}
 	return nil
 }
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/comment"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var CommentService = new(commentService)
+
+type commentService struct {
+}
+
+// ListComment 分页查询文章已通过审核的评论，回复挂在所属顶层评论下
+func (s *commentService) ListComment(c *gin.Context, listDto dto.CommentListDto) (*vo.CommentListVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	if _, err := s.getCommentableArticle(c, listDto.ArticleID, false); err != nil {
+		return nil, err
+	}
+
+	var rootList, replyList []model.Comment
+	var total int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		var listErr error
+		rootList, listErr = dao.CommentDao.ListRootComment(c, listDto.ArticleID, utils.COMMENT_STATUS_APPROVED, listDto.Pageinate)
+		if listErr != nil {
+			l.Error("Failed to list root comment", zap.Error(listErr), zap.Int64("article id", listDto.ArticleID))
+			return listErr
+		}
+		var countErr error
+		total, countErr = dao.CommentDao.CountRootComment(c, listDto.ArticleID, utils.COMMENT_STATUS_APPROVED)
+		if countErr != nil {
+			l.Error("Failed to count root comment", zap.Error(countErr), zap.Int64("article id", listDto.ArticleID))
+			return countErr
+		}
+		if len(rootList) == 0 {
+			return nil
+		}
+		rootIDs := make([]int64, 0, len(rootList))
+		for i := range rootList {
+			rootIDs = append(rootIDs, rootList[i].ID)
+		}
+		replyList, listErr = dao.CommentDao.ListReplyByRootIDs(c, rootIDs, utils.COMMENT_STATUS_APPROVED)
+		if listErr != nil {
+			l.Error("Failed to list comment reply", zap.Error(listErr), zap.Int64s("root ids", rootIDs))
+			return listErr
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to run db transaction", zap.Error(txErr))
+		return nil, txErr
+	}
+
+	threads := comment.BuildThreads(rootList, replyList)
+	commentList := make([]vo.CommentVo, 0, len(threads))
+	for i := range threads {
+		rootVo := toCommentVo(threads[i].Root)
+		for _, reply := range threads[i].Replies {
+			replyVo := toCommentVo(reply.Comment)
+			replyVo.ReplyTo = reply.ReplyTo
+			rootVo.Replies = append(rootVo.Replies, replyVo)
+		}
+		commentList = append(commentList, rootVo)
+	}
+
+	return &vo.CommentListVo{
+		CommentList: commentList,
+		Pageinate:   buildPageinate(listDto.Pageinate, total),
+	}, nil
+}
+
+// CreateComment 发表评论，新评论需审核通过后才会展示
+func (s *commentService) CreateComment(c *gin.Context, createDto dto.CommentCreateDto) error {
+	l := logger.FromContext(c.Request.Context())
+	if _, err := s.getCommentableArticle(c, createDto.ArticleID, true); err != nil {
+		return err
+	}
+
+	commentModel := &model.Comment{
+		ArticleID: createDto.ArticleID,
+		Nickname:  createDto.Nickname,
+		Email:     createDto.Email,
+		Website:   createDto.Website,
+		Content:   createDto.Content,
+		Status:    utils.COMMENT_STATUS_PENDING,
+		IP:        c.ClientIP(),
+		UserAgent: truncateString(c.Request.UserAgent(), 255),
+	}
+	if createDto.ParentID > 0 {
+		parent, err := dao.CommentDao.QueryCommentByID(c, createDto.ParentID)
+		if err != nil {
+			l.Error("Failed to query parent comment", zap.Error(err), zap.Int64("parent id", createDto.ParentID))
+			return err
+		}
+		rootID, err := comment.RootOf(parent, createDto.ArticleID)
+		if err != nil {
+			return err
+		}
+		commentModel.ParentID = parent.ID
+		commentModel.RootID = rootID
+	}
+
+	now := time.Now()
+	commentModel.CreatedTime = now
+	commentModel.UpdatedTime = now
+	if err := dao.CommentDao.InsertComment(c, commentModel); err != nil {
+		l.Error("Failed to insert comment", zap.Error(err), zap.Int64("article id", createDto.ArticleID))
+		return err
+	}
+	return nil
+}
+
+// ListCommentAdmin 管理端分页查询评论
+func (s *commentService) ListCommentAdmin(c *gin.Context, listDto dto.CommentAdminListDto) (*vo.CommentAdminListVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	var commentList []model.CommentDetail
+	var total int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		var listErr error
+		commentList, listErr = dao.CommentDao.ListCommentAdmin(c, listDto)
+		if listErr != nil {
+			l.Error("Failed to list comment", zap.Error(listErr))
+			return listErr
+		}
+		var countErr error
+		total, countErr = dao.CommentDao.CountCommentAdmin(c, listDto)
+		if countErr != nil {
+			l.Error("Failed to count comment", zap.Error(countErr))
+			return countErr
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to run db transaction", zap.Error(txErr))
+		return nil, txErr
+	}
+
+	list := make([]vo.CommentAdminVo, 0, len(commentList))
+	for i := range commentList {
+		list = append(list, vo.CommentAdminVo{
+			CommentVo:    toCommentVo(commentList[i].Comment),
+			ArticleTitle: commentList[i].ArticleTitle,
+			Email:        commentList[i].Email,
+			IP:           commentList[i].IP,
+			UserAgent:    commentList[i].UserAgent,
+		})
+	}
+	return &vo.CommentAdminListVo{
+		CommentList: list,
+		Pageinate:   buildPageinate(listDto.Pageinate, total),
+	}, nil
+}
+
+// ApproveCommentList 审核通过评论 - 批量
+func (s *commentService) ApproveCommentList(c *gin.Context, idsDto dto.CommentIDsDto) error {
+	return s.updateCommentStatus(c, utils.AUDIT_ACTION_APPROVE, idsDto.IDs, utils.COMMENT_STATUS_APPROVED)
+}
+
+// HideCommentList 隐藏评论 - 批量
+func (s *commentService) HideCommentList(c *gin.Context, idsDto dto.CommentIDsDto) error {
+	return s.updateCommentStatus(c, utils.AUDIT_ACTION_HIDE, idsDto.IDs, utils.COMMENT_STATUS_HIDDEN)
+}
+
+// DeleteCommentList 删除评论及其下所有回复 - 批量
+func (s *commentService) DeleteCommentList(c *gin.Context, idsDto dto.CommentIDsDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		deleteIDs, err := comment.CollectDescendants(idsDto.IDs, func(parentIDs []int64) ([]int64, error) {
+			childIDs, err := dao.CommentDao.ListCommentIDByParentIDs(c, parentIDs)
+			if err != nil {
+				l.Error("Failed to list comment id by parent ids", zap.Error(err), zap.Int64s("parent ids", parentIDs))
+			}
+			return childIDs, err
+		})
+		if err != nil {
+			return err
+		}
+		commentList, err := dao.CommentDao.ListCommentByIDs(c, deleteIDs)
+		if err != nil {
+			l.Error("Failed to list comment by ids", zap.Error(err), zap.Int64s("ids", deleteIDs))
+			return err
+		}
+		if err := dao.CommentDao.DeleteCommentByIDs(c, deleteIDs); err != nil {
+			l.Error("Failed to delete comment", zap.Error(err), zap.Int64s("ids", deleteIDs))
+			return err
+		}
+		for i := range commentList {
+			if err := AuditLogService.Record(c, utils.AUDIT_ACTION_DELETE, utils.AUDIT_ENTITY_COMMENT, commentList[i].ID, commentList[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to delete comment list", zap.Error(txErr))
+		return txErr
+	}
+	return nil
+}
+
+// CountApprovedComment 统计文章已通过审核的评论数，查询失败时返回空结果，不影响文章查询
+func (s *commentService) CountApprovedComment(c *gin.Context, articleIDs []int64) map[int64]int {
+	countMap, err := dao.CommentDao.CountApprovedCommentByArticleIDs(c, articleIDs)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to count article comment", zap.Error(err), zap.Int64s("article ids", articleIDs))
+		return map[int64]int{}
+	}
+	return countMap
+}
+
+// updateCommentStatus 批量修改评论状态并记录审计日志
+func (s *commentService) updateCommentStatus(c *gin.Context, action string, ids []int64, status uint8) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		commentList, err := dao.CommentDao.ListCommentByIDs(c, ids)
+		if err != nil {
+			l.Error("Failed to list comment by ids", zap.Error(err), zap.Int64s("ids", ids))
+			return err
+		}
+		if _, err := dao.CommentDao.UpdateCommentStatusByIDs(c, ids, status); err != nil {
+			l.Error("Failed to update comment status", zap.Error(err), zap.Int64s("ids", ids), zap.Uint8("status", status))
+			return err
+		}
+		for i := range commentList {
+			if commentList[i].Status == status {
+				continue
+			}
+			after := commentList[i]
+			after.Status = status
+			if err := AuditLogService.Record(c, action, utils.AUDIT_ENTITY_COMMENT, commentList[i].ID, commentList[i], after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to update comment status", zap.Error(txErr), zap.Int64s("ids", ids))
+		return txErr
+	}
+	return nil
+}
+
+// getCommentableArticle 获取已上线的文章，forWrite为true时要求文章允许评论
+func (s *commentService) getCommentableArticle(c *gin.Context, articleID int64, forWrite bool) (*model.Article, error) {
+	l := logger.FromContext(c.Request.Context())
+	article, err := dao.ArticleDao.QueryArticleByID(c, articleID)
+	if err != nil {
+		l.Error("Failed to query article", zap.Error(err), zap.Int64("article id", articleID))
+		return nil, err
+	}
+	if err := comment.CheckArticle(article, forWrite); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+func toCommentVo(comment model.Comment) vo.CommentVo {
+	return vo.CommentVo{
+		ID:          comment.ID,
+		ArticleID:   comment.ArticleID,
+		RootID:      comment.RootID,
+		ParentID:    comment.ParentID,
+		Nickname:    comment.Nickname,
+		Website:     comment.Website,
+		Content:     comment.Content,
+		Status:      comment.Status,
+		CreatedTime: comment.CreatedTime.UnixMilli(),
+		Replies:     []vo.CommentVo{},
+	}
+}
+
+func buildPageinate(p dto.Pageinate, total int64) dto.Pageinate {
+	pageCount := total / int64(p.PageSize)
+	if total%int64(p.PageSize) != 0 {
+		pageCount++
+	}
+	return dto.Pageinate{
+		PageSize:  p.PageSize,
+		PageNum:   p.PageNum,
+		Total:     total,
+		PageCount: int(pageCount),
+	}
+}
+
+func truncateString(str string, maxLen int) string {
+	runes := []rune(str)
+	if len(runes) <= maxLen {
+		return str
+	}
+	return string(runes[:maxLen])
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/narcissus1949/narcissus-blog/cmd/blog/app/config"
 	"github.com/narcissus1949/narcissus-blog/internal/encrypt"
 	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/jwt"
 	"github.com/narcissus1949/narcissus-blog/internal/logger"
 	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	"github.com/narcissus1949/narcissus-blog/pkg/dto"
@@ -109,6 +110,11 @@ func (s *commoneService) PublicKeyEncrypt(req dto.PublicKeyEncrypDto) (vo.Public
 	return resp, nil
 }
 
+// GetJWKS 返回用于验证token的公钥集合，只使用HS256密钥时为空
+func (s *commoneService) GetJWKS() jwt.JWKSet {
+	return jwt.JWKS()
+}
+
 func checkImageFile(file *multipart.FileHeader) error {
 	if file == nil || file.Filename == "" || file.Size == 0 {
 		return errors.New("image is empty")
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/cmd/blog/app/config"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/feed"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"go.uber.org/zap"
+)
+
+var FeedService = new(feedService)
+
+type feedService struct {
+}
+
+// ListFeedVersion 查询订阅源包含的文章ID和更新时间，用于在生成订阅源前处理条件请求
+func (s *feedService) ListFeedVersion(c *gin.Context, feedDto dto.FeedDto) ([]feed.Version, error) {
+	listDto, err := s.feedListDto(c, feedDto)
+	if err != nil {
+		return nil, err
+	}
+	versionList, err := dao.ArticleDao.ListArticleVersion(c, listDto)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to list article version", zap.Error(err))
+		return nil, err
+	}
+	versions := make([]feed.Version, 0, len(versionList))
+	for i := range versionList {
+		versions = append(versions, feed.Version{ID: versionList[i].ID, Updated: versionList[i].UpdatedTime})
+	}
+	return versions, nil
+}
+
+// BuildFeed 根据已上线的最新文章生成订阅源，feedPath为订阅源接口路径
+func (s *feedService) BuildFeed(c *gin.Context, feedDto dto.FeedDto, feedPath string) (*feed.Feed, error) {
+	l := logger.FromContext(c.Request.Context())
+	mode := feedDto.Mode
+	if mode == "" {
+		mode = feed.Config.Mode
+	}
+
+	listDto, err := s.feedListDto(c, feedDto)
+	if err != nil {
+		return nil, err
+	}
+	siteURL := config.Config.App.SiteURL()
+	result := &feed.Feed{
+		Title:       feed.Config.Title,
+		Description: feed.Config.Description,
+		Link:        siteURL + "/",
+		FeedURL:     siteURL + feed.Config.PathPrefix + feedPath,
+		Language:    "zh-CN",
+	}
+	if feedDto.Category != "" {
+		result.Title += " - " + feedDto.Category
+		result.Link = siteURL + fmt.Sprintf(utils.SITE_CATEGORY_PATH_TEMPLATE, url.PathEscape(feedDto.Category))
+	}
+	if feedDto.Tag != "" {
+		result.Title += " - " + feedDto.Tag
+		result.Link = siteURL + fmt.Sprintf(utils.SITE_TAG_PATH_TEMPLATE, url.PathEscape(feedDto.Tag))
+	}
+	query := url.Values{}
+	for key, value := range map[string]string{"category": feedDto.Category, "tag": feedDto.Tag, "mode": feedDto.Mode} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if len(query) > 0 {
+		result.FeedURL += "?" + query.Encode()
+	}
+
+	articleList, err := dao.ArticleDao.ListArticle(c, listDto)
+	if err != nil {
+		l.Error("Failed to list article", zap.Error(err))
+		return nil, err
+	}
+	if len(articleList) == 0 {
+		return result, nil
+	}
+
+	// 全文模式查询文章内容
+	contentMap := map[int64]string{}
+	if mode == feed.MODE_FULL {
+		articleIDs := make([]int64, 0, len(articleList))
+		for i := range articleList {
+			articleIDs = append(articleIDs, articleList[i].ID)
+		}
+		contentList, err := dao.ArticleContentDao.ListContentByArticleIDs(c, articleIDs)
+		if err != nil {
+			l.Error("Failed to list article content", zap.Error(err), zap.Int64s("ids", articleIDs))
+			return nil, err
+		}
+		for i := range contentList {
+			rendered, err := ArticleRenderService.Render(c.Request.Context(), contentList[i].Content)
+			if err != nil {
+				l.Error("Failed to render article content", zap.Error(err), zap.Int64("article id", contentList[i].ArticleID))
+				return nil, err
+			}
+			contentMap[contentList[i].ArticleID] = rendered.HTML
+		}
+	}
+
+	for i := range articleList {
+		article := &articleList[i]
+		categories := []string{}
+		if article.CategoryName != "" {
+			categories = append(categories, article.CategoryName)
+		}
+		if len(article.TagNameList) > 0 {
+			tagList := strings.Split(article.TagNameList, ",")
+			sort.Strings(tagList)
+			categories = append(categories, tagList...)
+		}
+		link := siteURL + utils.GetSiteArticlePath(article.ID, article.Slug)
+		result.Items = append(result.Items, feed.Item{
+			ID:         link,
+			Title:      article.Title,
+			Link:       link,
+			Author:     article.Author,
+			Summary:    article.Summary,
+			Content:    contentMap[article.ID],
+			Categories: categories,
+			Published:  article.CreatedTime,
+			Updated:    article.UpdatedTime,
+		})
+		if article.UpdatedTime.After(result.Updated) {
+			result.Updated = article.UpdatedTime
+		}
+	}
+	return result, nil
+}
+
+// feedListDto 订阅源的文章查询条件，分类、标签不存在时返回错误
+func (s *feedService) feedListDto(c *gin.Context, feedDto dto.FeedDto) (dto.ArticleListDto, error) {
+	status := true
+	listDto := dto.ArticleListDto{
+		Type:   []int{utils.ARTICLE_TYPE_POST, utils.ARTICLE_TYPE_ESSAY},
+		Status: &status,
+		Pageinate: dto.Pageinate{
+			PageNum:  1,
+			PageSize: feed.Config.Limit,
+		},
+		OnlyPublished: true,
+	}
+	// 分类、标签订阅源只包含博文
+	if feedDto.Category != "" {
+		if _, err := CategoryService.GetCategoryIDByName(c, feedDto.Category); err != nil {
+			return listDto, err
+		}
+		listDto.Type = []int{utils.ARTICLE_TYPE_POST}
+		listDto.Category = feedDto.Category
+	}
+	if feedDto.Tag != "" {
+		tagIDList, err := TagService.ListTagIdByNameArr(c, dto.TagDto{NameList: []string{feedDto.Tag}})
+		if err != nil {
+			return listDto, err
+		}
+		if len(tagIDList) == 0 {
+			return listDto, cerr.New(cerr.ERROR_ARTICLE_TAG_NOT_EXIST)
+		}
+		listDto.Type = []int{utils.ARTICLE_TYPE_POST}
+		listDto.Tags = []string{feedDto.Tag}
+	}
+	return listDto, nil
+}
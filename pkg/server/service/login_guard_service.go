@@ -0,0 +1,198 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/loginguard"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var LoginGuardService = new(loginGuardService)
+
+type loginGuardService struct {
+}
+
+// guardKeys 失败计数和锁定使用的redis key前缀，登录与申请重置密码分别计数
+type guardKeys struct {
+	failUser string
+	failIP   string
+	lockUser string
+	lockIP   string
+}
+
+var (
+	loginGuardKeys = guardKeys{
+		failUser: utils.LOGIN_FAIL_USER_KEY,
+		failIP:   utils.LOGIN_FAIL_IP_KEY,
+		lockUser: utils.LOGIN_LOCK_USER_KEY,
+		lockIP:   utils.LOGIN_LOCK_IP_KEY,
+	}
+	forgotGuardKeys = guardKeys{
+		failUser: utils.PASSWORD_FORGOT_USER_KEY,
+		failIP:   utils.PASSWORD_FORGOT_IP_KEY,
+		lockUser: utils.PASSWORD_FORGOT_LOCK_USER_KEY,
+		lockIP:   utils.PASSWORD_FORGOT_LOCK_IP_KEY,
+	}
+)
+
+// NewCaptcha 生成图片验证码
+func (s *loginGuardService) NewCaptcha(c *gin.Context) (*vo.CaptchaVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	code, img, err := loginguard.GenerateCaptcha()
+	if err != nil {
+		l.Error("Failed to generate captcha", zap.Error(err))
+		return nil, err
+	}
+	captchaID, err := randomToken(16)
+	if err != nil {
+		l.Error("Failed to generate captcha id", zap.Error(err))
+		return nil, err
+	}
+	expire := time.Duration(loginguard.Config.CaptchaExpire) * time.Second
+	if err := cache.Client.Set(c, utils.CAPTCHA_KEY+captchaID, code, expire).Err(); err != nil {
+		l.Error("Failed to save captcha", zap.Error(err))
+		return nil, err
+	}
+	return &vo.CaptchaVo{
+		CaptchaID: captchaID,
+		Image:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(img),
+	}, nil
+}
+
+// Check 登录前检查用户名和IP是否被锁定，失败次数达到阈值后校验验证码
+func (s *loginGuardService) Check(c *gin.Context, loginDto dto.LoginDto) error {
+	l := logger.FromContext(c.Request.Context())
+	ip := c.ClientIP()
+	if err := s.CheckLocked(c, loginDto.Username); err != nil {
+		return err
+	}
+
+	values, err := cache.Client.MGet(c, utils.LOGIN_FAIL_USER_KEY+loginDto.Username, utils.LOGIN_FAIL_IP_KEY+ip).Result()
+	if err != nil {
+		l.Error("Failed to get login fail count", zap.Error(err))
+		return err
+	}
+	fails := make([]int64, 0, len(values))
+	for _, v := range values {
+		var count int64
+		if str, ok := v.(string); ok {
+			count, _ = strconv.ParseInt(str, 10, 64)
+		}
+		fails = append(fails, count)
+	}
+	if !loginguard.CaptchaRequired(fails...) {
+		return nil
+	}
+
+	verificationCode := strings.TrimSpace(loginDto.VerificationCode)
+	if loginDto.CaptchaID == "" || verificationCode == "" {
+		return cerr.New(cerr.ERROR_USER_CAPTCHA_REQUIRE)
+	}
+	// 验证码只能使用一次
+	code, err := cache.Client.GetDel(c, utils.CAPTCHA_KEY+loginDto.CaptchaID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return cerr.New(cerr.ERROR_USER_CAPTCHA_WRONG)
+		}
+		l.Error("Failed to get captcha", zap.Error(err))
+		return err
+	}
+	if !strings.EqualFold(code, verificationCode) {
+		return cerr.New(cerr.ERROR_USER_CAPTCHA_WRONG)
+	}
+	return nil
+}
+
+// CheckLocked 检查用户名和IP是否被锁定
+func (s *loginGuardService) CheckLocked(c *gin.Context, username string) error {
+	ttl, err := s.lockTTL(c, loginGuardKeys, username)
+	if err != nil || ttl <= 0 {
+		return err
+	}
+	logger.FromContext(c.Request.Context()).Warn("Login locked", zap.String("username", username), zap.String("ip", c.ClientIP()), zap.Duration("ttl", ttl))
+	return cerr.New(cerr.ERROR_USER_LOGIN_LOCKED, fmt.Sprintf("登录失败次数过多，请%d秒后重试", int(ttl.Seconds())+1))
+}
+
+// RecordFailure 记录登录失败，达到阈值后按失败次数指数增加锁定时长，失败只记录日志
+func (s *loginGuardService) RecordFailure(c *gin.Context, username string) {
+	s.record(c, loginGuardKeys, username)
+}
+
+// CheckForgot 检查账号和IP申请重置密码是否过于频繁，未锁定时计入本次申请。
+// 不论账号是否存在都计数，避免通过是否限流判断账号是否存在
+func (s *loginGuardService) CheckForgot(c *gin.Context, account string) error {
+	ttl, err := s.lockTTL(c, forgotGuardKeys, account)
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		logger.FromContext(c.Request.Context()).Warn("Password forgot locked", zap.String("account", account), zap.String("ip", c.ClientIP()), zap.Duration("ttl", ttl))
+		return cerr.New(cerr.ERROR_USER_RESET_TOO_FREQUENT, fmt.Sprintf("重置密码请求过于频繁，请%d秒后重试", int(ttl.Seconds())+1))
+	}
+	s.record(c, forgotGuardKeys, account)
+	return nil
+}
+
+// lockTTL 返回用户名和IP中剩余锁定时间较长的一个，未锁定时返回0
+func (s *loginGuardService) lockTTL(c *gin.Context, keys guardKeys, username string) (time.Duration, error) {
+	var maxTTL time.Duration
+	for _, key := range []string{keys.lockUser + username, keys.lockIP + c.ClientIP()} {
+		ttl, err := cache.Client.TTL(c, key).Result()
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("Failed to get lock ttl", zap.Error(err), zap.String("key", key))
+			return 0, err
+		}
+		maxTTL = max(maxTTL, ttl)
+	}
+	return maxTTL, nil
+}
+
+// record 用户名和IP计数加一，达到阈值后按次数指数增加锁定时长，失败只记录日志
+func (s *loginGuardService) record(c *gin.Context, keys guardKeys, username string) {
+	l := logger.FromContext(c.Request.Context())
+	ip := c.ClientIP()
+	window := time.Duration(loginguard.Config.FailWindow) * time.Minute
+
+	pipe := cache.Client.TxPipeline()
+	userFails := pipe.Incr(c, keys.failUser+username)
+	pipe.Expire(c, keys.failUser+username, window)
+	ipFails := pipe.Incr(c, keys.failIP+ip)
+	pipe.Expire(c, keys.failIP+ip, window)
+	if _, err := pipe.Exec(c); err != nil {
+		l.Error("Failed to record failure", zap.Error(err), zap.String("username", username), zap.String("ip", ip))
+		return
+	}
+
+	if lock := loginguard.LockDuration(userFails.Val(), loginguard.Config.LockThreshold); lock > 0 {
+		if err := cache.Client.Set(c, keys.lockUser+username, userFails.Val(), lock).Err(); err != nil {
+			l.Error("Failed to lock username", zap.Error(err), zap.String("username", username))
+		}
+		l.Warn("Username locked", zap.String("key", keys.lockUser+username), zap.Int64("fails", userFails.Val()), zap.Duration("lock", lock))
+	}
+	if lock := loginguard.LockDuration(ipFails.Val(), loginguard.Config.IPLockThreshold); lock > 0 {
+		if err := cache.Client.Set(c, keys.lockIP+ip, ipFails.Val(), lock).Err(); err != nil {
+			l.Error("Failed to lock ip", zap.Error(err), zap.String("ip", ip))
+		}
+		l.Warn("IP locked", zap.String("key", keys.lockIP+ip), zap.Int64("fails", ipFails.Val()), zap.Duration("lock", lock))
+	}
+}
+
+// Reset 登录成功后清除用户名的失败计数，IP计数保留至过期，避免攻击者用自己的账号重置
+func (s *loginGuardService) Reset(c *gin.Context, username string) {
+	if err := cache.Client.Del(c, utils.LOGIN_FAIL_USER_KEY+username).Err(); err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to reset login fail count", zap.Error(err), zap.String("username", username))
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/moderation"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var ModerationService = new(moderationService)
+
+// 审核结果处理函数，key为提交类型，新增审核类型时在此注册
+var moderationSubjectAppliers = map[string]func(c *gin.Context, subjectIDs []int64, approved bool) error{
+	moderation.SUBJECT_TYPE_USER_SIGNUP: applyUserSignupModeration,
+}
+
+type moderationService struct {
+}
+
+// Submit 提交内容进入审核队列，需在调用方的事务中执行
+func (s *moderationService) Submit(c *gin.Context, subjectType string, subjectID int64, content string) error {
+	l := logger.FromContext(c.Request.Context())
+	if _, ok := moderationSubjectAppliers[subjectType]; !ok {
+		return cerr.New(cerr.ERROR_MODERATION_SUBJECT_NOT_SUPPORT)
+	}
+
+	fingerprint := getSubmitterFingerprint(c)
+	recentCount, countErr := countRecentSubmission(c, fingerprint)
+	if countErr != nil {
+		// 计数失败不影响提交，仅跳过重复提交检测
+		l.Error("Failed to count recent submission", zap.Error(countErr), zap.String("fingerprint", fingerprint))
+	}
+	result := moderation.Evaluate(moderation.Submission{
+		SubjectType: subjectType,
+		Content:     content,
+		Fingerprint: fingerprint,
+		RecentCount: recentCount,
+	})
+
+	now := time.Now()
+	moderationModel := &model.Moderation{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Content:     content,
+		Fingerprint: fingerprint,
+		IP:          c.ClientIP(),
+		Score:       result.Score,
+		Reasons:     truncateString(strings.Join(result.Reasons, ","), 500),
+		Status:      utils.MODERATION_STATUS_PENDING,
+		CreatedTime: now,
+		UpdatedTime: now,
+	}
+	if err := dao.ModerationDao.InsertModeration(c, moderationModel); err != nil {
+		l.Error("Failed to insert moderation", zap.Error(err), zap.String("subject type", subjectType), zap.Int64("subject id", subjectID))
+		return err
+	}
+	return nil
+}
+
+// ListModeration 分页查询审核队列，按可疑分数倒序
+func (s *moderationService) ListModeration(c *gin.Context, listDto dto.ModerationListDto) (*vo.ModerationListVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	var moderationList []model.Moderation
+	var total int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		var listErr error
+		moderationList, listErr = dao.ModerationDao.ListModeration(c, listDto)
+		if listErr != nil {
+			l.Error("Failed to list moderation", zap.Error(listErr))
+			return listErr
+		}
+		var countErr error
+		total, countErr = dao.ModerationDao.CountModeration(c, listDto)
+		if countErr != nil {
+			l.Error("Failed to count moderation", zap.Error(countErr))
+			return countErr
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to run db transaction", zap.Error(txErr))
+		return nil, txErr
+	}
+
+	list := make([]vo.ModerationVo, 0, len(moderationList))
+	for i := range moderationList {
+		reasons := []string{}
+		if len(moderationList[i].Reasons) > 0 {
+			reasons = strings.Split(moderationList[i].Reasons, ",")
+		}
+		var reviewedTime int64
+		if moderationList[i].ReviewedTime != nil {
+			reviewedTime = moderationList[i].ReviewedTime.UnixMilli()
+		}
+		list = append(list, vo.ModerationVo{
+			ID:           moderationList[i].ID,
+			SubjectType:  moderationList[i].SubjectType,
+			SubjectID:    moderationList[i].SubjectID,
+			Content:      moderationList[i].Content,
+			Fingerprint:  moderationList[i].Fingerprint,
+			IP:           moderationList[i].IP,
+			Score:        moderationList[i].Score,
+			Reasons:      reasons,
+			Status:       moderationList[i].Status,
+			ReviewerID:   moderationList[i].ReviewerID,
+			ReviewedTime: reviewedTime,
+			CreatedTime:  moderationList[i].CreatedTime.UnixMilli(),
+		})
+	}
+	return &vo.ModerationListVo{
+		ModerationList: list,
+		Pageinate:      buildPageinate(listDto.Pageinate, total),
+	}, nil
+}
+
+// ApproveModerationList 批量通过
+func (s *moderationService) ApproveModerationList(c *gin.Context, reviewDto dto.ModerationReviewDto) error {
+	return s.review(c, reviewDto.IDs, true)
+}
+
+// RejectModerationList 批量拒绝
+func (s *moderationService) RejectModerationList(c *gin.Context, reviewDto dto.ModerationReviewDto) error {
+	return s.review(c, reviewDto.IDs, false)
+}
+
+func (s *moderationService) review(c *gin.Context, ids []int64, approved bool) error {
+	l := logger.FromContext(c.Request.Context())
+	status := uint8(utils.MODERATION_STATUS_REJECTED)
+	if approved {
+		status = utils.MODERATION_STATUS_APPROVED
+	}
+	txErr := mysql.RunDBTransaction(c, func() error {
+		// 只处理待审核的记录，已处理的记录忽略
+		moderationList, err := dao.ModerationDao.ListModerationByIDsAndStatus(c, ids, utils.MODERATION_STATUS_PENDING)
+		if err != nil {
+			l.Error("Failed to list pending moderation", zap.Error(err), zap.Int64s("ids", ids))
+			return err
+		}
+		if len(moderationList) == 0 {
+			return cerr.New(cerr.ERROR_MODERATION_NOT_EXIST)
+		}
+
+		// 按提交类型分组，交由对应的处理函数更新实体状态
+		subjectIDsMap := map[string][]int64{}
+		pendingIDs := make([]int64, 0, len(moderationList))
+		for i := range moderationList {
+			subjectIDsMap[moderationList[i].SubjectType] = append(subjectIDsMap[moderationList[i].SubjectType], moderationList[i].SubjectID)
+			pendingIDs = append(pendingIDs, moderationList[i].ID)
+		}
+		for subjectType, subjectIDs := range subjectIDsMap {
+			applier, ok := moderationSubjectAppliers[subjectType]
+			if !ok {
+				l.Error("Moderation subject type not support", zap.String("subject type", subjectType))
+				return cerr.New(cerr.ERROR_MODERATION_SUBJECT_NOT_SUPPORT)
+			}
+			if err := applier(c, subjectIDs, approved); err != nil {
+				l.Error("Failed to apply moderation result", zap.Error(err), zap.String("subject type", subjectType), zap.Int64s("subject ids", subjectIDs))
+				return err
+			}
+		}
+
+		if _, err := dao.ModerationDao.UpdateModerationStatusByIDs(c, pendingIDs, status, c.GetInt(utils.CONTEXT_USER_ID)); err != nil {
+			l.Error("Failed to update moderation status", zap.Error(err), zap.Int64s("ids", pendingIDs))
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to review moderation", zap.Error(txErr))
+		return txErr
+	}
+	return nil
+}
+
+// applyUserSignupModeration 更新注册用户的状态并记录审计日志，需在事务中执行
+func applyUserSignupModeration(c *gin.Context, subjectIDs []int64, approved bool) error {
+	status, action := uint8(utils.USER_STATUS_REJECTED), utils.AUDIT_ACTION_REJECT
+	if approved {
+		status, action = utils.USER_STATUS_NORMAL, utils.AUDIT_ACTION_APPROVE
+	}
+	userList, err := dao.UserDaoInstance.ListUserByIDs(c, subjectIDs)
+	if err != nil {
+		return err
+	}
+	if err := dao.UserDaoInstance.UpdateUserStatusByIDs(c, subjectIDs, status); err != nil {
+		return err
+	}
+	return UserAdminService.recordUserList(c, action, userList, func(user *model.User) {
+		user.Status = status
+	})
+}
+
+// getSubmitterFingerprint 由IP和User-Agent在服务端计算提交者指纹，不使用客户端可随意修改的cookie
+func getSubmitterFingerprint(c *gin.Context) string {
+	return utils.GenerateTempUserID(c.ClientIP(), c.Request.UserAgent())
+}
+
+// countRecentSubmission 记录本次提交，并返回统计窗口内的提交次数
+// 计数不存在时先创建并设置过期时间，与自增在同一事务中执行，保证计数一定会过期
+func countRecentSubmission(c *gin.Context, fingerprint string) (int, error) {
+	key := utils.MODERATION_SUBMITTER_KEY + fingerprint
+	pipe := cache.Client.TxPipeline()
+	pipe.SetNX(c, key, 0, time.Duration(moderation.Config.RepeatWindow)*time.Minute)
+	count := pipe.Incr(c, key)
+	if _, err := pipe.Exec(c); err != nil {
+		return 0, err
+	}
+	return int(count.Val()), nil
+}
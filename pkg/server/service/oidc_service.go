@@ -0,0 +1,426 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/moderation"
+	"github.com/narcissus1949/narcissus-blog/internal/oidc"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// 生成不重复用户名、昵称的最大尝试次数
+	oidcNameAttempts = 5
+	// 自动创建的用户名、昵称长度限制，与注册时一致
+	usernameMinLen = 5
+	usernameMaxLen = 20
+	nicknameMinLen = 5
+	nicknameMaxLen = 10
+)
+
+var (
+	usernameInvalidCharRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	nicknameInvalidCharRegexp = regexp.MustCompile(`[^\w\p{Han}]`)
+)
+
+var OidcService = new(oidcService)
+
+type oidcService struct {
+}
+
+// oidcState 授权请求状态，回调时一次性取出
+type oidcState struct {
+	Provider   string `json:"provider"`
+	Nonce      string `json:"nonce"`
+	Verifier   string `json:"verifier"`               // PKCE code verifier
+	LinkUserID int    `json:"link_user_id,omitempty"` // 不为0时表示为已登录用户关联外部身份
+}
+
+// ListProviders 查询已配置的第三方登录方式
+func (s *oidcService) ListProviders(c *gin.Context) []vo.OidcProviderVo {
+	providers := oidc.List()
+	list := make([]vo.OidcProviderVo, 0, len(providers))
+	for _, provider := range providers {
+		list = append(list, vo.OidcProviderVo{
+			Name:        provider.Name(),
+			DisplayName: provider.DisplayName(),
+		})
+	}
+	return list
+}
+
+// Authorize 生成第三方登录授权地址
+func (s *oidcService) Authorize(c *gin.Context, authorizeDto dto.OidcAuthorizeDto) (*vo.OidcAuthorizeVo, error) {
+	return s.authorize(c, authorizeDto.Provider, 0)
+}
+
+// AuthorizeLink 生成为当前用户关联第三方账号的授权地址
+func (s *oidcService) AuthorizeLink(c *gin.Context, authorizeDto dto.OidcAuthorizeDto) (*vo.OidcAuthorizeVo, error) {
+	return s.authorize(c, authorizeDto.Provider, c.GetInt(utils.CONTEXT_USER_ID))
+}
+
+// Callback 使用授权码完成第三方登录，外部身份未关联用户时按配置创建账号
+func (s *oidcService) Callback(c *gin.Context, callbackDto dto.OidcCallbackDto) (*vo.LoginVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	state, err := s.consumeState(c, callbackDto)
+	if err != nil {
+		return nil, err
+	}
+	if state.LinkUserID != 0 {
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_STATE_INVALID)
+	}
+	identity, err := s.exchange(c, callbackDto, state)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *model.User
+	txErr := mysql.RunDBTransaction(c, func() error {
+		userIdentity, err := dao.UserIdentityDao.QueryIdentity(c, identity.Provider, identity.Subject)
+		if err != nil {
+			l.Error("Failed to query user identity", zap.Error(err), zap.String("provider", identity.Provider))
+			return err
+		}
+		if userIdentity == nil {
+			if user, err = s.createUser(c, identity); err != nil {
+				return err
+			}
+			userIdentity = &model.UserIdentity{
+				UserID:      user.ID,
+				Provider:    identity.Provider,
+				Subject:     identity.Subject,
+				Email:       identity.Email,
+				CreatedTime: time.Now(),
+			}
+			if err := dao.UserIdentityDao.InsertIdentity(c, userIdentity); err != nil {
+				l.Error("Failed to insert user identity", zap.Error(err), zap.Int("user id", user.ID), zap.String("provider", identity.Provider))
+				return err
+			}
+		} else {
+			if user, err = UserRoleService.checkUserExist(c, userIdentity.UserID); err != nil {
+				return err
+			}
+		}
+		if err := dao.UserIdentityDao.UpdateLastLoginTime(c, userIdentity.ID, time.Now()); err != nil {
+			l.Error("Failed to update identity last login time", zap.Error(err), zap.Int("identity id", userIdentity.ID))
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to login with oidc", zap.Error(txErr), zap.String("provider", identity.Provider))
+		return nil, txErr
+	}
+
+	if err := checkLoginStatus(user); err != nil {
+		return nil, err
+	}
+	return UserServiceInstance.completeLogin(c, user)
+}
+
+// LinkCallback 使用授权码为当前用户关联第三方账号
+func (s *oidcService) LinkCallback(c *gin.Context, callbackDto dto.OidcCallbackDto) error {
+	l := logger.FromContext(c.Request.Context())
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	state, err := s.consumeState(c, callbackDto)
+	if err != nil {
+		return err
+	}
+	if state.LinkUserID != userID {
+		return cerr.New(cerr.ERROR_USER_OIDC_STATE_INVALID)
+	}
+	identity, err := s.exchange(c, callbackDto, state)
+	if err != nil {
+		return err
+	}
+
+	txErr := mysql.RunDBTransaction(c, func() error {
+		userIdentity, err := dao.UserIdentityDao.QueryIdentity(c, identity.Provider, identity.Subject)
+		if err != nil {
+			l.Error("Failed to query user identity", zap.Error(err), zap.String("provider", identity.Provider))
+			return err
+		}
+		if userIdentity != nil {
+			if userIdentity.UserID != userID {
+				return cerr.New(cerr.ERROR_USER_IDENTITY_LINKED)
+			}
+			return nil
+		}
+		if err := dao.UserIdentityDao.InsertIdentity(c, &model.UserIdentity{
+			UserID:      userID,
+			Provider:    identity.Provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			CreatedTime: time.Now(),
+		}); err != nil {
+			l.Error("Failed to insert user identity", zap.Error(err), zap.Int("user id", userID), zap.String("provider", identity.Provider))
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to link user identity", zap.Error(txErr), zap.Int("user id", userID), zap.String("provider", identity.Provider))
+		return txErr
+	}
+	return nil
+}
+
+// ListIdentities 查询当前用户关联的第三方账号
+func (s *oidcService) ListIdentities(c *gin.Context) ([]vo.UserIdentityVo, error) {
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	identities, err := dao.UserIdentityDao.ListIdentityByUserID(c, userID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to list user identities", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	list := make([]vo.UserIdentityVo, 0, len(identities))
+	for _, identity := range identities {
+		identityVo := vo.UserIdentityVo{
+			Provider:    identity.Provider,
+			Email:       identity.Email,
+			CreatedTime: identity.CreatedTime.UnixMilli(),
+		}
+		if identity.LastLoginTime != nil {
+			identityVo.LastLoginTime = identity.LastLoginTime.UnixMilli()
+		}
+		list = append(list, identityVo)
+	}
+	return list, nil
+}
+
+// Unlink 解除当前用户与第三方账号的关联
+func (s *oidcService) Unlink(c *gin.Context, unlinkDto dto.OidcUnlinkDto) error {
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	rowsAffected, err := dao.UserIdentityDao.DeleteIdentity(c, userID, unlinkDto.Provider)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to delete user identity", zap.Error(err),
+			zap.Int("user id", userID), zap.String("provider", unlinkDto.Provider))
+		return err
+	}
+	if rowsAffected == 0 {
+		return cerr.New(cerr.ERROR_USER_IDENTITY_NOT_EXIST)
+	}
+	return nil
+}
+
+func (s *oidcService) authorize(c *gin.Context, providerName string, linkUserID int) (*vo.OidcAuthorizeVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	provider, err := oidc.Get(providerName)
+	if err != nil {
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_PROVIDER_NOT_EXIST)
+	}
+	stateToken, err := randomToken(32)
+	if err != nil {
+		l.Error("Failed to generate oidc state", zap.Error(err))
+		return nil, err
+	}
+	nonce, err := randomToken(16)
+	if err != nil {
+		l.Error("Failed to generate oidc nonce", zap.Error(err))
+		return nil, err
+	}
+	state := oidcState{
+		Provider:   providerName,
+		Nonce:      nonce,
+		Verifier:   oidc.GenerateVerifier(),
+		LinkUserID: linkUserID,
+	}
+	authorizationURL, err := provider.AuthCodeURL(c, stateToken, state.Nonce, state.Verifier)
+	if err != nil {
+		l.Error("Failed to build oidc authorization url", zap.Error(err), zap.String("provider", providerName))
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_FAILED)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf(utils.OIDC_STATE_KEY_TEMPLATE, hashToken(stateToken))
+	if err := cache.Client.Set(c, key, data, time.Duration(oidc.Config.StateExpire)*time.Second).Err(); err != nil {
+		l.Error("Failed to save oidc state", zap.Error(err), zap.String("provider", providerName))
+		return nil, err
+	}
+	return &vo.OidcAuthorizeVo{AuthorizationURL: authorizationURL}, nil
+}
+
+// consumeState 取出并删除授权请求状态，保证每个授权码只能回调一次
+func (s *oidcService) consumeState(c *gin.Context, callbackDto dto.OidcCallbackDto) (*oidcState, error) {
+	l := logger.FromContext(c.Request.Context())
+	key := fmt.Sprintf(utils.OIDC_STATE_KEY_TEMPLATE, hashToken(callbackDto.State))
+	data, err := cache.Client.GetDel(c, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, cerr.New(cerr.ERROR_USER_OIDC_STATE_INVALID)
+		}
+		l.Error("Failed to get oidc state", zap.Error(err))
+		return nil, err
+	}
+	var state oidcState
+	if err := json.Unmarshal(data, &state); err != nil {
+		l.Error("Failed to unmarshal oidc state", zap.Error(err))
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_STATE_INVALID)
+	}
+	if state.Provider != callbackDto.Provider {
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_STATE_INVALID)
+	}
+	return &state, nil
+}
+
+func (s *oidcService) exchange(c *gin.Context, callbackDto dto.OidcCallbackDto, state *oidcState) (*oidc.Identity, error) {
+	provider, err := oidc.Get(state.Provider)
+	if err != nil {
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_PROVIDER_NOT_EXIST)
+	}
+	identity, err := provider.Exchange(c, callbackDto.Code, state.Nonce, state.Verifier)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Warn("Failed to exchange oidc code", zap.Error(err), zap.String("provider", state.Provider))
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_FAILED)
+	}
+	if identity.Subject == "" {
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_FAILED)
+	}
+	return identity, nil
+}
+
+// createUser 外部身份首次登录时创建新用户，需在事务中执行。
+// 本地邮箱未经验证，不按邮箱自动关联已有用户，已有用户需登录后主动关联外部身份
+func (s *oidcService) createUser(c *gin.Context, identity *oidc.Identity) (*model.User, error) {
+	l := logger.FromContext(c.Request.Context())
+	if !oidc.Config.AllowSignup {
+		return nil, cerr.New(cerr.ERROR_USER_OIDC_SIGNUP_DISABLED)
+	}
+
+	username, err := s.availableUsername(c, identity)
+	if err != nil {
+		return nil, err
+	}
+	nickname, err := s.availableNickname(c, identity, username)
+	if err != nil {
+		return nil, err
+	}
+	// 随机密码，用户可通过找回密码设置
+	password, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		l.Error("Failed to hash password", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &model.User{
+		Username:  username,
+		Nickname:  nickname,
+		Password:  string(hashedPassword),
+		Status:    utils.USER_STATUS_NORMAL,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if identity.EmailVerified {
+		user.Email = identity.Email
+	}
+	// 开启审核时，新用户需审核通过后才能登录
+	if moderation.Config.Enable {
+		user.Status = utils.USER_STATUS_PENDING
+	}
+	if err := dao.UserDaoInstance.InsertUser(c, user); err != nil {
+		l.Error("Failed to insert user", zap.Error(err), zap.String("username", username))
+		return nil, err
+	}
+	if moderation.Config.Enable {
+		content := strings.Join([]string{user.Username, user.Nickname, user.Email}, " ")
+		if err := ModerationService.Submit(c, moderation.SUBJECT_TYPE_USER_SIGNUP, int64(user.ID), content); err != nil {
+			l.Error("Failed to submit user signup moderation", zap.Error(err), zap.String("username", username))
+			return nil, err
+		}
+	}
+	l.Info("User created by oidc login", zap.Int("user id", user.ID), zap.String("provider", identity.Provider))
+	return user, nil
+}
+
+// availableUsername 由外部身份生成未被使用的用户名
+// 外部身份的用户名由用户自行设置，始终追加随机后缀，避免与admin等特权账号或已有用户同名
+func (s *oidcService) availableUsername(c *gin.Context, identity *oidc.Identity) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base = usernameInvalidCharRegexp.ReplaceAllString(base, "")
+	if len(base) < usernameMinLen {
+		base = identity.Provider + "_" + base
+		base = usernameInvalidCharRegexp.ReplaceAllString(base, "")
+	}
+	// 预留后缀长度
+	if len(base) > usernameMaxLen-7 {
+		base = base[:usernameMaxLen-7]
+	}
+
+	for i := 0; i < oidcNameAttempts; i++ {
+		suffix, err := randomToken(3)
+		if err != nil {
+			return "", err
+		}
+		username := base + "_" + suffix
+		existUser, err := dao.UserDaoInstance.QueryByUsername(username)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("Failed to query user by username", zap.Error(err), zap.String("username", username))
+			return "", err
+		}
+		if existUser == nil {
+			return username, nil
+		}
+	}
+	return "", cerr.New(cerr.ERROR_USER_ALREADY_EXIST)
+}
+
+// availableNickname 由外部身份生成未被使用的昵称，冲突时追加随机后缀
+func (s *oidcService) availableNickname(c *gin.Context, identity *oidc.Identity, username string) (string, error) {
+	base := nicknameInvalidCharRegexp.ReplaceAllString(identity.Name, "")
+	if utf8.RuneCountInString(base) < nicknameMinLen {
+		base = username
+	}
+	// 预留后缀长度
+	if runes := []rune(base); len(runes) > nicknameMaxLen-4 {
+		base = string(runes[:nicknameMaxLen-4])
+	}
+
+	nickname := base
+	for i := 0; i < oidcNameAttempts; i++ {
+		if utf8.RuneCountInString(nickname) >= nicknameMinLen {
+			existUser, err := dao.UserDaoInstance.QueryByNickname(c, nickname)
+			if err != nil {
+				logger.FromContext(c.Request.Context()).Error("Failed to query user by nickname", zap.Error(err), zap.String("nickname", nickname))
+				return "", err
+			}
+			if existUser == nil {
+				return nickname, nil
+			}
+		}
+		suffix, err := randomToken(2)
+		if err != nil {
+			return "", err
+		}
+		nickname = base + suffix
+	}
+	return "", cerr.New(cerr.ERROR_USER_NICKNAME_EXIST)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/cmd/blog/app/config"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/encrypt"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/jwt"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/mail"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// 重置token随机字节数
+	resetTokenBytes = 32
+	// 后台发送重置邮件的超时时间
+	resetMailTimeout = time.Minute
+)
+
+var PasswordResetService = new(passwordResetService)
+
+type passwordResetService struct {
+}
+
+// RequestReset 向用户邮箱发送重置链接，账号不存在或无法发送时同样返回成功，避免暴露账号是否存在。
+// 邮件在后台发送，响应时间不随账号是否存在变化；同一账号和IP的申请次数按登录保护的阈值限流
+func (s *passwordResetService) RequestReset(c *gin.Context, forgotDto dto.ForgotPasswordDto) error {
+	l := logger.FromContext(c.Request.Context())
+	if err := LoginGuardService.CheckForgot(c, forgotDto.Account); err != nil {
+		return err
+	}
+	userList, err := dao.UserDaoInstance.ListByUsernameOrEmail(c, forgotDto.Account)
+	if err != nil {
+		l.Error("Failed to list user by username or email", zap.Error(err))
+		return err
+	}
+	users := make([]model.User, 0, len(userList))
+	for i := range userList {
+		if userList[i].Status != utils.USER_STATUS_NORMAL || userList[i].Email == "" {
+			l.Info("Skip password reset", zap.Int("user id", userList[i].ID), zap.Uint8("status", userList[i].Status))
+			continue
+		}
+		users = append(users, userList[i])
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	// gin.Context在请求结束后会被复用，后台任务只保留请求上下文中的日志等信息
+	ctx := context.WithoutCancel(c.Request.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, resetMailTimeout)
+		defer cancel()
+		for i := range users {
+			if err := s.sendResetMail(ctx, &users[i]); err != nil {
+				logger.FromContext(ctx).Error("Failed to send password reset mail", zap.Error(err), zap.Int("user id", users[i].ID))
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *passwordResetService) ConfirmReset(c *gin.Context, resetDto dto.ResetPasswordDto) error {
+	l := logger.FromContext(c.Request.Context())
+	// rsa解密
+	newPasswd, decryptErr := encrypt.RSADecryptWithBase64([]byte(resetDto.NewPassword), config.Config.App.PrivateKeyDir)
+	if decryptErr != nil {
+		l.Error("Failed to decrypt new password", zap.Error(decryptErr))
+		return cerr.NewParamError("new password invalid")
+	}
+
+	// 取出即删除，保证token只能使用一次
+	tokenHash := hashToken(resetDto.Token)
+	userIDStr, err := cache.Client.GetDel(c, fmt.Sprintf(utils.PASSWORD_RESET_KEY_TEMPLATE, tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return cerr.New(cerr.ERROR_USER_RESET_INVALID)
+		}
+		l.Error("Failed to get password reset token", zap.Error(err))
+		return err
+	}
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		l.Error("Invalid password reset token value", zap.Error(err), zap.String("value", userIDStr))
+		return cerr.New(cerr.ERROR_USER_RESET_INVALID)
+	}
+	if err := cache.Client.Del(c, utils.PASSWORD_RESET_USER_KEY+userIDStr).Err(); err != nil {
+		l.Error("Failed to delete user password reset key", zap.Error(err), zap.Int("user id", userID))
+	}
+
+	user, err := UserRoleService.checkUserExist(c, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status != utils.USER_STATUS_NORMAL {
+		return cerr.New(cerr.ERROR_USER_RESET_INVALID)
+	}
+
+	hashedPassword, hashErr := bcrypt.GenerateFromPassword(newPasswd, bcrypt.DefaultCost)
+	if hashErr != nil {
+		l.Error("Failed to hash password", zap.Error(hashErr), zap.Int("user id", userID))
+		return hashErr
+	}
+	txErr := mysql.RunDBTransaction(c, func() error {
+		if err := dao.UserDaoInstance.UpdateUserPassword(c, userID, string(hashedPassword)); err != nil {
+			l.Error("Failed to update password", zap.Error(err), zap.Int("user id", userID))
+			return err
+		}
+		// 个人访问token随密码重置一并失效
+		if err := dao.UserAccessTokenDao.DeleteByUserIDs(c, []int64{int64(userID)}); err != nil {
+			l.Error("Failed to delete user access tokens", zap.Error(err), zap.Int("user id", userID))
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to reset password", zap.Error(txErr), zap.Int("user id", userID))
+		return txErr
+	}
+	// 事务提交后再撤销，避免事务回滚导致密码未修改但会话已全部失效
+	if err := jwt.RevokeUserTokens(c, int64(userID)); err != nil {
+		l.Error("Failed to revoke user tokens", zap.Error(err), zap.Int("user id", userID))
+		return err
+	}
+	return nil
+}
+
+// sendResetMail 生成重置token并发送邮件，同一用户只保留最近一次申请的token
+func (s *passwordResetService) sendResetMail(ctx context.Context, user *model.User) error {
+	token, err := randomToken(resetTokenBytes)
+	if err != nil {
+		return err
+	}
+	tokenHash := hashToken(token)
+	expire := time.Duration(mail.Config.ResetExpire) * time.Minute
+
+	userKey := utils.PASSWORD_RESET_USER_KEY + strconv.Itoa(user.ID)
+	oldHash, err := cache.Client.Get(ctx, userKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	pipe := cache.Client.TxPipeline()
+	if oldHash != "" {
+		pipe.Del(ctx, fmt.Sprintf(utils.PASSWORD_RESET_KEY_TEMPLATE, oldHash))
+	}
+	pipe.Set(ctx, fmt.Sprintf(utils.PASSWORD_RESET_KEY_TEMPLATE, tokenHash), user.ID, expire)
+	pipe.Set(ctx, userKey, tokenHash, expire)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	link := config.Config.App.SiteURL() + fmt.Sprintf(utils.SITE_PASSWORD_RESET_PATH, token)
+	return mail.Default.Send(ctx, mail.Message{
+		To:      []string{user.Email},
+		Subject: "重置密码",
+		Body: fmt.Sprintf("%s，您好：\n\n我们收到了重置您账号(%s)密码的申请，请在%d分钟内打开以下链接设置新密码：\n\n%s\n\n如果这不是您本人的操作，请忽略本邮件。\n",
+			user.Nickname, user.Username, mail.Config.ResetExpire, link),
+	})
+}
+
+// hashToken redis中只保存token的sha256，避免泄露后被直接使用
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken 生成n字节随机数的十六进制字符串
+func randomToken(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+const (
+	// 每个用户最多可创建的token数量
+	personalTokenMaxCount = 20
+	// 保存的token前缀长度，包含PERSONAL_TOKEN_PREFIX
+	personalTokenPrefixLen = 12
+	// 最近使用时间的更新间隔，避免每次请求都写数据库
+	personalTokenTouchInterval = time.Minute
+)
+
+var PersonalTokenService = new(personalTokenService)
+
+type personalTokenService struct {
+}
+
+// ListTokens 查询当前用户的个人访问token
+func (s *personalTokenService) ListTokens(c *gin.Context) ([]vo.AccessTokenVo, error) {
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	tokens, err := dao.UserAccessTokenDao.ListByUserID(c, userID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to list access tokens", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	list := make([]vo.AccessTokenVo, 0, len(tokens))
+	for i := range tokens {
+		list = append(list, toAccessTokenVo(tokens[i]))
+	}
+	return list, nil
+}
+
+// CreateToken 为当前用户创建个人访问token，完整token只在创建时返回
+func (s *personalTokenService) CreateToken(c *gin.Context, createDto dto.AccessTokenCreateDto) (*vo.AccessTokenCreateVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	count, err := dao.UserAccessTokenDao.CountByUserID(c, userID)
+	if err != nil {
+		l.Error("Failed to count access tokens", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	if count >= personalTokenMaxCount {
+		return nil, cerr.New(cerr.ERROR_USER_ACCESS_TOKEN_LIMIT)
+	}
+
+	random, err := randomToken(32)
+	if err != nil {
+		l.Error("Failed to generate access token", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	token := utils.PERSONAL_TOKEN_PREFIX + random
+	scopes := slices.Clone(createDto.Scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+	now := time.Now()
+	accessToken := &model.UserAccessToken{
+		UserID:      userID,
+		Name:        createDto.Name,
+		TokenHash:   hashToken(token),
+		TokenPrefix: token[:personalTokenPrefixLen],
+		Scopes:      strings.Join(scopes, ","),
+		CreatedTime: now,
+	}
+	if createDto.ExpireDays > 0 {
+		expireTime := now.AddDate(0, 0, createDto.ExpireDays)
+		accessToken.ExpireTime = &expireTime
+	}
+	if err := dao.UserAccessTokenDao.InsertToken(c, accessToken); err != nil {
+		l.Error("Failed to insert access token", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	l.Info("Access token created", zap.Int("user id", userID), zap.Int("token id", accessToken.ID), zap.Strings("scopes", scopes))
+	return &vo.AccessTokenCreateVo{
+		AccessTokenVo: toAccessTokenVo(*accessToken),
+		Token:         token,
+	}, nil
+}
+
+// RevokeToken 删除当前用户的个人访问token，立即失效
+func (s *personalTokenService) RevokeToken(c *gin.Context, revokeDto dto.AccessTokenRevokeDto) error {
+	l := logger.FromContext(c.Request.Context())
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	rowsAffected, err := dao.UserAccessTokenDao.DeleteToken(c, userID, revokeDto.ID)
+	if err != nil {
+		l.Error("Failed to delete access token", zap.Error(err), zap.Int("user id", userID), zap.Int("token id", revokeDto.ID))
+		return err
+	}
+	if rowsAffected == 0 {
+		return cerr.New(cerr.ERROR_USER_ACCESS_TOKEN_NOT_EXIST)
+	}
+	l.Info("Access token revoked", zap.Int("user id", userID), zap.Int("token id", revokeDto.ID))
+	return nil
+}
+
+// Authenticate 校验个人访问token，返回token所属用户、当前角色和权限范围，token无效时返回nil
+func (s *personalTokenService) Authenticate(c *gin.Context, token string) (*rbac.TokenPrincipal, error) {
+	l := logger.FromContext(c.Request.Context())
+	accessToken, err := dao.UserAccessTokenDao.QueryByTokenHash(c, hashToken(token))
+	if err != nil {
+		l.Error("Failed to query access token", zap.Error(err))
+		return nil, err
+	}
+	if accessToken == nil {
+		return nil, nil
+	}
+	now := time.Now()
+	if accessToken.ExpireTime != nil && now.After(*accessToken.ExpireTime) {
+		return nil, nil
+	}
+	user, err := dao.UserDaoInstance.QueryByID(c, accessToken.UserID)
+	if err != nil {
+		l.Error("Failed to query user by id", zap.Error(err), zap.Int("user id", accessToken.UserID))
+		return nil, err
+	}
+	if user == nil || user.Status != utils.USER_STATUS_NORMAL || user.PasswordResetRequired {
+		return nil, nil
+	}
+	// token长期有效，角色每次从数据库读取
+	roles, err := UserRoleService.LoadRoles(c, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if accessToken.LastUsedTime == nil || now.Sub(*accessToken.LastUsedTime) >= personalTokenTouchInterval {
+		if err := dao.UserAccessTokenDao.UpdateLastUsed(c, accessToken.ID, now, c.ClientIP()); err != nil {
+			l.Error("Failed to update access token last used", zap.Error(err), zap.Int("token id", accessToken.ID))
+		}
+	}
+	return &rbac.TokenPrincipal{
+		UserID: user.ID,
+		Roles:  roles,
+		Scopes: strings.Split(accessToken.Scopes, ","),
+	}, nil
+}
+
+func toAccessTokenVo(token model.UserAccessToken) vo.AccessTokenVo {
+	tokenVo := vo.AccessTokenVo{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      strings.Split(token.Scopes, ","),
+		LastUsedIP:  token.LastUsedIP,
+		CreatedTime: token.CreatedTime.UnixMilli(),
+	}
+	if token.ExpireTime != nil {
+		tokenVo.ExpireTime = token.ExpireTime.UnixMilli()
+	}
+	if token.LastUsedTime != nil {
+		tokenVo.LastUsedTime = token.LastUsedTime.UnixMilli()
+	}
+	return tokenVo
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/jwt"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var SessionService = new(sessionService)
+
+type sessionService struct {
+}
+
+// ListSessions 查询当前用户的有效会话
+func (s *sessionService) ListSessions(c *gin.Context) ([]vo.SessionVo, error) {
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	currentSessionID := c.GetString(utils.CONTEXT_SESSION_ID)
+	sessions, err := jwt.ListUserSessions(c, userID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to list user sessions", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	list := make([]vo.SessionVo, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, vo.SessionVo{
+			ID:             session.ID,
+			Device:         session.Device,
+			UserAgent:      session.UserAgent,
+			IP:             session.IP,
+			CreatedTime:    session.CreatedTime,
+			LastActiveTime: session.LastActiveTime,
+			Current:        session.ID == currentSessionID,
+		})
+	}
+	return list, nil
+}
+
+// RevokeSession 撤销当前用户的指定会话，该会话的access token和refresh token立即失效
+func (s *sessionService) RevokeSession(c *gin.Context, revokeDto dto.SessionRevokeDto) error {
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	if err := jwt.DeleteSession(c, userID, revokeDto.SessionID); err != nil {
+		if errors.Is(err, jwt.ErrSessionNotFound) {
+			return cerr.New(cerr.ERROR_USER_SESSION_NOT_EXIST)
+		}
+		logger.FromContext(c.Request.Context()).Error("Failed to delete session", zap.Error(err),
+			zap.Int("user id", userID), zap.String("session id", revokeDto.SessionID))
+		return err
+	}
+	return nil
+}
+
+// RevokeOtherSessions 撤销当前用户除当前会话外的所有会话，当前会话通过登出结束
+func (s *sessionService) RevokeOtherSessions(c *gin.Context) error {
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	if err := jwt.DeleteUserSessions(c, userID, c.GetString(utils.CONTEXT_SESSION_ID)); err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to delete user sessions", zap.Error(err), zap.Int("user id", userID))
+		return err
+	}
+	return nil
+}
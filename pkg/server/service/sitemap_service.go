@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/cmd/blog/app/config"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/sitemap"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 拆分后的子sitemap路径
+const sitemapPagePathTemplate = "/sitemap/%d.xml"
+
+var SitemapService = new(sitemapService)
+
+type sitemapService struct {
+}
+
+// GetSitemap 获取sitemap，page为0时返回/sitemap.xml(URL数超过上限时为sitemap index)，
+// 否则返回第page个子sitemap，不存在时返回nil
+func (s *sitemapService) GetSitemap(c *gin.Context, page int) ([]byte, error) {
+	l := logger.FromContext(c.Request.Context())
+	// 缓存key包含版本，失效时只需增加版本，旧版本的缓存到期后自动删除
+	version, err := cache.Client.Get(c, utils.SITEMAP_VERSION_KEY).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		l.Error("Failed to get sitemap cache version", zap.Error(err))
+	}
+	pageName := "index"
+	if page > 0 {
+		pageName = strconv.Itoa(page)
+	}
+	cacheKey := fmt.Sprintf(utils.SITEMAP_CACHE_KEY_TEMPLATE, version, pageName)
+	cached, err := cache.Client.Get(c, cacheKey).Bytes()
+	if err == nil {
+		return cached, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		l.Error("Failed to get sitemap from cache", zap.Error(err), zap.String("key", cacheKey))
+	}
+
+	urls, err := s.listURLs(c)
+	if err != nil {
+		return nil, err
+	}
+	pageCount := sitemap.PageCount(len(urls))
+	var data []byte
+	switch {
+	case page == 0 && pageCount == 1:
+		data, err = sitemap.EncodeURLSet(urls)
+	case page == 0:
+		siteURL := config.Config.App.SiteURL()
+		sitemaps := make([]sitemap.URL, 0, pageCount)
+		for i := 1; i <= pageCount; i++ {
+			sitemaps = append(sitemaps, sitemap.URL{
+				Loc:     siteURL + fmt.Sprintf(sitemapPagePathTemplate, i),
+				LastMod: latestLastMod(sitemap.Page(urls, i)),
+			})
+		}
+		data, err = sitemap.EncodeIndex(sitemaps)
+	case page <= pageCount:
+		data, err = sitemap.EncodeURLSet(sitemap.Page(urls, page))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		l.Error("Failed to encode sitemap", zap.Error(err), zap.Int("page", page))
+		return nil, err
+	}
+
+	expire := time.Duration(sitemap.Config.CacheExpire) * time.Minute
+	if err := cache.Client.Set(c, cacheKey, data, expire).Err(); err != nil {
+		l.Error("Failed to set sitemap cache", zap.Error(err), zap.String("key", cacheKey))
+	}
+	return data, nil
+}
+
+// GetRobots 生成robots.txt
+func (s *sitemapService) GetRobots() []byte {
+	return sitemap.Robots(config.Config.App.SiteURL() + "/sitemap.xml")
+}
+
+// InvalidateCache 使sitemap缓存失效，文章保存(包括别名变化)或删除、分类和标签改名或删除后调用
+func (s *sitemapService) InvalidateCache(ctx context.Context) error {
+	return cache.Client.Incr(ctx, utils.SITEMAP_VERSION_KEY).Err()
+}
+
+// listURLs 列出首页、已发布文章、分类和标签页面
+func (s *sitemapService) listURLs(c *gin.Context) ([]sitemap.URL, error) {
+	l := logger.FromContext(c.Request.Context())
+	siteURL := config.Config.App.SiteURL()
+
+	articleList, err := dao.ArticleDao.ListPublishedArticleLastmod(c)
+	if err != nil {
+		l.Error("Failed to list published article lastmod", zap.Error(err))
+		return nil, err
+	}
+	categoryList, err := dao.ArticleDao.ListPublishedCategoryLastmod(c)
+	if err != nil {
+		l.Error("Failed to list published category lastmod", zap.Error(err))
+		return nil, err
+	}
+	tagList, err := dao.ArticleDao.ListPublishedTagLastmod(c)
+	if err != nil {
+		l.Error("Failed to list published tag lastmod", zap.Error(err))
+		return nil, err
+	}
+
+	urls := make([]sitemap.URL, 0, 1+len(articleList)+len(categoryList)+len(tagList))
+	urls = append(urls, sitemap.URL{Loc: siteURL + "/"})
+	for i := range articleList {
+		urls = append(urls, sitemap.URL{
+			Loc:     siteURL + utils.GetSiteArticlePath(articleList[i].ID, articleList[i].Slug),
+			LastMod: articleList[i].UpdatedTime,
+		})
+		if articleList[i].UpdatedTime.After(urls[0].LastMod) {
+			urls[0].LastMod = articleList[i].UpdatedTime
+		}
+	}
+	for i := range categoryList {
+		urls = append(urls, sitemap.URL{
+			Loc:     siteURL + fmt.Sprintf(utils.SITE_CATEGORY_PATH_TEMPLATE, url.PathEscape(categoryList[i].Name)),
+			LastMod: categoryList[i].UpdatedTime,
+		})
+	}
+	for i := range tagList {
+		urls = append(urls, sitemap.URL{
+			Loc:     siteURL + fmt.Sprintf(utils.SITE_TAG_PATH_TEMPLATE, url.PathEscape(tagList[i].Name)),
+			LastMod: tagList[i].UpdatedTime,
+		})
+	}
+	return urls, nil
+}
+
+func latestLastMod(urls []sitemap.URL) time.Time {
+	var latest time.Time
+	for _, u := range urls {
+		if u.LastMod.After(latest) {
+			latest = u.LastMod
+		}
+	}
+	return latest
+}
@@ -9,6 +9,7 @@ import (
 	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
 	"github.com/narcissus1949/narcissus-blog/internal/logger"
 	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	"github.com/narcissus1949/narcissus-blog/pkg/dto"
 	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
 	"github.com/narcissus1949/narcissus-blog/pkg/vo"
@@ -156,12 +157,23 @@ func (s *tagService) CreateTagList(ctx *gin.Context, tagDto dto.TagDto) error {
 			UpdatedTime: now,
 		})
 	}
-	if err := dao.TagDao.InsertTagBatch(ctx, tagModels); err != nil {
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			return cerr.New(cerr.ERROR_ARTICLE_TAG_EXIST)
+	txErr := mysql.RunDBTransaction(ctx, func() error {
+		if err := dao.TagDao.InsertTagBatch(ctx, tagModels); err != nil {
+			if strings.Contains(err.Error(), "Duplicate entry") {
+				return cerr.New(cerr.ERROR_ARTICLE_TAG_EXIST)
+			}
+			l.Error("Failed to insert tag", zap.Error(err))
+			return err
+		}
+		for i := range tagModels {
+			if err := AuditLogService.Record(ctx, utils.AUDIT_ACTION_CREATE, utils.AUDIT_ENTITY_TAG, tagModels[i].ID, nil, tagModels[i]); err != nil {
+				return err
+			}
 		}
-		l.Error("Failed to insert tag", zap.Error(err))
-		return err
+		return nil
+	})
+	if txErr != nil {
+		return txErr
 	}
 	return nil
 }
@@ -189,21 +201,47 @@ func (s *tagService) UpdateTag(ctx *gin.Context, updateDto dto.TagUpdateDto) err
 			l.Error("Failed to update tag", zap.Error(err), zap.Int64("tag id", updateDto.ID))
 			return err
 		}
-		return nil
+		afterVo := *tagVo
+		afterVo.Name = tagModel.Name
+		afterVo.UpdatedTime = tagModel.UpdatedTime.Format("2006-01-02 15:04:05")
+		return AuditLogService.Record(ctx, utils.AUDIT_ACTION_UPDATE, utils.AUDIT_ENTITY_TAG, updateDto.ID, tagVo, afterVo)
 	})
 	if txErr != nil {
 		l.Error("Failed to update tag", zap.Error(txErr))
 		return txErr
 	}
-
+	// 标签页面地址包含标签名，改名后需要重新生成sitemap
+	if err := SitemapService.InvalidateCache(ctx); err != nil {
+		l.Error("Failed to invalidate sitemap cache", zap.Error(err))
+	}
 	return nil
 }
 
-func (s *tagService) DeleteTagList(deleteDto dto.TagDto) error {
-	if err := dao.TagDao.DeleteTagByNameList(deleteDto.NameList); err != nil {
-		// 无 context，这里回退使用全局 logger
-		logger.FromContext(nil).Error("Failed to delete tag", zap.Error(err), zap.Strings("tag", deleteDto.NameList))
-		return err
+// DeleteTagList 删除标签 - 批量
+func (s *tagService) DeleteTagList(ctx *gin.Context, deleteDto dto.TagDto) error {
+	l := logger.FromContext(ctx.Request.Context())
+	txErr := mysql.RunDBTransaction(ctx, func() error {
+		tagList, err := dao.TagDao.ListTagByNameList(ctx, deleteDto.NameList)
+		if err != nil {
+			l.Error("Failed to list tag by name list", zap.Error(err), zap.Strings("tag", deleteDto.NameList))
+			return err
+		}
+		if err := dao.TagDao.DeleteTagByNameList(ctx, deleteDto.NameList); err != nil {
+			l.Error("Failed to delete tag", zap.Error(err), zap.Strings("tag", deleteDto.NameList))
+			return err
+		}
+		for i := range tagList {
+			if err := AuditLogService.Record(ctx, utils.AUDIT_ACTION_DELETE, utils.AUDIT_ENTITY_TAG, tagList[i].ID, tagList[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		return txErr
+	}
+	if err := SitemapService.InvalidateCache(ctx); err != nil {
+		l.Error("Failed to invalidate sitemap cache", zap.Error(err))
 	}
 	return nil
 }
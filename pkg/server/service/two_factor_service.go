@@ -0,0 +1,382 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/cmd/blog/app/config"
+	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	"github.com/narcissus1949/narcissus-blog/internal/encrypt"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/twofactor"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// 预认证token有效期
+	preAuthExpire = 5 * time.Minute
+	// 预认证token允许的动态验证码错误次数，超过后需重新登录
+	preAuthMaxAttempts = 5
+	// 已使用的动态验证码保留时间，覆盖验证时允许的时间偏差
+	totpUsedExpire = 3 * twofactor.Period * time.Second
+)
+
+var TwoFactorService = new(twoFactorService)
+
+type twoFactorService struct {
+}
+
+// GetStatus 查询当前用户两步验证状态
+func (s *twoFactorService) GetStatus(c *gin.Context) (*vo.TotpStatusVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	userTotp, err := dao.UserTotpDao.QueryTotp(c, userID)
+	if err != nil {
+		l.Error("Failed to query user totp", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	status := &vo.TotpStatusVo{}
+	if userTotp == nil || !userTotp.Enabled {
+		return status, nil
+	}
+	status.Enabled = true
+	status.RecoveryCodesRemaining, err = dao.UserTotpDao.CountUnusedRecoveryCodes(c, userID)
+	if err != nil {
+		l.Error("Failed to count recovery codes", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	return status, nil
+}
+
+// Enroll 生成新的TOTP密钥，验证通过后才启用
+func (s *twoFactorService) Enroll(c *gin.Context) (*vo.TotpEnrollVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	user, err := UserRoleService.checkUserExist(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	userTotp, err := dao.UserTotpDao.QueryTotp(c, userID)
+	if err != nil {
+		l.Error("Failed to query user totp", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	if userTotp != nil && userTotp.Enabled {
+		return nil, cerr.New(cerr.ERROR_USER_TOTP_ENABLED)
+	}
+
+	key, err := twofactor.GenerateKey(config.Config.App.Name, user.Username)
+	if err != nil {
+		l.Error("Failed to generate totp key", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	now := time.Now()
+	if err := dao.UserTotpDao.SaveTotp(c, &model.UserTotp{
+		UserID:      userID,
+		Secret:      key.Secret,
+		Enabled:     false,
+		CreatedTime: now,
+		UpdatedTime: now,
+	}); err != nil {
+		l.Error("Failed to save user totp", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	return &vo.TotpEnrollVo{
+		Secret:     key.Secret,
+		OtpauthURI: key.OtpauthURI,
+		QRCode:     key.QRCode,
+	}, nil
+}
+
+// Activate 验证动态验证码并启用两步验证，返回恢复码
+func (s *twoFactorService) Activate(c *gin.Context, codeDto dto.TotpCodeDto) (*vo.TotpRecoveryCodesVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	userTotp, err := dao.UserTotpDao.QueryTotp(c, userID)
+	if err != nil {
+		l.Error("Failed to query user totp", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	if userTotp == nil {
+		return nil, cerr.New(cerr.ERROR_USER_TOTP_NOT_ENROLL)
+	}
+	if userTotp.Enabled {
+		return nil, cerr.New(cerr.ERROR_USER_TOTP_ENABLED)
+	}
+	if ok, err := s.verifyTOTP(c, userID, userTotp.Secret, codeDto.Code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, cerr.New(cerr.ERROR_USER_TOTP_INVALID)
+	}
+
+	var codes []string
+	txErr := mysql.RunDBTransaction(c, func() error {
+		if err := dao.UserTotpDao.EnableTotp(c, userID); err != nil {
+			l.Error("Failed to enable user totp", zap.Error(err), zap.Int("user id", userID))
+			return err
+		}
+		var err error
+		codes, err = s.resetRecoveryCodes(c, userID)
+		return err
+	})
+	if txErr != nil {
+		l.Error("Failed to activate totp", zap.Error(txErr), zap.Int("user id", userID))
+		return nil, txErr
+	}
+	return &vo.TotpRecoveryCodesVo{RecoveryCodes: codes}, nil
+}
+
+// Disable 校验密码和动态验证码(或恢复码)后关闭两步验证
+func (s *twoFactorService) Disable(c *gin.Context, disableDto dto.TotpDisableDto) error {
+	l := logger.FromContext(c.Request.Context())
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	user, err := UserRoleService.checkUserExist(c, userID)
+	if err != nil {
+		return err
+	}
+	// rsa解密
+	passwd, decryptErr := encrypt.RSADecryptWithBase64([]byte(disableDto.Password), config.Config.App.PrivateKeyDir)
+	if decryptErr != nil {
+		l.Error("Failed to decrypt password", zap.Error(decryptErr), zap.Int("user id", userID))
+		return cerr.NewParamError("password invalid")
+	}
+	if compareErr := bcrypt.CompareHashAndPassword([]byte(user.Password), passwd); compareErr != nil {
+		return cerr.New(cerr.ERROR_USER_PASSWORD_WRONG)
+	}
+
+	txErr := mysql.RunDBTransaction(c, func() error {
+		userTotp, err := dao.UserTotpDao.QueryTotp(c, userID)
+		if err != nil {
+			l.Error("Failed to query user totp", zap.Error(err), zap.Int("user id", userID))
+			return err
+		}
+		if userTotp == nil || !userTotp.Enabled {
+			return cerr.New(cerr.ERROR_USER_TOTP_NOT_ENROLL)
+		}
+		if ok, err := s.verifySecondFactor(c, userTotp, disableDto.Code); err != nil {
+			return err
+		} else if !ok {
+			return cerr.New(cerr.ERROR_USER_TOTP_INVALID)
+		}
+		if err := dao.UserTotpDao.DeleteTotp(c, userID); err != nil {
+			l.Error("Failed to delete user totp", zap.Error(err), zap.Int("user id", userID))
+			return err
+		}
+		if err := dao.UserTotpDao.DeleteRecoveryCodes(c, userID); err != nil {
+			l.Error("Failed to delete recovery codes", zap.Error(err), zap.Int("user id", userID))
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to disable totp", zap.Error(txErr), zap.Int("user id", userID))
+		return txErr
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes 校验动态验证码后重新生成恢复码，原恢复码全部失效
+func (s *twoFactorService) RegenerateRecoveryCodes(c *gin.Context, codeDto dto.TotpCodeDto) (*vo.TotpRecoveryCodesVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	userID := c.GetInt(utils.CONTEXT_USER_ID)
+	var codes []string
+	txErr := mysql.RunDBTransaction(c, func() error {
+		userTotp, err := dao.UserTotpDao.QueryTotp(c, userID)
+		if err != nil {
+			l.Error("Failed to query user totp", zap.Error(err), zap.Int("user id", userID))
+			return err
+		}
+		if userTotp == nil || !userTotp.Enabled {
+			return cerr.New(cerr.ERROR_USER_TOTP_NOT_ENROLL)
+		}
+		if ok, err := s.verifyTOTP(c, userID, userTotp.Secret, codeDto.Code); err != nil {
+			return err
+		} else if !ok {
+			return cerr.New(cerr.ERROR_USER_TOTP_INVALID)
+		}
+		codes, err = s.resetRecoveryCodes(c, userID)
+		return err
+	})
+	if txErr != nil {
+		l.Error("Failed to regenerate recovery codes", zap.Error(txErr), zap.Int("user id", userID))
+		return nil, txErr
+	}
+	return &vo.TotpRecoveryCodesVo{RecoveryCodes: codes}, nil
+}
+
+// isEnabled 用户是否已开启两步验证
+func (s *twoFactorService) isEnabled(c *gin.Context, userID int) (bool, error) {
+	userTotp, err := dao.UserTotpDao.QueryTotp(c, userID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to query user totp", zap.Error(err), zap.Int("user id", userID))
+		return false, err
+	}
+	return userTotp != nil && userTotp.Enabled, nil
+}
+
+// beginLogin 密码验证通过后签发预认证token，只能用于提交动态验证码
+func (s *twoFactorService) beginLogin(c *gin.Context, user *model.User) (*vo.LoginVo, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to generate pre-auth token", zap.Error(err), zap.Int("user id", user.ID))
+		return nil, err
+	}
+	key := fmt.Sprintf(utils.LOGIN_PREAUTH_KEY_TEMPLATE, hashToken(token))
+	if err := cache.Client.Set(c, key, user.ID, preAuthExpire).Err(); err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to save pre-auth token", zap.Error(err), zap.Int("user id", user.ID))
+		return nil, err
+	}
+	return &vo.LoginVo{
+		TwoFactorRequired: true,
+		PreAuthToken:      token,
+	}, nil
+}
+
+// CompleteLogin 校验预认证token和动态验证码(或恢复码)，通过后签发正式token
+func (s *twoFactorService) CompleteLogin(c *gin.Context, loginDto dto.LoginTwoFactorDto) (*vo.LoginVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	tokenHash := hashToken(loginDto.PreAuthToken)
+	preAuthKey := fmt.Sprintf(utils.LOGIN_PREAUTH_KEY_TEMPLATE, tokenHash)
+	attemptKey := fmt.Sprintf(utils.LOGIN_PREAUTH_ATTEMPT_TEMPLATE, tokenHash)
+	userIDStr, err := cache.Client.Get(c, preAuthKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, cerr.New(cerr.ERROR_USER_PREAUTH_INVALID)
+		}
+		l.Error("Failed to get pre-auth token", zap.Error(err))
+		return nil, err
+	}
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		l.Error("Invalid pre-auth token value", zap.Error(err), zap.String("value", userIDStr))
+		return nil, cerr.New(cerr.ERROR_USER_PREAUTH_INVALID)
+	}
+
+	user, err := UserRoleService.checkUserExist(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != utils.USER_STATUS_NORMAL {
+		return nil, cerr.New(cerr.ERROR_USER_DISABLED)
+	}
+	userTotp, err := dao.UserTotpDao.QueryTotp(c, userID)
+	if err != nil {
+		l.Error("Failed to query user totp", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	if userTotp == nil || !userTotp.Enabled {
+		return nil, cerr.New(cerr.ERROR_USER_PREAUTH_INVALID)
+	}
+	// 动态验证码错误计入用户名和IP的登录失败次数，锁定后不允许继续尝试
+	if err := LoginGuardService.CheckLocked(c, user.Username); err != nil {
+		return nil, err
+	}
+
+	// 校验前先增加尝试次数，避免并发请求绕过次数限制
+	pipe := cache.Client.TxPipeline()
+	attemptsCmd := pipe.Incr(c, attemptKey)
+	pipe.Expire(c, attemptKey, preAuthExpire)
+	if _, err := pipe.Exec(c); err != nil {
+		l.Error("Failed to increase pre-auth attempts", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	attempts := attemptsCmd.Val()
+	if attempts > preAuthMaxAttempts {
+		cache.Client.Del(c, preAuthKey, attemptKey)
+		return nil, cerr.New(cerr.ERROR_USER_PREAUTH_INVALID)
+	}
+
+	ok, err := s.verifySecondFactor(c, userTotp, loginDto.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		LoginGuardService.RecordFailure(c, user.Username)
+		// 错误次数过多时作废预认证token，需重新输入密码
+		if attempts >= preAuthMaxAttempts {
+			cache.Client.Del(c, preAuthKey, attemptKey)
+		}
+		l.Warn("Two-factor code invalid", zap.Int("user id", userID), zap.Int64("attempts", attempts))
+		return nil, cerr.New(cerr.ERROR_USER_TOTP_INVALID)
+	}
+	if err := cache.Client.Del(c, preAuthKey, attemptKey).Err(); err != nil {
+		l.Error("Failed to delete pre-auth token", zap.Error(err), zap.Int("user id", userID))
+	}
+	LoginGuardService.Reset(c, user.Username)
+
+	resp, err := UserServiceInstance.issueTokens(c, user)
+	if err != nil {
+		return nil, err
+	}
+	resp.PasswordResetRequired = user.PasswordResetRequired
+	return resp, nil
+}
+
+// verifySecondFactor 6位数字按动态验证码校验，否则按恢复码校验，恢复码使用后失效
+func (s *twoFactorService) verifySecondFactor(c *gin.Context, userTotp *model.UserTotp, code string) (bool, error) {
+	if twofactor.IsTOTPCode(code) {
+		return s.verifyTOTP(c, userTotp.UserID, userTotp.Secret, code)
+	}
+	rowsAffected, err := dao.UserTotpDao.UseRecoveryCode(c, userTotp.UserID, twofactor.HashRecoveryCode(code))
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to use recovery code", zap.Error(err), zap.Int("user id", userTotp.UserID))
+		return false, err
+	}
+	if rowsAffected > 0 {
+		logger.FromContext(c.Request.Context()).Info("Recovery code used", zap.Int("user id", userTotp.UserID))
+	}
+	return rowsAffected > 0, nil
+}
+
+// verifyTOTP 校验动态验证码，同一验证码在有效期内只能使用一次
+func (s *twoFactorService) verifyTOTP(c *gin.Context, userID int, secret string, code string) (bool, error) {
+	if !twofactor.IsTOTPCode(code) || !twofactor.Validate(code, secret) {
+		return false, nil
+	}
+	firstUse, err := cache.Client.SetNX(c, fmt.Sprintf(utils.TOTP_USED_KEY_TEMPLATE, userID, code), 1, totpUsedExpire).Result()
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to mark totp code used", zap.Error(err), zap.Int("user id", userID))
+		return false, err
+	}
+	return firstUse, nil
+}
+
+// resetRecoveryCodes 删除原恢复码并生成新的恢复码，需在事务中执行
+func (s *twoFactorService) resetRecoveryCodes(c *gin.Context, userID int) ([]string, error) {
+	l := logger.FromContext(c.Request.Context())
+	codes, err := twofactor.GenerateRecoveryCodes()
+	if err != nil {
+		l.Error("Failed to generate recovery codes", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	if err := dao.UserTotpDao.DeleteRecoveryCodes(c, userID); err != nil {
+		l.Error("Failed to delete recovery codes", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	now := time.Now()
+	models := make([]*model.UserRecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		models = append(models, &model.UserRecoveryCode{
+			UserID:      userID,
+			CodeHash:    twofactor.HashRecoveryCode(code),
+			CreatedTime: now,
+		})
+	}
+	if err := dao.UserTotpDao.InsertRecoveryCodes(c, models); err != nil {
+		l.Error("Failed to insert recovery codes", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	return codes, nil
+}
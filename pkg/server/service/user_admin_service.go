@@ -0,0 +1,227 @@
+package service
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/jwt"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var UserAdminService = new(userAdminService)
+
+type userAdminService struct {
+}
+
+// ListUser 管理员分页查询用户
+func (s *userAdminService) ListUser(c *gin.Context, listDto dto.UserListDto) (*vo.UserListVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	var userList []model.User
+	var roleMap map[int][]int32
+	var total int64
+	txErr := mysql.RunDBTransaction(c, func() error {
+		var listErr error
+		userList, listErr = dao.UserDaoInstance.ListUser(c, listDto)
+		if listErr != nil {
+			l.Error("Failed to list user", zap.Error(listErr))
+			return listErr
+		}
+		var countErr error
+		total, countErr = dao.UserDaoInstance.CountUser(c, listDto)
+		if countErr != nil {
+			l.Error("Failed to count user", zap.Error(countErr))
+			return countErr
+		}
+		userIDs := make([]int, 0, len(userList))
+		for i := range userList {
+			userIDs = append(userIDs, userList[i].ID)
+		}
+		var roleErr error
+		roleMap, roleErr = dao.UserRoleDao.ListRolesByUserIDs(c, userIDs)
+		if roleErr != nil {
+			l.Error("Failed to list user roles", zap.Error(roleErr))
+			return roleErr
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to run db transaction", zap.Error(txErr))
+		return nil, txErr
+	}
+
+	list := make([]vo.UserVo, 0, len(userList))
+	for i := range userList {
+		list = append(list, toUserVo(userList[i], roleMap[userList[i].ID]))
+	}
+	return &vo.UserListVo{
+		UserList:  list,
+		Pageinate: buildPageinate(listDto.Pageinate, total),
+	}, nil
+}
+
+// UpdateUser 管理员修改用户昵称、邮箱、头像
+func (s *userAdminService) UpdateUser(c *gin.Context, updateDto dto.UserUpdateDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		user, err := UserRoleService.checkUserExist(c, updateDto.ID)
+		if err != nil {
+			return err
+		}
+		if err := UserServiceInstance.checkNicknameAvailable(c, user, updateDto.Nickname); err != nil {
+			return err
+		}
+		before := userAuditData(*user)
+		user.Nickname = updateDto.Nickname
+		user.Email = updateDto.Email
+		user.AvatarPath = updateDto.AvatarPath
+		user.UpdatedAt = time.Now()
+		if err := dao.UserDaoInstance.UpdateUserProfile(c, user); err != nil {
+			return err
+		}
+		return AuditLogService.Record(c, utils.AUDIT_ACTION_UPDATE, utils.AUDIT_ENTITY_USER, int64(user.ID), before, userAuditData(*user))
+	})
+	if txErr != nil {
+		l.Error("Failed to update user", zap.Error(txErr), zap.Int("user id", updateDto.ID))
+		return txErr
+	}
+	return nil
+}
+
+// DisableUserList 批量禁用用户，已签发的token立即失效
+func (s *userAdminService) DisableUserList(c *gin.Context, idsDto dto.UserIDsDto) error {
+	l := logger.FromContext(c.Request.Context())
+	currentUserID := int64(c.GetInt(utils.CONTEXT_USER_ID))
+	for _, id := range idsDto.IDs {
+		if id == currentUserID {
+			return cerr.New(cerr.ERROR_USER_DISABLE_SELF)
+		}
+	}
+	txErr := mysql.RunDBTransaction(c, func() error {
+		userList, err := dao.UserDaoInstance.ListUserByIDs(c, idsDto.IDs)
+		if err != nil {
+			l.Error("Failed to list user by ids", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+			return err
+		}
+		if err := dao.UserDaoInstance.UpdateUserStatusByIDs(c, idsDto.IDs, utils.USER_STATUS_DISABLED); err != nil {
+			l.Error("Failed to disable user", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+			return err
+		}
+		return s.recordUserList(c, utils.AUDIT_ACTION_DISABLE, userList, func(user *model.User) {
+			user.Status = utils.USER_STATUS_DISABLED
+		})
+	})
+	if txErr != nil {
+		l.Error("Failed to disable user list", zap.Error(txErr))
+		return txErr
+	}
+	// 事务提交后再撤销，避免撤销成功但事务回滚
+	if err := jwt.RevokeUserTokens(c, idsDto.IDs...); err != nil {
+		l.Error("Failed to revoke user tokens", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+		return err
+	}
+	return nil
+}
+
+// EnableUserList 批量启用已禁用的用户，禁用前签发的token仍然无效
+func (s *userAdminService) EnableUserList(c *gin.Context, idsDto dto.UserIDsDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		userList, err := dao.UserDaoInstance.ListUserByIDs(c, idsDto.IDs)
+		if err != nil {
+			l.Error("Failed to list user by ids", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+			return err
+		}
+		if _, err := dao.UserDaoInstance.UpdateUserStatusByIDsAndStatus(c, idsDto.IDs, utils.USER_STATUS_DISABLED, utils.USER_STATUS_NORMAL); err != nil {
+			l.Error("Failed to enable user list", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+			return err
+		}
+		// 只记录实际被启用的用户
+		disabledList := make([]model.User, 0, len(userList))
+		for i := range userList {
+			if userList[i].Status == utils.USER_STATUS_DISABLED {
+				disabledList = append(disabledList, userList[i])
+			}
+		}
+		return s.recordUserList(c, utils.AUDIT_ACTION_ENABLE, disabledList, func(user *model.User) {
+			user.Status = utils.USER_STATUS_NORMAL
+		})
+	})
+	if txErr != nil {
+		l.Error("Failed to enable user list", zap.Error(txErr))
+		return txErr
+	}
+	return nil
+}
+
+// ForcePasswordReset 强制用户重置密码，已签发的token和个人访问token立即失效，
+// 重新登录后签发的token只能用于修改密码
+func (s *userAdminService) ForcePasswordReset(c *gin.Context, idsDto dto.UserIDsDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		userList, err := dao.UserDaoInstance.ListUserByIDs(c, idsDto.IDs)
+		if err != nil {
+			l.Error("Failed to list user by ids", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+			return err
+		}
+		if err := dao.UserDaoInstance.UpdatePasswordResetRequiredByIDs(c, idsDto.IDs, true); err != nil {
+			l.Error("Failed to set password reset required", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+			return err
+		}
+		if err := dao.UserAccessTokenDao.DeleteByUserIDs(c, idsDto.IDs); err != nil {
+			l.Error("Failed to delete user access tokens", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+			return err
+		}
+		return s.recordUserList(c, utils.AUDIT_ACTION_PASSWORD_RESET, userList, func(user *model.User) {
+			user.PasswordResetRequired = true
+		})
+	})
+	if txErr != nil {
+		l.Error("Failed to force password reset", zap.Error(txErr))
+		return txErr
+	}
+	// 事务提交后再撤销，避免撤销成功但事务回滚
+	if err := jwt.RevokeUserTokens(c, idsDto.IDs...); err != nil {
+		l.Error("Failed to revoke user tokens", zap.Error(err), zap.Int64s("ids", idsDto.IDs))
+		return err
+	}
+	return nil
+}
+
+// recordUserList 批量记录用户审计日志，apply为本次修改对用户的影响，需在事务中执行
+func (s *userAdminService) recordUserList(c *gin.Context, action string, userList []model.User, apply func(user *model.User)) error {
+	for i := range userList {
+		before := userAuditData(userList[i])
+		apply(&userList[i])
+		if err := AuditLogService.Record(c, action, utils.AUDIT_ENTITY_USER, int64(userList[i].ID), before, userAuditData(userList[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toUserVo(user model.User, roles []int32) vo.UserVo {
+	if roles == nil {
+		roles = []int32{}
+	}
+	return vo.UserVo{
+		ID:                    user.ID,
+		Username:              user.Username,
+		Nickname:              user.Nickname,
+		Email:                 user.Email,
+		PhoneNumber:           user.PhoneNumber,
+		AvatarPath:            user.AvatarPath,
+		Status:                user.Status,
+		PasswordResetRequired: user.PasswordResetRequired,
+		Roles:                 roles,
+		CreatedAt:             user.CreatedAt.UnixMilli(),
+		UpdatedAt:             user.UpdatedAt.UnixMilli(),
+	}
+}
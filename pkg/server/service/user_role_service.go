@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
+	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
+	"github.com/narcissus1949/narcissus-blog/internal/jwt"
+	"github.com/narcissus1949/narcissus-blog/internal/logger"
+	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/rbac"
+	"github.com/narcissus1949/narcissus-blog/internal/utils"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
+	"github.com/narcissus1949/narcissus-blog/pkg/vo"
+	"go.uber.org/zap"
+)
+
+var UserRoleService = new(userRoleService)
+
+type userRoleService struct {
+}
+
+// LoadRoles 查询用户角色用于签发token
+func (s *userRoleService) LoadRoles(c *gin.Context, user *model.User) ([]int32, error) {
+	roles, err := dao.UserRoleDao.ListRolesByUserID(c, user.ID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to list user roles", zap.Error(err), zap.Int("user id", user.ID))
+		return nil, err
+	}
+	return roles, nil
+}
+
+// BootstrapAdmin 启动时为配置的初始管理员授予管理员角色，系统中已有管理员时不处理，
+// 初始管理员仍在等待注册审核时一并审核通过
+func (s *userRoleService) BootstrapAdmin(ctx context.Context) error {
+	userID := rbac.Config.InitialAdminUserID
+	if userID == 0 {
+		return nil
+	}
+	l := logger.FromContext(ctx)
+	return mysql.RunDBTransaction2(ctx, func(ctx context.Context) error {
+		adminCount, err := dao.UserRoleDao.CountUserByRole(ctx, rbac.ROLE_ADMIN)
+		if err != nil {
+			return fmt.Errorf("count admin: %w", err)
+		}
+		if adminCount > 0 {
+			return nil
+		}
+		user, err := dao.UserDaoInstance.QueryByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("query initial admin: %w", err)
+		}
+		if user == nil {
+			return fmt.Errorf("initial admin user %d not exist", userID)
+		}
+		role, err := dao.UserRoleDao.QueryRoleByRole(ctx, rbac.ROLE_ADMIN)
+		if err != nil {
+			return fmt.Errorf("query admin role: %w", err)
+		}
+		if role == nil {
+			return errors.New("admin role not exist")
+		}
+
+		if user.Status == utils.USER_STATUS_PENDING {
+			if _, err := dao.UserDaoInstance.UpdateUserStatusByIDsAndStatus(ctx, []int64{int64(userID)}, utils.USER_STATUS_PENDING, utils.USER_STATUS_NORMAL); err != nil {
+				return fmt.Errorf("approve initial admin: %w", err)
+			}
+			after := *user
+			after.Status = utils.USER_STATUS_NORMAL
+			if err := AuditLogService.RecordSystem(ctx, utils.AUDIT_ACTION_APPROVE, utils.AUDIT_ENTITY_USER, int64(userID), userAuditData(*user), userAuditData(after)); err != nil {
+				return err
+			}
+		}
+		if err := dao.UserRoleDao.InsertUserRole(ctx, &model.UserRole{
+			UserID:      userID,
+			RoleID:      role.ID,
+			CreatedTime: time.Now(),
+		}); err != nil {
+			return fmt.Errorf("grant initial admin: %w", err)
+		}
+		if err := AuditLogService.RecordSystem(ctx, utils.AUDIT_ACTION_GRANT_ROLE, utils.AUDIT_ENTITY_USER, int64(userID),
+			vo.UserRoleVo{UserID: userID, Roles: []int32{}},
+			vo.UserRoleVo{UserID: userID, Roles: []int32{rbac.ROLE_ADMIN}}); err != nil {
+			return err
+		}
+		l.Info("Granted initial admin role", zap.Int("user id", userID), zap.String("username", user.Username))
+		return nil
+	})
+}
+
+// ListUserRoles 查询用户角色
+func (s *userRoleService) ListUserRoles(c *gin.Context, queryDto dto.UserRoleQueryDto) (*vo.UserRoleVo, error) {
+	l := logger.FromContext(c.Request.Context())
+	if _, err := s.checkUserExist(c, queryDto.UserID); err != nil {
+		return nil, err
+	}
+	roles, err := dao.UserRoleDao.ListRolesByUserID(c, queryDto.UserID)
+	if err != nil {
+		l.Error("Failed to list user roles", zap.Error(err), zap.Int("user id", queryDto.UserID))
+		return nil, err
+	}
+	return &vo.UserRoleVo{
+		UserID: queryDto.UserID,
+		Roles:  roles,
+	}, nil
+}
+
+// GrantRole 授予用户角色，新角色在用户下次签发token时生效
+func (s *userRoleService) GrantRole(c *gin.Context, roleDto dto.UserRoleDto) error {
+	l := logger.FromContext(c.Request.Context())
+	txErr := mysql.RunDBTransaction(c, func() error {
+		if _, err := s.checkUserExist(c, roleDto.UserID); err != nil {
+			return err
+		}
+		before, err := dao.UserRoleDao.ListRolesByUserID(c, roleDto.UserID)
+		if err != nil {
+			return err
+		}
+		if err := s.grant(c, roleDto.UserID, *roleDto.Role); err != nil {
+			return err
+		}
+		return s.recordRoleChange(c, utils.AUDIT_ACTION_GRANT_ROLE, roleDto.UserID, before)
+	})
+	if txErr != nil {
+		l.Error("Failed to grant role", zap.Error(txErr), zap.Int("user id", roleDto.UserID), zap.Int32("role", *roleDto.Role))
+		return txErr
+	}
+	return nil
+}
+
+// RevokeRole 撤销用户角色并使用户已签发的token失效，管理员不能撤销自己的管理员角色
+func (s *userRoleService) RevokeRole(c *gin.Context, roleDto dto.UserRoleDto) error {
+	l := logger.FromContext(c.Request.Context())
+	if *roleDto.Role == rbac.ROLE_ADMIN && roleDto.UserID == c.GetInt(utils.CONTEXT_USER_ID) {
+		return cerr.New(cerr.ERROR_USER_ROLE_SELF)
+	}
+	txErr := mysql.RunDBTransaction(c, func() error {
+		if _, err := s.checkUserExist(c, roleDto.UserID); err != nil {
+			return err
+		}
+		role, err := s.getRole(c, *roleDto.Role)
+		if err != nil {
+			return err
+		}
+		before, err := dao.UserRoleDao.ListRolesByUserID(c, roleDto.UserID)
+		if err != nil {
+			return err
+		}
+		if err := dao.UserRoleDao.DeleteUserRole(c, roleDto.UserID, role.ID); err != nil {
+			return err
+		}
+		return s.recordRoleChange(c, utils.AUDIT_ACTION_REVOKE_ROLE, roleDto.UserID, before)
+	})
+	if txErr != nil {
+		l.Error("Failed to revoke role", zap.Error(txErr), zap.Int("user id", roleDto.UserID), zap.Int32("role", *roleDto.Role))
+		return txErr
+	}
+	// 角色保存在access token中，事务提交后撤销用户的token，使撤销立即生效
+	if err := jwt.RevokeUserTokens(c, int64(roleDto.UserID)); err != nil {
+		l.Error("Failed to revoke user tokens", zap.Error(err), zap.Int("user id", roleDto.UserID))
+		return err
+	}
+	return nil
+}
+
+// recordRoleChange 记录用户角色变更前后的角色列表，需在事务中执行
+func (s *userRoleService) recordRoleChange(c *gin.Context, action string, userID int, before []int32) error {
+	after, err := dao.UserRoleDao.ListRolesByUserID(c, userID)
+	if err != nil {
+		return err
+	}
+	return AuditLogService.Record(c, action, utils.AUDIT_ENTITY_USER, int64(userID),
+		vo.UserRoleVo{UserID: userID, Roles: before},
+		vo.UserRoleVo{UserID: userID, Roles: after})
+}
+
+func (s *userRoleService) grant(c *gin.Context, userID int, roleValue int32) error {
+	role, err := s.getRole(c, roleValue)
+	if err != nil {
+		return err
+	}
+	return dao.UserRoleDao.InsertUserRole(c, &model.UserRole{
+		UserID:      userID,
+		RoleID:      role.ID,
+		CreatedTime: time.Now(),
+	})
+}
+
+func (s *userRoleService) getRole(c *gin.Context, roleValue int32) (*model.Role, error) {
+	role, err := dao.UserRoleDao.QueryRoleByRole(c, roleValue)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to query role", zap.Error(err), zap.Int32("role", roleValue))
+		return nil, err
+	}
+	if role == nil {
+		return nil, cerr.New(cerr.ERROR_USER_ROLE_NOT_EXIST)
+	}
+	return role, nil
+}
+
+func (s *userRoleService) checkUserExist(c *gin.Context, userID int) (*model.User, error) {
+	user, err := dao.UserDaoInstance.QueryByID(c, userID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to query user", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	if user == nil {
+		return nil, cerr.New(cerr.ERROR_USER_NOT_EXIST)
+	}
+	return user, nil
+}
@@ -1,16 +1,20 @@
 package service
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/narcissus1949/narcissus-blog/cmd/blog/app/config"
 	"github.com/narcissus1949/narcissus-blog/internal/database/cache"
+	"github.com/narcissus1949/narcissus-blog/internal/database/mysql"
 	"github.com/narcissus1949/narcissus-blog/internal/encrypt"
 	cerr "github.com/narcissus1949/narcissus-blog/internal/error"
 	"github.com/narcissus1949/narcissus-blog/internal/jwt"
 	"github.com/narcissus1949/narcissus-blog/internal/logger"
 	"github.com/narcissus1949/narcissus-blog/internal/model"
+	"github.com/narcissus1949/narcissus-blog/internal/moderation"
 	"github.com/narcissus1949/narcissus-blog/internal/utils"
 	"github.com/narcissus1949/narcissus-blog/pkg/dto"
 	"github.com/narcissus1949/narcissus-blog/pkg/server/dao"
@@ -62,13 +66,33 @@ func (s *userService) SignIn(ctx *gin.Context, signIn dto.SignIn) error {
 		Email:       signIn.Email,
 		PhoneNumber: signIn.PhoneNumber,
 		AvatarPath:  signIn.AvatarPath,
+		Status:      utils.USER_STATUS_NORMAL,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
+	// 开启审核时，新注册用户需审核通过后才能登录
+	if moderation.Config.Enable {
+		user.Status = utils.USER_STATUS_PENDING
+	}
 
-	if err := dao.UserDaoInstance.InsertUser(&user); err != nil {
-		l.Error("Failed to insert user", zap.Error(err), zap.String("username", signIn.Username))
-		return err
+	txErr := mysql.RunDBTransaction(ctx, func() error {
+		if err := dao.UserDaoInstance.InsertUser(ctx, &user); err != nil {
+			l.Error("Failed to insert user", zap.Error(err), zap.String("username", signIn.Username))
+			return err
+		}
+		if !moderation.Config.Enable {
+			return nil
+		}
+		content := strings.Join([]string{signIn.Username, signIn.Nickname, signIn.Email, signIn.AvatarPath}, " ")
+		if err := ModerationService.Submit(ctx, moderation.SUBJECT_TYPE_USER_SIGNUP, int64(user.ID), content); err != nil {
+			l.Error("Failed to submit user signup moderation", zap.Error(err), zap.String("username", signIn.Username))
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to sign in", zap.Error(txErr), zap.String("username", signIn.Username))
+		return txErr
 	}
 	return nil
 }
@@ -76,6 +100,10 @@ func (s *userService) SignIn(ctx *gin.Context, signIn dto.SignIn) error {
 func (s *userService) Login(ctx *gin.Context, loginRequest dto.LoginDto) (*vo.LoginVo, error) {
 	// 从请求上下文获取日志记录器
 	l := logger.FromContext(ctx.Request.Context())
+	// 暴力破解防护
+	if err := LoginGuardService.Check(ctx, loginRequest); err != nil {
+		return nil, err
+	}
 	// 用户验证
 	user, queryErr := dao.UserDaoInstance.QueryByUsername(loginRequest.Username)
 	if queryErr != nil {
@@ -84,6 +112,7 @@ func (s *userService) Login(ctx *gin.Context, loginRequest dto.LoginDto) (*vo.Lo
 	}
 
 	if user == nil {
+		LoginGuardService.RecordFailure(ctx, loginRequest.Username)
 		return nil, cerr.New(cerr.ERROR_USER_NOT_EXIST)
 	}
 
@@ -95,24 +124,39 @@ func (s *userService) Login(ctx *gin.Context, loginRequest dto.LoginDto) (*vo.Lo
 	}
 	// 密码验证
 	if compareErr := bcrypt.CompareHashAndPassword([]byte(user.Password), decryptPasswd); compareErr != nil {
+		LoginGuardService.RecordFailure(ctx, loginRequest.Username)
 		return nil, cerr.New(cerr.ERROR_USER_PASSWORD_WRONG)
 	}
+	// 密码验证通过后再检查账号状态，避免未知密码时通过返回的错误判断账号状态
+	if err := checkLoginStatus(user); err != nil {
+		return nil, err
+	}
 
-	// 生成token
-	accessToken, genAccessTokenErr := jwt.GenAccessToken(jwt.DefaultIssuse, user.ID)
-	if genAccessTokenErr != nil {
-		l.Error("Failed to generate access token", zap.Error(genAccessTokenErr), zap.String("username", loginRequest.Username))
-		return nil, genAccessTokenErr
+	resp, err := s.completeLogin(ctx, user)
+	if err != nil {
+		return nil, err
 	}
-	refreshToken, genRefreshTokenErr := jwt.GenRefreshToken(jwt.DefaultIssuse, user.ID)
-	if genRefreshTokenErr != nil {
-		l.Error("Failed to generate refresh token", zap.Error(genRefreshTokenErr), zap.String("username", loginRequest.Username))
-		return nil, genRefreshTokenErr
+	// 开启两步验证时，动态验证码校验通过后再清除失败计数
+	if !resp.TwoFactorRequired {
+		LoginGuardService.Reset(ctx, loginRequest.Username)
 	}
-	resp := &vo.LoginVo{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	return resp, nil
+}
+
+// completeLogin 身份验证通过后签发token，已开启两步验证时先签发预认证token，提交动态验证码后再签发正式token
+func (s *userService) completeLogin(ctx *gin.Context, user *model.User) (*vo.LoginVo, error) {
+	if enabled, err := TwoFactorService.isEnabled(ctx, user.ID); err != nil {
+		return nil, err
+	} else if enabled {
+		return TwoFactorService.beginLogin(ctx, user)
+	}
+
+	// 生成token
+	resp, issueErr := s.issueTokens(ctx, user)
+	if issueErr != nil {
+		return nil, issueErr
 	}
+	resp.PasswordResetRequired = user.PasswordResetRequired
 
 	return resp, nil
 }
@@ -148,6 +192,14 @@ func (s *userService) Logout(ctx *gin.Context, logoutRequest dto.LogoutDto) erro
 		return cerr.New(cerr.ERROR_USER_TOKEN_INVALIDE)
 	}
 
+	// 删除当前会话
+	if sessionID := ctx.GetString(utils.CONTEXT_SESSION_ID); sessionID != "" {
+		if err := jwt.DeleteSession(ctx, ctx.GetInt(utils.CONTEXT_USER_ID), sessionID); err != nil && !errors.Is(err, jwt.ErrSessionNotFound) {
+			l.Error("Failed to delete session", zap.Error(err), zap.String("session id", sessionID))
+			return err
+		}
+	}
+
 	if accessTokenRemainExpireTime > 0 {
 		status := redisClient.Set(ctx, utils.ACCESS_TOKEN_BLACKLIST+logoutRequest.AccessToken,
 			userIDFromAccessToken,
@@ -178,41 +230,249 @@ func (s *userService) RefreshToken(ctx *gin.Context, refreshTokenRequest dto.Ref
 		l.Error("Failed to parse refresh token", zap.Error(parseTokenErr))
 		return nil, parseTokenErr
 	}
-	expireTime, getExpireTimeErr := claims.GetExpirationTime()
-	if getExpireTimeErr != nil {
-		l.Error("Failed to get expire time from refresh token", zap.Error(getExpireTimeErr))
-		return nil, getExpireTimeErr
+	// 只接受启用会话管理后签发的refresh token
+	if claims.SessionID == "" || claims.ID == "" || !claims.IsRefreshToken() {
+		return nil, cerr.New(cerr.ERROR_USER_TOKEN_INVALIDE)
+	}
+
+	// 角色可能已变更，重新从数据库读取
+	user, queryErr := dao.UserDaoInstance.QueryByID(ctx, claims.UserID)
+	if queryErr != nil {
+		l.Error("Failed to query user by id", zap.Error(queryErr), zap.Int("user id", claims.UserID))
+		return nil, queryErr
+	}
+	if user == nil {
+		return nil, cerr.New(cerr.ERROR_USER_NOT_EXIST)
+	}
+	if user.Status == utils.USER_STATUS_DISABLED {
+		return nil, cerr.New(cerr.ERROR_USER_DISABLED)
+	}
+	revoked, checkRevokedErr := jwt.IsUserTokenRevoked(ctx, claims)
+	if checkRevokedErr != nil {
+		l.Error("Failed to check user token revoked", zap.Error(checkRevokedErr))
+		return nil, checkRevokedErr
+	}
+	if revoked {
+		return nil, cerr.New(cerr.ERROR_USER_TOKEN_INVALIDE)
+	}
+	roles, loadRolesErr := UserRoleService.LoadRoles(ctx, user)
+	if loadRolesErr != nil {
+		return nil, loadRolesErr
 	}
 
-	resp := &vo.LoginVo{}
-	// 刷新token
-	accessToken, genAccessTokenErr := jwt.GenAccessToken(jwt.DefaultIssuse, claims.UserID)
+	// 每次刷新都轮换refresh token，先签发新token再轮换，避免轮换成功但签发失败导致会话不可用
+	refreshID, genRefreshIDErr := jwt.NewTokenID()
+	if genRefreshIDErr != nil {
+		l.Error("Failed to generate refresh token id", zap.Error(genRefreshIDErr))
+		return nil, genRefreshIDErr
+	}
+	accessToken, genAccessTokenErr := jwt.GenAccessToken(claims.UserID, roles, claims.SessionID, user.PasswordResetRequired)
 	if genAccessTokenErr != nil {
 		l.Error("Failed to generate access token", zap.Error(genAccessTokenErr))
 		return nil, genAccessTokenErr
 	}
-	resp.AccessToken = accessToken
-
-	// 若refresh token距离过期时间小于jwt.RefreshTokenInterval，则同时刷新refresh token
-	remainExpire := expireTime.Unix() - time.Now().Unix()
-	if remainExpire <= int64(jwt.RefreshTokenInterval) {
-		if remainExpire > 0 {
-			_, setBlacklistErr := cache.Client.Set(ctx, utils.REFRESH_TOKEN_BLACKLIST+refreshTokenRequest.RefreshToken,
-				claims.UserID,
-				time.Duration(expireTime.Unix())*time.Second).Result()
-			if setBlacklistErr != nil {
-				l.Error("Failed to set refresh token blacklist", zap.Error(setBlacklistErr))
-				return nil, setBlacklistErr
-			}
+	refreshToken, genRefreshTokenErr := jwt.GenRefreshToken(claims.UserID, claims.SessionID, refreshID, user.PasswordResetRequired)
+	if genRefreshTokenErr != nil {
+		l.Error("Failed to generate refresh token", zap.Error(genRefreshTokenErr))
+		return nil, genRefreshTokenErr
+	}
+
+	rotateErr := jwt.RotateSession(ctx, claims.UserID, claims.SessionID, claims.ID, refreshID, ctx.ClientIP())
+	if rotateErr != nil {
+		switch {
+		case errors.Is(rotateErr, jwt.ErrSessionNotFound):
+			return nil, cerr.New(cerr.ERROR_USER_TOKEN_INVALIDE)
+		case errors.Is(rotateErr, jwt.ErrRefreshTokenReused):
+			// 已轮换的refresh token被重放，可能已泄露，整个会话已被撤销
+			l.Warn("Refresh token reused, session revoked",
+				zap.Int("user id", claims.UserID), zap.String("session id", claims.SessionID), zap.String("ip", ctx.ClientIP()))
+			return nil, cerr.New(cerr.ERROR_USER_TOKEN_INVALIDE)
+		}
+		l.Error("Failed to rotate session", zap.Error(rotateErr), zap.Int("user id", claims.UserID))
+		return nil, rotateErr
+	}
+
+	return &vo.LoginVo{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// GetProfile 查询当前登录用户资料
+func (s *userService) GetProfile(ctx *gin.Context) (*vo.UserVo, error) {
+	l := logger.FromContext(ctx.Request.Context())
+	userID := ctx.GetInt(utils.CONTEXT_USER_ID)
+	user, err := UserRoleService.checkUserExist(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := dao.UserRoleDao.ListRolesByUserID(ctx, userID)
+	if err != nil {
+		l.Error("Failed to list user roles", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
+	}
+	userVo := toUserVo(*user, roles)
+	return &userVo, nil
+}
+
+// UpdateProfile 修改当前登录用户资料
+func (s *userService) UpdateProfile(ctx *gin.Context, profileDto dto.UserProfileDto) (*vo.UserVo, error) {
+	l := logger.FromContext(ctx.Request.Context())
+	userID := ctx.GetInt(utils.CONTEXT_USER_ID)
+	txErr := mysql.RunDBTransaction(ctx, func() error {
+		user, err := UserRoleService.checkUserExist(ctx, userID)
+		if err != nil {
+			return err
 		}
+		if err := s.checkNicknameAvailable(ctx, user, profileDto.Nickname); err != nil {
+			return err
+		}
+		user.Nickname = profileDto.Nickname
+		user.Email = profileDto.Email
+		user.PhoneNumber = profileDto.PhoneNumber
+		user.AvatarPath = profileDto.AvatarPath
+		user.UpdatedAt = time.Now()
+		return dao.UserDaoInstance.UpdateUserProfile(ctx, user)
+	})
+	if txErr != nil {
+		l.Error("Failed to update profile", zap.Error(txErr), zap.Int("user id", userID))
+		return nil, txErr
+	}
+	return s.GetProfile(ctx)
+}
 
-		refreshToken, genRefreshTokenErr := jwt.GenRefreshToken(jwt.DefaultIssuse, claims.UserID)
-		if genRefreshTokenErr != nil {
-			l.Error("Failed to generate refresh token", zap.Error(genRefreshTokenErr))
-			return nil, genRefreshTokenErr
+// ChangePassword 修改当前登录用户密码，撤销该用户的其他会话并为当前会话签发新token
+func (s *userService) ChangePassword(ctx *gin.Context, passwordDto dto.ChangePasswordDto) (*vo.LoginVo, error) {
+	l := logger.FromContext(ctx.Request.Context())
+	userID := ctx.GetInt(utils.CONTEXT_USER_ID)
+	user, err := UserRoleService.checkUserExist(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// rsa解密
+	oldPasswd, decryptErr := encrypt.RSADecryptWithBase64([]byte(passwordDto.OldPassword), config.Config.App.PrivateKeyDir)
+	if decryptErr != nil {
+		l.Error("Failed to decrypt old password", zap.Error(decryptErr), zap.Int("user id", userID))
+		return nil, cerr.NewParamError("old password invalid")
+	}
+	newPasswd, decryptErr := encrypt.RSADecryptWithBase64([]byte(passwordDto.NewPassword), config.Config.App.PrivateKeyDir)
+	if decryptErr != nil {
+		l.Error("Failed to decrypt new password", zap.Error(decryptErr), zap.Int("user id", userID))
+		return nil, cerr.NewParamError("new password invalid")
+	}
+	// 旧密码验证
+	if compareErr := bcrypt.CompareHashAndPassword([]byte(user.Password), oldPasswd); compareErr != nil {
+		return nil, cerr.New(cerr.ERROR_USER_PASSWORD_WRONG)
+	}
+
+	hashedPassword, hashErr := bcrypt.GenerateFromPassword(newPasswd, bcrypt.DefaultCost)
+	if hashErr != nil {
+		l.Error("Failed to hash password", zap.Error(hashErr), zap.Int("user id", userID))
+		return nil, hashErr
+	}
+
+	txErr := mysql.RunDBTransaction(ctx, func() error {
+		if err := dao.UserDaoInstance.UpdateUserPassword(ctx, userID, string(hashedPassword)); err != nil {
+			l.Error("Failed to update password", zap.Error(err), zap.Int("user id", userID))
+			return err
 		}
-		resp.RefreshToken = refreshToken
+		// 个人访问token随密码修改一并失效
+		if err := dao.UserAccessTokenDao.DeleteByUserIDs(ctx, []int64{int64(userID)}); err != nil {
+			l.Error("Failed to delete user access tokens", zap.Error(err), zap.Int("user id", userID))
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		l.Error("Failed to change password", zap.Error(txErr), zap.Int("user id", userID))
+		return nil, txErr
+	}
+	// 事务提交后再撤销，避免事务回滚导致密码未修改但会话已全部失效
+	if err := jwt.RevokeUserTokens(ctx, int64(userID)); err != nil {
+		l.Error("Failed to revoke user tokens", zap.Error(err), zap.Int("user id", userID))
+		return nil, err
 	}
 
-	return resp, nil
+	user.PasswordResetRequired = false
+	return s.issueTokens(ctx, user)
+}
+
+// issueTokens 为用户创建新会话并签发access token和refresh token
+func (s *userService) issueTokens(ctx *gin.Context, user *model.User) (*vo.LoginVo, error) {
+	l := logger.FromContext(ctx.Request.Context())
+	roles, loadRolesErr := UserRoleService.LoadRoles(ctx, user)
+	if loadRolesErr != nil {
+		return nil, loadRolesErr
+	}
+	sessionID, genSessionIDErr := jwt.NewTokenID()
+	if genSessionIDErr != nil {
+		l.Error("Failed to generate session id", zap.Error(genSessionIDErr), zap.Int("user id", user.ID))
+		return nil, genSessionIDErr
+	}
+	refreshID, genRefreshIDErr := jwt.NewTokenID()
+	if genRefreshIDErr != nil {
+		l.Error("Failed to generate refresh token id", zap.Error(genRefreshIDErr), zap.Int("user id", user.ID))
+		return nil, genRefreshIDErr
+	}
+	accessToken, genAccessTokenErr := jwt.GenAccessToken(user.ID, roles, sessionID, user.PasswordResetRequired)
+	if genAccessTokenErr != nil {
+		l.Error("Failed to generate access token", zap.Error(genAccessTokenErr), zap.Int("user id", user.ID))
+		return nil, genAccessTokenErr
+	}
+	refreshToken, genRefreshTokenErr := jwt.GenRefreshToken(user.ID, sessionID, refreshID, user.PasswordResetRequired)
+	if genRefreshTokenErr != nil {
+		l.Error("Failed to generate refresh token", zap.Error(genRefreshTokenErr), zap.Int("user id", user.ID))
+		return nil, genRefreshTokenErr
+	}
+
+	// 记录会话，refresh token族以会话ID标识
+	now := time.Now().UnixMilli()
+	userAgent := ctx.Request.UserAgent()
+	if createSessionErr := jwt.CreateSession(ctx, &jwt.Session{
+		ID:             sessionID,
+		UserID:         user.ID,
+		Device:         jwt.ParseDevice(userAgent),
+		UserAgent:      userAgent,
+		IP:             ctx.ClientIP(),
+		RefreshID:      refreshID,
+		CreatedTime:    now,
+		LastActiveTime: now,
+	}); createSessionErr != nil {
+		l.Error("Failed to create session", zap.Error(createSessionErr), zap.Int("user id", user.ID))
+		return nil, createSessionErr
+	}
+	return &vo.LoginVo{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// checkLoginStatus 检查用户状态是否允许登录
+func checkLoginStatus(user *model.User) error {
+	switch user.Status {
+	case utils.USER_STATUS_PENDING:
+		return cerr.New(cerr.ERROR_USER_PENDING_REVIEW)
+	case utils.USER_STATUS_REJECTED:
+		return cerr.New(cerr.ERROR_USER_REJECTED)
+	case utils.USER_STATUS_DISABLED:
+		return cerr.New(cerr.ERROR_USER_DISABLED)
+	}
+	return nil
+}
+
+// checkNicknameAvailable 昵称变更时检查是否已被其他用户使用
+func (s *userService) checkNicknameAvailable(ctx *gin.Context, user *model.User, nickname string) error {
+	if user.Nickname == nickname {
+		return nil
+	}
+	existUser, err := dao.UserDaoInstance.QueryByNickname(ctx, nickname)
+	if err != nil {
+		logger.FromContext(ctx.Request.Context()).Error("Failed to query user by nickname", zap.Error(err), zap.String("nickname", nickname))
+		return err
+	}
+	if existUser != nil {
+		return cerr.New(cerr.ERROR_USER_NICKNAME_EXIST)
+	}
+	return nil
 }
@@ -0,0 +1,14 @@
+package vo
+
+type ArticleDraftVo struct {
+	ArticleID      int64    `json:"articleID"`
+	Title          string   `json:"title"`          // 文章标题
+	Summary        string   `json:"summary"`        // 摘要
+	Content        string   `json:"content"`        // 文章内容
+	Category       string   `json:"category"`       // 文章分类名称
+	TagNameList    []string `json:"tagNameList"`    // 文章标签名称列表
+	BaseVersion    int      `json:"baseVersion"`    // 草稿所基于的文章版本号
+	CurrentVersion int      `json:"currentVersion"` // 文章当前版本号，新建文章为0
+	Stale          bool     `json:"stale"`          // 文章在草稿之后已被其他会话修改
+	UpdatedTime    int64    `json:"updatedTime"`    // 草稿保存时间
+}
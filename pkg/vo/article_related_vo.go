@@ -0,0 +1,17 @@
+package vo
+
+type ArticleRelatedItemVo struct {
+	ID           int64    `json:"id"`
+	Title        string   `json:"title"`        // 文章标题
+	Slug         string   `json:"slug"`         // URL别名
+	Summary      string   `json:"summary"`      // 摘要
+	CategoryName string   `json:"categoryName"` // 文章分类名称
+	TagNameList  []string `json:"tagNameList"`  // 文章标签名称列表
+	Score        float64  `json:"score"`        // 相关度得分
+	CreatedTime  int64    `json:"createdTime"`
+	UpdatedTime  int64    `json:"updatedTime"`
+}
+
+type ArticleRelatedVo struct {
+	ArticleList []ArticleRelatedItemVo `json:"articleList"`
+}
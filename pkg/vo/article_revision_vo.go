@@ -0,0 +1,28 @@
+package vo
+
+import "github.com/narcissus1949/narcissus-blog/pkg/dto"
+
+type ArticleRevisionVo struct {
+	ID           int64    `json:"id"`
+	ArticleID    int64    `json:"articleID"`
+	Revision     int      `json:"revision"`          // 版本号
+	Title        string   `json:"title"`             // 文章标题
+	Summary      string   `json:"summary"`           // 摘要
+	CategoryName string   `json:"categoryName"`      // 文章分类名称
+	TagNameList  []string `json:"tagNameList"`       // 文章标签名称列表
+	Content      string   `json:"content,omitempty"` // 文章内容，列表查询时为空
+	EditorID     int      `json:"editorID"`          // 编辑者用户ID
+	CreatedTime  int64    `json:"createdTime"`       // 保存时间
+}
+
+type ArticleRevisionListVo struct {
+	RevisionList []ArticleRevisionVo `json:"revisionList"`
+	Pageinate    dto.Pageinate       `json:"pageinate"`
+}
+
+type ArticleRevisionDiffVo struct {
+	ArticleID int64  `json:"articleID"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Diff      string `json:"diff"` // unified diff格式的差异文本，无差异时为空
+}
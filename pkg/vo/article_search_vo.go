@@ -0,0 +1,21 @@
+package vo
+
+import "github.com/narcissus1949/narcissus-blog/pkg/dto"
+
+type ArticleSearchItemVo struct {
+	ID             int64    `json:"id"`
+	Title          string   `json:"title"`          // 文章标题
+	Summary        string   `json:"summary"`        // 摘要
+	CategoryName   string   `json:"categoryName"`   // 文章分类名称
+	TagNameList    []string `json:"tagNameList"`    // 文章标签名称列表
+	TitleHighlight string   `json:"titleHighlight"` // 高亮后的标题，命中词用<em>包裹，已做HTML转义
+	Snippet        string   `json:"snippet"`        // 高亮后的正文片段，命中词用<em>包裹，已做HTML转义
+	Score          float64  `json:"score"`          // 相关度得分
+	CreatedTime    int64    `json:"createdTime"`
+	UpdatedTime    int64    `json:"updatedTime"`
+}
+
+type ArticleSearchVo struct {
+	ArticleList []ArticleSearchItemVo `json:"articleList"`
+	Pageinate   dto.Pageinate         `json:"pageinate"`
+}
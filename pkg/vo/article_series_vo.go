@@ -0,0 +1,40 @@
+package vo
+
+import "github.com/narcissus1949/narcissus-blog/pkg/dto"
+
+type ArticleSeriesVo struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	ArticleCount int    `json:"articleCount"` // 已发布的文章数
+	CreatedTime  int64  `json:"createdTime"`
+	UpdatedTime  int64  `json:"updatedTime"`
+}
+
+type ArticleSeriesListVo struct {
+	SeriesList []ArticleSeriesVo `json:"seriesList"`
+	Pageinate  dto.Pageinate     `json:"pageinate"`
+}
+
+// 系列中的文章
+type ArticleSeriesItemVo struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+	Order int    `json:"order"` // 在系列中的序号，从1开始
+}
+
+// 系列详情，包含按顺序排列的文章目录
+type ArticleSeriesDetailVo struct {
+	ArticleSeriesVo
+	Articles []ArticleSeriesItemVo `json:"articles"`
+}
+
+// 文章详情中的系列导航
+type ArticleSeriesNavVo struct {
+	ID       int64                 `json:"id"`
+	Name     string                `json:"name"`
+	Prev     *ArticleSeriesItemVo  `json:"prev"` // 上一篇，没有时为空
+	Next     *ArticleSeriesItemVo  `json:"next"` // 下一篇，没有时为空
+	Articles []ArticleSeriesItemVo `json:"articles"`
+}
@@ -1,22 +1,30 @@
 package vo
 
-import "github.com/narcissus1949/narcissus-blog/pkg/dto"
+import (
+	"github.com/narcissus1949/narcissus-blog/internal/markdown"
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+)
 
 type ArticleMeta struct {
 	ID                  int64  `json:"id"`                  // 文章ID
 	Title               string `json:"title"`               // 文章标题
+	Slug                string `json:"slug"`                // URL别名
 	Summary             string `json:"summary"`             // 摘要
 	CategoryID          *int   `json:"categoryID"`          // 文章分类ID，每篇文章最多1个分类，可以为空
 	Type                uint8  `json:"type"`                // 文章类型，0表示原创，1表示转载
 	TagsID              string `json:"tagsID"`              // 文章标签ID列表，用,隔开，每篇文章最多有5个标签
 	Author              string `json:"author"`              // 作者姓名或标识
+	OwnerID             int    `json:"ownerID"`             // 所属用户ID，0表示无所属用户
 	AllowComment        bool   `json:"allowComment"`        // 是否允许评论，0表示不允许评论，1表示允许评论
 	Views               int    `json:"views"`               // 文章浏览量
+	CommentCount        int    `json:"commentCount"`        // 文章评论数，仅统计已通过审核的评论
 	Weight              int    `json:"weight"`              // 文章权重，默认初始值为0
 	IsSticky            bool   `json:"isSticky"`            // 是否置顶。0表示不置顶，1表示置顶。默认初始值为 0
 	IsOriginal          bool   `json:"isOriginal"`          // 原创/转载标识。0表示非原创，1表示原创。默认初始值为1，表示原创
 	OriginalArticleLink string `json:"originalArticleLink"` // 转载文章的原始文章链接，可为空
 	Status              uint8  `json:"status"`              // 状态，0表示offline，1表示online
+	PublishAt           int64  `json:"publishAt"`           // 定时发布时间，为0表示没有待执行的定时发布
+	Version             int    `json:"version"`             // 文章版本号，更新文章时需回传
 	CreatedTime         int64  `json:"createdTime"`         // 创建时间
 	UpdatedTime         int64  `json:"updatedTime"`         // 更新时间
 }
@@ -26,6 +34,12 @@ type ArticleDetailVo struct {
 	CategoryName string   `json:"categoryName"`
 	TagNameList  []string `json:"tagNameList"`
 	Content      string   `json:"content"`
+
+	ContentHTML string             `json:"contentHTML,omitempty"` // 渲染并过滤后的HTML，仅前台详情返回
+	TOC         []markdown.TOCItem `json:"toc,omitempty"`         // 按标题生成的目录
+	ReadingTime int                `json:"readingTime,omitempty"` // 预计阅读时间，单位: 分钟
+
+	Series *ArticleSeriesNavVo `json:"series,omitempty"` // 文章所属系列的导航，不属于任何系列时为空
 }
 
 // 查询文章列表响应内容
@@ -33,3 +47,21 @@ type ArticleListVo struct {
 	ArticleList []ArticleDetailVo `json:"articleList"`
 	Pageinate   dto.Pageinate     `json:"pageinate"`
 }
+
+// 回收站文章
+type ArticleTrashVo struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Slug        string `json:"slug"`
+	Type        uint8  `json:"type"`
+	Author      string `json:"author"`
+	OwnerID     int    `json:"ownerID"`
+	CreatedTime int64  `json:"createdTime"`
+	DeletedTime int64  `json:"deletedTime"` // 移入回收站的时间
+	ExpireTime  int64  `json:"expireTime"`  // 超过该时间后自动永久删除
+}
+
+type ArticleTrashListVo struct {
+	ArticleList []ArticleTrashVo `json:"articleList"`
+	Pageinate   dto.Pageinate    `json:"pageinate"`
+}
@@ -0,0 +1,25 @@
+package vo
+
+import (
+	"encoding/json"
+
+	"github.com/narcissus1949/narcissus-blog/pkg/dto"
+)
+
+type AuditLogVo struct {
+	ID          int64           `json:"id"`
+	ActorID     int             `json:"actorID"` // 操作人ID
+	Action      string          `json:"action"`
+	EntityType  string          `json:"entityType"`
+	EntityID    int64           `json:"entityID"`
+	Before      json.RawMessage `json:"before"` // 修改前的快照，新建时为null
+	After       json.RawMessage `json:"after"`  // 修改后的快照，删除时为null
+	RequestID   string          `json:"requestID"`
+	IP          string          `json:"ip"`
+	CreatedTime int64           `json:"createdTime"`
+}
+
+type AuditLogListVo struct {
+	AuditLogList []AuditLogVo  `json:"auditLogList"`
+	Pageinate    dto.Pageinate `json:"pageinate"`
+}
@@ -0,0 +1,36 @@
+package vo
+
+import "github.com/narcissus1949/narcissus-blog/pkg/dto"
+
+type CommentVo struct {
+	ID          int64       `json:"id"`
+	ArticleID   int64       `json:"articleID"`
+	RootID      int64       `json:"rootID"`      // 所属顶层评论ID，顶层评论为0
+	ParentID    int64       `json:"parentID"`    // 回复的评论ID，顶层评论为0
+	ReplyTo     string      `json:"replyTo"`     // 回复的评论者昵称
+	Nickname    string      `json:"nickname"`    // 评论者昵称
+	Website     string      `json:"website"`     // 评论者网站
+	Content     string      `json:"content"`     // 评论内容
+	Status      uint8       `json:"status"`      // 状态，0表示待审核，1表示已通过，2表示已隐藏
+	CreatedTime int64       `json:"createdTime"` // 创建时间
+	Replies     []CommentVo `json:"replies"`     // 楼中楼回复，按时间正序
+}
+
+// 管理端评论信息
+type CommentAdminVo struct {
+	CommentVo
+	ArticleTitle string `json:"articleTitle"`
+	Email        string `json:"email"`
+	IP           string `json:"ip"`
+	UserAgent    string `json:"userAgent"`
+}
+
+type CommentListVo struct {
+	CommentList []CommentVo   `json:"commentList"`
+	Pageinate   dto.Pageinate `json:"pageinate"`
+}
+
+type CommentAdminListVo struct {
+	CommentList []CommentAdminVo `json:"commentList"`
+	Pageinate   dto.Pageinate    `json:"pageinate"`
+}
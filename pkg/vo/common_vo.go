@@ -11,3 +11,8 @@ type UploadImageVo struct {
 type RASPublicKeyVo struct {
 	PublicKey string `json:"public_key"`
 }
+
+type CaptchaVo struct {
+	CaptchaID string `json:"captcha_id"`
+	Image     string `json:"image"` // PNG图片的data URI
+}
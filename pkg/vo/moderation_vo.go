@@ -0,0 +1,23 @@
+package vo
+
+import "github.com/narcissus1949/narcissus-blog/pkg/dto"
+
+type ModerationVo struct {
+	ID           int64    `json:"id"`
+	SubjectType  string   `json:"subjectType"`  // 提交类型
+	SubjectID    int64    `json:"subjectID"`    // 提交内容对应的实体ID
+	Content      string   `json:"content"`      // 参与检测的文本快照
+	Fingerprint  string   `json:"fingerprint"`  // 提交者指纹
+	IP           string   `json:"ip"`           // 提交者IP
+	Score        int      `json:"score"`        // 可疑分数
+	Reasons      []string `json:"reasons"`      // 命中的规则
+	Status       uint8    `json:"status"`       // 状态，0表示待审核，1表示已通过，2表示已拒绝
+	ReviewerID   int      `json:"reviewerID"`   // 审核人ID
+	ReviewedTime int64    `json:"reviewedTime"` // 审核时间
+	CreatedTime  int64    `json:"createdTime"`  // 提交时间
+}
+
+type ModerationListVo struct {
+	ModerationList []ModerationVo `json:"moderationList"`
+	Pageinate      dto.Pageinate  `json:"pageinate"`
+}
@@ -24,6 +24,14 @@ func UnauthorizedFail(c *gin.Context, msg ...string) {
 	ResponseJson(c, http.StatusUnauthorized, result.Fail(c, cerr.New(cerr.UNAUTHORIZED, msg...)))
 }
 
+func ForbiddenFail(c *gin.Context, msg ...string) {
+	ResponseJson(c, http.StatusForbidden, result.Fail(c, cerr.New(cerr.FORBIDDEN, msg...)))
+}
+
+func PasswordResetRequiredFail(c *gin.Context) {
+	ResponseJson(c, http.StatusForbidden, result.Fail(c, cerr.New(cerr.ERROR_USER_PASSWORD_RESET_REQUIRED)))
+}
+
 func TokenExpire(c *gin.Context) {
 	ResponseJson(c, http.StatusUnauthorized, result.Fail(c, cerr.New(cerr.ERROR_USER_TOKEN_EXPIRE)))
 }
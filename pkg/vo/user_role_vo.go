@@ -0,0 +1,6 @@
+package vo
+
+type UserRoleVo struct {
+	UserID int     `json:"user_id"`
+	Roles  []int32 `json:"roles"` // 用户拥有的角色
+}
@@ -1,6 +1,89 @@
 package vo
 
+import "github.com/narcissus1949/narcissus-blog/pkg/dto"
+
 type LoginVo struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	PasswordResetRequired bool `json:"password_reset_required,omitempty"` // 管理员已强制重置密码，需修改密码后继续使用
+
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"` // 已开启两步验证，需使用预认证token提交动态验证码
+	PreAuthToken      string `json:"pre_auth_token,omitempty"`      // 预认证token，只能用于提交动态验证码
+}
+
+type TotpStatusVo struct {
+	Enabled                bool  `json:"enabled"`                  // 是否已开启两步验证
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"` // 剩余可用的恢复码数量
+}
+
+type TotpEnrollVo struct {
+	Secret     string `json:"secret"`      // base32编码的密钥，供手动输入
+	OtpauthURI string `json:"otpauth_uri"` // otpauth://totp/... 供认证器应用导入
+	QRCode     string `json:"qr_code"`     // otpauth URI二维码PNG图片的data URI
+}
+
+type TotpRecoveryCodesVo struct {
+	RecoveryCodes []string `json:"recovery_codes"` // 恢复码只返回一次，每个只能使用一次
+}
+
+type SessionVo struct {
+	ID             string `json:"id"`
+	Device         string `json:"device"` // 由User-Agent解析的浏览器和操作系统
+	UserAgent      string `json:"user_agent"`
+	IP             string `json:"ip"`               // 最近一次刷新token的IP
+	CreatedTime    int64  `json:"created_time"`     // 登录时间
+	LastActiveTime int64  `json:"last_active_time"` // 最近一次刷新token的时间
+	Current        bool   `json:"current"`          // 是否为当前请求所属会话
+}
+
+type OidcProviderVo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+type OidcAuthorizeVo struct {
+	AuthorizationURL string `json:"authorization_url"` // 跳转到身份提供方的授权地址
+}
+
+type UserIdentityVo struct {
+	Provider      string `json:"provider"`
+	Email         string `json:"email"`
+	CreatedTime   int64  `json:"created_time"`
+	LastLoginTime int64  `json:"last_login_time"` // 从未通过该身份登录时为0
+}
+
+type AccessTokenVo struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	TokenPrefix  string   `json:"token_prefix"` // token前几位，用于识别
+	Scopes       []string `json:"scopes"`
+	ExpireTime   int64    `json:"expire_time"`    // 永不过期时为0
+	LastUsedTime int64    `json:"last_used_time"` // 从未使用时为0
+	LastUsedIP   string   `json:"last_used_ip"`
+	CreatedTime  int64    `json:"created_time"`
+}
+
+type AccessTokenCreateVo struct {
+	AccessTokenVo
+	Token string `json:"token"` // 完整token只在创建时返回一次
+}
+
+type UserVo struct {
+	ID                    int     `json:"id"`
+	Username              string  `json:"username"`
+	Nickname              string  `json:"nickname"`
+	Email                 string  `json:"email"`
+	PhoneNumber           string  `json:"phoneNumber"`
+	AvatarPath            string  `json:"avatarPath"`
+	Status                uint8   `json:"status"`                // 状态，0表示待审核，1表示正常，2表示已拒绝，3表示已禁用
+	PasswordResetRequired bool    `json:"passwordResetRequired"` // 是否需要重置密码
+	Roles                 []int32 `json:"roles"`                 // 用户拥有的角色
+	CreatedAt             int64   `json:"createdAt"`
+	UpdatedAt             int64   `json:"updatedAt"`
+}
+
+type UserListVo struct {
+	UserList  []UserVo      `json:"userList"`
+	Pageinate dto.Pageinate `json:"pageinate"`
 }